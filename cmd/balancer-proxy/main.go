@@ -25,6 +25,7 @@ func main() {
 		ComplexityMedium: cfg.MinOverlapPct,
 	}
 	proxy := httpDelivery.NewBalancerProxy(entityConfig)
+	proxy.SetSharedStore(cfg.ChallengeStore == config.ChallengeStoreRedis)
 
 	if err := proxy.ConnectToBalancer(cfg.BalancerAddress); err != nil {
 		log.Fatalf("Failed to connect to balancer: %v", err)
@@ -13,8 +13,11 @@ import (
 	"captcha-service/internal/config"
 	"captcha-service/internal/domain/entity"
 	"captcha-service/internal/infrastructure/cache"
+	"captcha-service/internal/infrastructure/event_processing"
+	"captcha-service/internal/infrastructure/imaging"
 	"captcha-service/internal/infrastructure/persistence"
 	"captcha-service/internal/infrastructure/repository"
+	templateInfra "captcha-service/internal/infrastructure/template"
 	"captcha-service/internal/service"
 	httpTransport "captcha-service/internal/transport/http"
 	wsTransport "captcha-service/internal/transport/websocket"
@@ -40,10 +43,25 @@ func main() {
 	challengeRepo := persistence.NewMemoryOptimizedRepository(cfg.MaxChallenges)
 
 	registry := service.NewGeneratorRegistry()
-	sliderGenerator := service.NewSliderPuzzleGenerator(entityConfig, challengeRepo, nil)
+	renderer, err := imaging.NewRenderer(entityConfig.BackgroundsPath, entity.CanvasWidth, entity.CanvasHeight, imaging.BackgroundOptions{
+		Mode:       entityConfig.BackgroundMode,
+		PhotoBlend: entityConfig.BackgroundPhotoBlend,
+	})
+	if err != nil {
+		log.Fatalf("Failed to load backgrounds: %v", err)
+	}
+	templateEngine := templateInfra.NewTemplateEngineService("./templates")
+
+	difficulty, err := service.LoadDifficultyProfiles(entityConfig.DifficultyProfilesPath)
+	if err != nil {
+		log.Fatalf("Failed to load difficulty profiles: %v", err)
+	}
+
+	sliderGenerator := service.NewSliderPuzzleGenerator(entityConfig, difficulty, challengeRepo, templateEngine, renderer)
 	registry.Register(entity.ChallengeTypeSliderPuzzle, sliderGenerator)
 
-	captchaService := service.NewCaptchaService(challengeRepo, registry, entityConfig)
+	captchaService := service.NewCaptchaService(challengeRepo, registry, entityConfig, event_processing.NewEventProcessorService(entityConfig))
+	captchaService.SetClientRuntime(templateEngine)
 
 	tmpl := template.New("demo")
 
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+	"text/template"
+
+	"captcha-service/internal/domain/entity"
+)
+
+// Writes the browser side of the event frame codec from the constants in
+// internal/domain/entity/event_frame.go. Run through go generate.
+func main() {
+	out := flag.String("o", "templates/event_codec.js", "output file")
+	flag.Parse()
+
+	code, err := generate()
+	if err != nil {
+		log.Fatalf("Failed to generate event codec: %v", err)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatalf("Failed to write event codec: %v", err)
+	}
+}
+
+type eventType struct {
+	Name  string
+	Value entity.BinaryEventType
+}
+
+func generate() ([]byte, error) {
+	data := map[string]interface{}{
+		"Version":       entity.EventFrameVersion,
+		"MaxEvents":     entity.MaxFrameEvents,
+		"MaxCoordinate": entity.MaxEventCoordinate,
+		"MaxType":       int(entity.EventTypeDragMoved),
+		"Types": []eventType{
+			{"SLIDER_MOVED", entity.EventTypeSliderMoved},
+			{"CLICK", entity.EventTypeClick},
+			{"DRAG_START", entity.EventTypeDragStart},
+			{"DRAG_END", entity.EventTypeDragEnd},
+			{"INTERACTION_STARTED", entity.EventTypeInteractionStarted},
+			{"CHALLENGE_COMPLETED", entity.EventTypeChallengeCompleted},
+			{"CHALLENGE_FAILED", entity.EventTypeChallengeFailed},
+			{"DRAG_MOVED", entity.EventTypeDragMoved},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := codecTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// The output is pasted into <script> blocks of challenge pages as is, so it
+// avoids template literals and anything else that would need escaping.
+var codecTemplate = template.Must(template.New("event_codec.js").Parse(`// Code generated by cmd/eventcodec-js from internal/domain/entity/event_frame.go. DO NOT EDIT.
+
+// CaptchaEvents packs pointer samples into event frames, version {{.Version}}:
+// version byte, uvarint count, then per event a type byte, the uvarint
+// milliseconds since the previous event (Unix ms for the first) and the zigzag
+// varint change of x and y (absolute for the first).
+var CaptchaEvents = (function () {
+    var VERSION = {{.Version}};
+    var MAX_EVENTS = {{.MaxEvents}};
+    var MAX_COORDINATE = {{.MaxCoordinate}};
+    var TYPES = {
+{{- range $i, $t := .Types}}{{if $i}},{{end}}
+        {{$t.Name}}: {{printf "%d" $t.Value}}
+{{- end}}
+    };
+    var MAX_TYPE = {{.MaxType}};
+
+    // Numbers stay exact up to 2^53, so varints are built with arithmetic
+    // rather than 32-bit shifts.
+    function pushUvarint(out, value) {
+        while (value >= 0x80) {
+            out.push((value % 0x80) | 0x80);
+            value = Math.floor(value / 0x80);
+        }
+        out.push(value);
+    }
+
+    function pushVarint(out, value) {
+        pushUvarint(out, value >= 0 ? value * 2 : -value * 2 - 1);
+    }
+
+    function validEvent(event) {
+        return Number.isInteger(event.type) && event.type >= 0 && event.type <= MAX_TYPE &&
+            Number.isInteger(event.x) && event.x >= 0 && event.x <= MAX_COORDINATE &&
+            Number.isInteger(event.y) && event.y >= 0 && event.y <= MAX_COORDINATE &&
+            Number.isSafeInteger(event.timestamp) && event.timestamp >= 0;
+    }
+
+    // encode returns the frame as a Uint8Array, or null if an event is out of
+    // range or older than the one before it.
+    function encode(events) {
+        if (!events.length || events.length > MAX_EVENTS) return null;
+
+        var out = [VERSION];
+        pushUvarint(out, events.length);
+
+        var previous = { x: 0, y: 0, timestamp: 0 };
+        for (var i = 0; i < events.length; i++) {
+            var event = events[i];
+            if (!validEvent(event) || event.timestamp < previous.timestamp) return null;
+
+            out.push(event.type);
+            pushUvarint(out, event.timestamp - previous.timestamp);
+            pushVarint(out, event.x - previous.x);
+            pushVarint(out, event.y - previous.y);
+            previous = event;
+        }
+        return new Uint8Array(out);
+    }
+
+    // decode is the inverse of encode; it returns null for a malformed frame.
+    function decode(bytes) {
+        var offset = 0;
+
+        function readUvarint() {
+            var value = 0;
+            var scale = 1;
+            for (var i = 0; i < 8 && offset < bytes.length; i++) {
+                var b = bytes[offset++];
+                value += (b & 0x7f) * scale;
+                if (b < 0x80) return value;
+                scale *= 0x80;
+            }
+            return -1;
+        }
+
+        function readVarint() {
+            var value = readUvarint();
+            if (value < 0) return null;
+            return value % 2 === 0 ? value / 2 : -(value + 1) / 2;
+        }
+
+        if (bytes[offset++] !== VERSION) return null;
+        var count = readUvarint();
+        if (count < 1 || count > MAX_EVENTS) return null;
+
+        var events = [];
+        var previous = { x: 0, y: 0, timestamp: 0 };
+        for (var i = 0; i < count; i++) {
+            if (offset >= bytes.length) return null;
+            var type = bytes[offset++];
+            var dt = readUvarint();
+            var dx = readVarint();
+            var dy = readVarint();
+            if (dt < 0 || dx === null || dy === null) return null;
+
+            var event = {
+                type: type,
+                x: previous.x + dx,
+                y: previous.y + dy,
+                timestamp: previous.timestamp + dt
+            };
+            if (!validEvent(event)) return null;
+            events.push(event);
+            previous = event;
+        }
+        return offset === bytes.length ? events : null;
+    }
+
+    // batch collects samples and hands them to send as one frame every
+    // intervalMs, or as soon as a frame is full. Call flush before sending
+    // anything that depends on the samples, such as an answer.
+    function batch(send, intervalMs) {
+        var pending = [];
+        var lastTimestamp = 0;
+        var timer = null;
+
+        function flush() {
+            if (timer !== null) {
+                clearTimeout(timer);
+                timer = null;
+            }
+            if (!pending.length) return;
+
+            var frame = encode(pending);
+            pending = [];
+            if (frame) send(frame);
+        }
+
+        function push(type, x, y, timestamp) {
+            var event = {
+                type: type,
+                x: Math.round(x),
+                y: Math.round(y),
+                // The clock may step back; frames must not.
+                timestamp: Math.max(Math.round(timestamp === undefined ? Date.now() : timestamp), lastTimestamp)
+            };
+            if (!validEvent(event)) return false;
+
+            lastTimestamp = event.timestamp;
+            pending.push(event);
+            if (pending.length >= MAX_EVENTS) {
+                flush();
+            } else if (timer === null) {
+                timer = setTimeout(flush, intervalMs === undefined ? 100 : intervalMs);
+            }
+            return true;
+        }
+
+        return { push: push, flush: flush };
+    }
+
+    return { VERSION: VERSION, TYPES: TYPES, MAX_EVENTS: MAX_EVENTS, encode: encode, decode: decode, batch: batch };
+})();
+`))
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedCodecUpToDate(t *testing.T) {
+	code, err := generate()
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../../templates/event_codec.js")
+	require.NoError(t, err)
+	assert.Equal(t, string(code), string(committed), "run go generate ./internal/domain/entity")
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pluginProto "captcha-service/gen/proto/plugin"
+	"captcha-service/internal/config"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	challengeTypeArithmetic = "arithmetic"
+	eventTypeRefresh        = "arithmetic_refresh"
+
+	pluginName    = "arithmetic"
+	pluginVersion = "1.0.0"
+
+	canvasWidth  = 260
+	canvasHeight = 80
+
+	// Multiplication is only asked from this complexity on.
+	multiplyFrom = 60
+)
+
+var texts = map[string]map[string]string{
+	"ru": {
+		"Title":   "Решите пример",
+		"Hint":    "Введите результат выражения на картинке.",
+		"Submit":  "Проверить",
+		"Refresh": "Другой пример",
+		"Success": "Успешно! Капча пройдена.",
+		"Retry":   "Неверно. Попробуйте ещё раз.",
+	},
+	"en": {
+		"Title":   "Solve the expression",
+		"Hint":    "Enter the result of the expression in the picture.",
+		"Submit":  "Check",
+		"Refresh": "Another one",
+		"Success": "Success! Captcha passed.",
+		"Retry":   "Incorrect. Please try again.",
+	},
+}
+
+// arithmeticState is what the captcha service stores for the plugin.
+type arithmeticState struct {
+	Answer     int    `json:"answer"`
+	Complexity int32  `json:"complexity"`
+	Locale     string `json:"locale"`
+}
+
+// arithmeticPlugin is the reference GeneratorPlugin: it draws a small
+// expression as a distorted SVG and expects its result to be typed in.
+type arithmeticPlugin struct {
+	pluginProto.UnimplementedGeneratorPluginServer
+
+	config *config.PluginConfig
+	page   *template.Template
+	rand   *rand.Rand
+	mu     sync.Mutex
+}
+
+func newArithmeticPlugin(cfg *config.PluginConfig) *arithmeticPlugin {
+	return &arithmeticPlugin{
+		config: cfg,
+		page:   template.Must(template.New("arithmetic").Parse(pageTemplate)),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (p *arithmeticPlugin) Describe(ctx context.Context, req *pluginProto.DescribeRequest) (*pluginProto.DescribeResponse, error) {
+	return &pluginProto.DescribeResponse{
+		Name:           pluginName,
+		Version:        pluginVersion,
+		ChallengeTypes: []string{challengeTypeArithmetic},
+	}, nil
+}
+
+func (p *arithmeticPlugin) Generate(ctx context.Context, req *pluginProto.GenerateRequest) (*pluginProto.GenerateResponse, error) {
+	if req.ChallengeType != challengeTypeArithmetic {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported challenge type %q", req.ChallengeType)
+	}
+
+	locale := req.Locale
+	if _, ok := texts[locale]; !ok {
+		locale = "ru"
+	}
+
+	rng := p.newRand()
+	expression, answer := randomExpression(rng, req.Complexity)
+
+	var html bytes.Buffer
+	err := p.page.Execute(&html, map[string]interface{}{
+		"Lang":        locale,
+		"Text":        texts[locale],
+		"ChallengeID": req.ChallengeId,
+		"UserID":      req.UserId,
+		"Width":       canvasWidth,
+		"Height":      canvasHeight,
+		"Picture":     template.HTML(renderExpression(rng, expression, req.Complexity)),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render arithmetic page: %v", err)
+	}
+
+	state, err := json.Marshal(arithmeticState{Answer: answer, Complexity: req.Complexity, Locale: locale})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode state: %v", err)
+	}
+
+	return &pluginProto.GenerateResponse{
+		Html:          html.String(),
+		State:         state,
+		ExpirationSec: p.config.ExpirationSec,
+		MinTimeMs:     p.config.MinTimeMs,
+		MaxTimeMs:     p.config.MaxTimeMs,
+	}, nil
+}
+
+// Validate compares the typed value with the stored result. Confidence drops
+// for answers typed faster than MinTimeMs or later than MaxTimeMs.
+func (p *arithmeticPlugin) Validate(ctx context.Context, req *pluginProto.ValidateRequest) (*pluginProto.ValidateResponse, error) {
+	var state arithmeticState
+	if err := json.Unmarshal(req.State, &state); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "неверный формат данных челленджа")
+	}
+
+	var answer map[string]interface{}
+	if err := json.Unmarshal(req.Answer, &answer); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "неверный формат ответа")
+	}
+
+	value, ok := answerValue(answer["value"])
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "неверный формат ответа")
+	}
+	if value != state.Answer {
+		return &pluginProto.ValidateResponse{Valid: false}, nil
+	}
+
+	timing := 1.0
+	switch elapsed := req.ElapsedMs; {
+	case elapsed <= 0:
+		timing = 0
+	case elapsed < p.config.MinTimeMs:
+		timing = float64(elapsed) / float64(p.config.MinTimeMs)
+	case elapsed > p.config.MaxTimeMs:
+		timing = float64(p.config.MaxTimeMs) / float64(elapsed)
+	}
+
+	return &pluginProto.ValidateResponse{
+		Valid:      true,
+		Confidence: int32(40 + 60*timing),
+	}, nil
+}
+
+// HandleEvent swaps the expression for a new one of the same complexity when
+// the user asks for another.
+func (p *arithmeticPlugin) HandleEvent(ctx context.Context, req *pluginProto.HandleEventRequest) (*pluginProto.HandleEventResponse, error) {
+	if req.EventType != eventTypeRefresh {
+		return nil, status.Errorf(codes.Unimplemented, "unknown event %q", req.EventType)
+	}
+
+	var state arithmeticState
+	if err := json.Unmarshal(req.State, &state); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "неверный формат данных челленджа")
+	}
+
+	rng := p.newRand()
+	expression, answer := randomExpression(rng, state.Complexity)
+	state.Answer = answer
+
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode state: %v", err)
+	}
+
+	reply, err := json.Marshal(map[string]string{
+		"type":    eventTypeRefresh,
+		"picture": renderExpression(rng, expression, state.Complexity),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode reply: %v", err)
+	}
+
+	return &pluginProto.HandleEventResponse{Reply: reply, State: encoded}, nil
+}
+
+func (p *arithmeticPlugin) newRand() *rand.Rand {
+	p.mu.Lock()
+	seed := p.rand.Int63()
+	p.mu.Unlock()
+	return rand.New(rand.NewSource(seed))
+}
+
+func answerValue(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), v == float64(int(v))
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// randomExpression picks operands that grow with complexity; subtraction never
+// goes below zero.
+func randomExpression(rng *rand.Rand, complexity int32) (string, int) {
+	limit := 9 + int(complexity)*40/100
+	a, b := 1+rng.Intn(limit), 1+rng.Intn(limit)
+
+	ops := 2
+	if complexity >= multiplyFrom {
+		ops = 3
+	}
+
+	switch rng.Intn(ops) {
+	case 0:
+		return fmt.Sprintf("%d+%d", a, b), a + b
+	case 1:
+		if a < b {
+			a, b = b, a
+		}
+		return fmt.Sprintf("%d−%d", a, b), a - b
+	default:
+		b = 2 + rng.Intn(8)
+		return fmt.Sprintf("%d×%d", a, b), a * b
+	}
+}
+
+// renderExpression draws every character on its own slant and offset and
+// crosses the picture with noise strokes, more of them at higher complexity.
+func renderExpression(rng *rand.Rand, expression string, complexity int32) string {
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img">`,
+		canvasWidth, canvasHeight, canvasWidth, canvasHeight)
+	fmt.Fprintf(&svg, `<rect width="100%%" height="100%%" fill="hsl(%d,40%%,92%%)"/>`, rng.Intn(360))
+
+	chars := []rune(expression + "=?")
+	step := (canvasWidth - 40) / len(chars)
+	slant := 10 + float64(complexity)*0.25
+
+	for i, char := range chars {
+		x := 20 + i*step + rng.Intn(7) - 3
+		y := 52 + rng.Intn(13) - 6
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" font-family="monospace" font-size="%d" font-weight="bold" fill="hsl(%d,60%%,30%%)" transform="rotate(%.1f %d %d)">%c</text>`,
+			x, y, 30+rng.Intn(9), rng.Intn(360), (rng.Float64()*2-1)*slant, x, y, char)
+	}
+
+	for n := 2 + int(complexity)/20; n > 0; n-- {
+		fmt.Fprintf(&svg, `<path d="M%d %d Q%d %d %d %d" stroke="hsl(%d,50%%,40%%)" stroke-width="%.1f" fill="none" opacity="0.7"/>`,
+			rng.Intn(canvasWidth/3), rng.Intn(canvasHeight),
+			rng.Intn(canvasWidth), rng.Intn(canvasHeight),
+			canvasWidth-rng.Intn(canvasWidth/3), rng.Intn(canvasHeight),
+			rng.Intn(360), 1+rng.Float64()*1.5)
+	}
+
+	svg.WriteString(`</svg>`)
+	return svg.String()
+}
+
+const pageTemplate = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+  <meta charset="UTF-8" />
+  <meta name="viewport" content="width=device-width,initial-scale=1" />
+  <title>{{.Text.Title}}</title>
+  <style>
+    * { box-sizing: border-box; }
+    body { margin: 0; font-family: system-ui, -apple-system, Arial, sans-serif; background: #f5f5f5; color:#222; }
+    .wrap { max-width: 480px; margin: 24px auto; background: #fff; border-radius: 10px; padding: 16px; box-shadow: 0 6px 20px rgba(0,0,0,.08); }
+    h1 { font-size: 18px; margin: 0 0 10px; text-align:center; }
+    #picture { display:flex; justify-content:center; margin: 12px 0; }
+    label { display:block; font-size: 14px; color:#444; margin-bottom: 8px; }
+    .row { display:flex; gap: 8px; }
+    input[type="text"] { flex: 1; font-size: 20px; padding: 8px 10px; border: 1px solid #ccc; border-radius: 6px; }
+    button { padding: 8px 16px; font-size: 15px; border: 0; border-radius: 6px; background: #1976d2; color: #fff; cursor: pointer; }
+    button.secondary { background: #eceff1; color: #333; }
+    button:disabled { background: #9e9e9e; cursor: default; }
+    .msg { text-align:center; margin:10px 0 0; font-weight:600; min-height: 1.2em; }
+    .ok { color:#1b5e20; }
+    .bad { color:#b00020; }
+  </style>
+</head>
+<body>
+  <main class="wrap">
+    <h1>{{.Text.Title}}</h1>
+    <div id="picture" style="width:{{.Width}}px;height:{{.Height}}px;margin:12px auto">{{.Picture}}</div>
+    <form id="form" autocomplete="off">
+      <label id="hint" for="value">{{.Text.Hint}}</label>
+      <div class="row">
+        <input id="value" type="text" inputmode="numeric" required aria-describedby="hint" />
+        <button id="submit" type="submit">{{.Text.Submit}}</button>
+        <button id="refresh" type="button" class="secondary">{{.Text.Refresh}}</button>
+      </div>
+    </form>
+    <div id="msg" class="msg" role="status" aria-live="polite"></div>
+  </main>
+
+  <script>
+    const challengeData = {
+      challenge_id: "{{.ChallengeID}}",
+      user_id: "{{.UserID}}"
+    };
+
+    const form = document.getElementById("form");
+    const input = document.getElementById("value");
+    const button = document.getElementById("submit");
+    const picture = document.getElementById("picture");
+    const msg = document.getElementById("msg");
+
+    // The captcha service injects its client runtime into the page.
+    const captcha = CaptchaRuntime.start({
+      challengeId: challengeData.challenge_id,
+      userId: challengeData.user_id
+    });
+
+    function setMsg(text, kind) {
+      msg.textContent = text || "";
+      msg.className = "msg" + (kind ? " " + kind : "");
+    }
+
+    captcha.onResult((valid) => {
+      if (valid) {
+        setMsg("{{.Text.Success}}", "ok");
+        input.disabled = true;
+        button.disabled = true;
+      } else {
+        setMsg("{{.Text.Retry}}", "bad");
+        input.value = "";
+        button.disabled = false;
+        input.focus();
+      }
+    });
+
+    captcha.onData((data) => {
+      if (data.type === 'arithmetic_refresh' && data.picture) {
+        picture.innerHTML = data.picture;
+        input.value = "";
+        input.focus();
+      }
+    });
+
+    form.addEventListener("submit", (e) => {
+      e.preventDefault();
+      if (input.value.trim() === "") return;
+
+      button.disabled = true;
+      setMsg("");
+      captcha.validate({ value: input.value.trim() });
+    });
+
+    document.getElementById("refresh").addEventListener("click", () => {
+      setMsg("");
+      captcha.send('arithmetic_refresh', {});
+    });
+
+    input.focus();
+  </script>
+</body>
+</html>
+`
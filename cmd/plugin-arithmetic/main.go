@@ -0,0 +1,62 @@
+package main
+
+import (
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	pluginProto "captcha-service/gen/proto/plugin"
+	"captcha-service/internal/config"
+	"captcha-service/pkg/logger"
+
+	"go.uber.org/zap"
+	grpcLib "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// The reference out-of-process generator. Point the captcha service at it
+// with PLUGIN_ADDRS=host:port to serve the "arithmetic" challenge type.
+func main() {
+	cfg, err := config.LoadPluginConfig()
+	if err != nil {
+		log.Fatalf("Failed to load plugin config: %v", err)
+	}
+
+	if err := logger.Init(cfg.LogLevel); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	lis, err := net.Listen("tcp", net.JoinHostPort(cfg.Host, cfg.Port))
+	if err != nil {
+		logger.Fatal("Failed to listen", zap.Error(err))
+	}
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	grpcServer := grpcLib.NewServer()
+	pluginProto.RegisterGeneratorPluginServer(grpcServer, newArithmeticPlugin(cfg))
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Fatal("Failed to serve gRPC", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Arithmetic plugin started",
+		zap.String("address", lis.Addr().String()),
+		zap.String("version", pluginVersion))
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	logger.Info("Shutting down arithmetic plugin...")
+	healthServer.Shutdown()
+	grpcServer.GracefulStop()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	pluginProto "captcha-service/gen/proto/plugin"
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// slowPlugin answers Generate only after delay, whatever the deadline.
+type slowPlugin struct {
+	*arithmeticPlugin
+	delay time.Duration
+}
+
+func (p *slowPlugin) Generate(ctx context.Context, req *pluginProto.GenerateRequest) (*pluginProto.GenerateResponse, error) {
+	time.Sleep(p.delay)
+	return p.arithmeticPlugin.Generate(ctx, req)
+}
+
+type testPlugin struct {
+	server   *grpc.Server
+	health   *health.Server
+	registry *service.GeneratorRegistry
+}
+
+// startPlugin serves plugin in process and registers it with a fresh plugin
+// host, as PLUGIN_ADDRS would.
+func startPlugin(t *testing.T, plugin pluginProto.GeneratorPluginServer) *testPlugin {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	server := grpc.NewServer()
+	pluginProto.RegisterGeneratorPluginServer(server, plugin)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	registry := service.NewGeneratorRegistry()
+	host := service.NewPluginHost(&config.CaptchaConfig{
+		PluginTimeoutMs:         200,
+		PluginHealthIntervalSec: 1,
+		ComplexityMedium:        50,
+		MaxAttempts:             3,
+	}, registry)
+	host.SetDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}))
+	require.NoError(t, host.Add("passthrough:///arithmetic"))
+	t.Cleanup(host.Stop)
+
+	require.Eventually(t, func() bool {
+		_, ok := registry.Get(challengeTypeArithmetic)
+		return ok
+	}, 2*time.Second, 10*time.Millisecond, "the plugin registers its challenge type")
+
+	return &testPlugin{server: server, health: healthServer, registry: registry}
+}
+
+func newPlugin() *arithmeticPlugin {
+	return newArithmeticPlugin(&config.PluginConfig{ExpirationSec: 180, MinTimeMs: 1500, MaxTimeMs: 30000})
+}
+
+func (p *testPlugin) generator(t *testing.T) service.ChallengeGenerator {
+	t.Helper()
+	generator, ok := p.registry.Get(challengeTypeArithmetic)
+	require.True(t, ok)
+	return generator
+}
+
+func storedAnswer(t *testing.T, challenge *entity.Challenge) int {
+	t.Helper()
+	data, ok := challenge.Data.(entity.PluginData)
+	require.True(t, ok)
+
+	var state arithmeticState
+	require.NoError(t, json.Unmarshal(data.State, &state))
+	return state.Answer
+}
+
+func TestPluginHostServesPluginChallenges(t *testing.T) {
+	plugin := startPlugin(t, newPlugin())
+	generator := plugin.generator(t)
+
+	challenge, err := generator.Generate(context.Background(), 30, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, challengeTypeArithmetic, challenge.Type)
+	assert.Equal(t, int32(30), challenge.Complexity)
+	assert.Contains(t, challenge.HTML, challenge.ID)
+	assert.Equal(t, 180*time.Second, challenge.ExpiresAt.Sub(challenge.CreatedAt))
+
+	valid, _, err := generator.Validate(map[string]interface{}{"value": storedAnswer(t, challenge) + 1}, challenge)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, confidence, err := generator.Validate(map[string]interface{}{"value": storedAnswer(t, challenge)}, challenge)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Positive(t, confidence)
+
+	_, _, err = generator.Validate(map[string]interface{}{"value": "many"}, challenge)
+	assert.EqualError(t, err, "неверный формат ответа", "InvalidArgument from the plugin is a malformed answer")
+}
+
+func TestPluginHostDropsUnhealthyPlugin(t *testing.T) {
+	plugin := startPlugin(t, newPlugin())
+	generator := plugin.generator(t)
+
+	plugin.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	require.Eventually(t, func() bool {
+		_, err := generator.Generate(context.Background(), 50, "user-1")
+		return err == entity.ErrGeneratorUnavailable
+	}, 3*time.Second, 50*time.Millisecond, "an unhealthy plugin stops serving challenges")
+
+	plugin.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	require.Eventually(t, func() bool {
+		_, err := generator.Generate(context.Background(), 50, "user-1")
+		return err == nil
+	}, 3*time.Second, 50*time.Millisecond, "a recovered plugin serves challenges again")
+}
+
+func TestPluginHostTimesOutSlowPlugin(t *testing.T) {
+	plugin := startPlugin(t, &slowPlugin{arithmeticPlugin: newPlugin(), delay: time.Second})
+
+	started := time.Now()
+	_, err := plugin.generator(t).Generate(context.Background(), 50, "user-1")
+	assert.ErrorIs(t, err, entity.ErrGeneratorUnavailable)
+	assert.Less(t, time.Since(started), time.Second, "PLUGIN_TIMEOUT_MS bounds the call")
+}
+
+func TestPluginHostPluginGoneMidChallenge(t *testing.T) {
+	plugin := startPlugin(t, newPlugin())
+	generator := plugin.generator(t)
+
+	challenge, err := generator.Generate(context.Background(), 50, "user-1")
+	require.NoError(t, err)
+
+	plugin.server.Stop()
+
+	_, _, err = generator.Validate(map[string]interface{}{"value": storedAnswer(t, challenge)}, challenge)
+	assert.ErrorIs(t, err, entity.ErrGeneratorUnavailable)
+
+	events, ok := generator.(service.EventHandlingGenerator)
+	require.True(t, ok)
+	_, err = events.HandleEvent(context.Background(), challenge, eventTypeRefresh, nil)
+	assert.ErrorIs(t, err, entity.ErrGeneratorUnavailable)
+}
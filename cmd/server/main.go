@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"log"
 	"os"
 	"os/signal"
@@ -11,10 +12,14 @@ import (
 
 	"captcha-service/internal/config"
 	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/audio"
 	"captcha-service/internal/infrastructure/balancer"
 	"captcha-service/internal/infrastructure/cache"
+	"captcha-service/internal/infrastructure/event_processing"
+	"captcha-service/internal/infrastructure/imaging"
 	"captcha-service/internal/infrastructure/persistence"
 	"captcha-service/internal/infrastructure/port"
+	"captcha-service/internal/infrastructure/redis"
 	"captcha-service/internal/infrastructure/template"
 	"captcha-service/internal/service"
 	"captcha-service/internal/transport/grpc"
@@ -36,14 +41,84 @@ func main() {
 	}
 	defer logger.Get().Sync()
 
-	repo := persistence.NewMemoryOptimizedRepository(int(cfg.MaxChallenges))
+	for name, replacement := range config.RemovedSettings() {
+		logger.Warn("Setting is no longer read", zap.String("setting", name), zap.String("replacedBy", replacement))
+	}
+
+	var redisClient *redis.Client
+	if cfg.ChallengeStore == config.ChallengeStoreRedis {
+		redisClient = newRedisClient(cfg)
+	}
+	repo := newChallengeStore(cfg, redisClient)
 
 	templateEngine := template.NewTemplateEngineService("./templates")
 
+	difficulty, err := service.LoadDifficultyProfiles(cfg.DifficultyProfilesPath)
+	if err != nil {
+		logger.Fatal("Failed to load difficulty profiles", zap.Error(err))
+	}
+
 	registry := service.NewGeneratorRegistry()
-	registry.Register(entity.ChallengeTypeSliderPuzzle, service.NewSliderPuzzleGenerator(cfg, repo, templateEngine))
+	var renderer *imaging.Renderer
+	switch cfg.ChallengeType {
+	case entity.ChallengeTypeSliderPuzzle:
+		renderer = newRenderer(cfg)
+		registry.Register(entity.ChallengeTypeSliderPuzzle, service.NewSliderPuzzleGenerator(cfg, difficulty, repo, templateEngine, renderer))
+	case entity.ChallengeTypeDragDrop:
+		registry.Register(entity.ChallengeTypeDragDrop, service.NewDragDropGenerator(cfg, difficulty, repo, templateEngine))
+	case entity.ChallengeTypeSteerGame:
+		registry.Register(entity.ChallengeTypeSteerGame, service.NewSteerGameGenerator(cfg, difficulty, repo, templateEngine))
+	case entity.ChallengeTypeRotation:
+		renderer = newRenderer(cfg)
+		registry.Register(entity.ChallengeTypeRotation, service.NewRotationGenerator(cfg, difficulty, repo, templateEngine, renderer))
+	case entity.ChallengeTypeClickOrder:
+		renderer = newRenderer(cfg)
+		registry.Register(entity.ChallengeTypeClickOrder, service.NewClickOrderGenerator(cfg, difficulty, repo, templateEngine, renderer))
+	case entity.ChallengeTypeAudio:
+	default:
+		if len(cfg.PluginAddrs) == 0 {
+			logger.Fatal("Unsupported challenge type", zap.String("challenge_type", cfg.ChallengeType))
+		}
+		logger.Info("Challenge type expected from a plugin", zap.String("challenge_type", cfg.ChallengeType))
+	}
+
+	// Every instance also serves the audio alternative to its own challenge.
+	registry.Register(entity.ChallengeTypeAudio, service.NewAudioGenerator(cfg, difficulty, repo, templateEngine, audio.NewSynthesizer(audio.DefaultSampleRate)))
+
+	if cfg.DebugSeeds {
+		registry.SetRecordSeeds(true)
+		logger.Warn("Debug seeds enabled: challenge seeds are recorded and can be replayed")
+	}
 
-	captchaService := service.NewCaptchaService(repo, registry, cfg)
+	var plugins *service.PluginHost
+	if len(cfg.PluginAddrs) > 0 {
+		plugins = service.NewPluginHost(cfg, registry)
+		for _, address := range cfg.PluginAddrs {
+			if err := plugins.Add(address); err != nil {
+				logger.Fatal("Failed to add plugin", zap.String("address", address), zap.Error(err))
+			}
+		}
+	}
+
+	// The balancer client is connected once the port is known, but its
+	// instance ID goes into every challenge ID from the start.
+	balancerClient := balancer.NewClient(cfg)
+	registry.SetIDPrefix(balancerClient.InstanceID())
+
+	var pool *service.ChallengePool
+	if cfg.PoolSize > 0 {
+		pool = service.NewChallengePool(cfg)
+		pool.Attach(registry)
+		logger.Info("Challenge pool enabled",
+			zap.Int32("size", cfg.PoolSize),
+			zap.Int32("bucket_width", cfg.PoolBucketWidth))
+	}
+
+	eventProcessor := event_processing.NewEventProcessorService(cfg)
+
+	captchaService := service.NewCaptchaService(repo, registry, cfg, eventProcessor)
+	captchaService.SetClientRuntime(templateEngine)
+	captchaService.SetTokenIssuer(newTokenIssuer(cfg, redisClient))
 
 	// Используем порт из конфигурации, если задан
 	var availablePort int
@@ -65,7 +140,6 @@ func main() {
 		logger.Info("Found available port", zap.Int("port", availablePort))
 	}
 
-	balancerClient := balancer.NewClient(cfg)
 	balancerClient.SetPort(int32(availablePort))
 
 	ctx := context.Background()
@@ -86,6 +160,10 @@ func main() {
 	globalBlocker := service.NewGlobalUserBlocker(serviceConfig)
 
 	httpHandlers := http.NewHandlersWithMemoryMonitor(captchaService, repo, sessionCache, globalBlocker)
+	if pool != nil {
+		httpHandlers.SetChallengePool(pool)
+	}
+	httpHandlers.SetDebugSeeds(cfg.DebugSeeds)
 
 	gatewayServer := grpc_gateway.NewServer(grpcHandlers, httpHandlers, availablePort)
 
@@ -115,6 +193,80 @@ func main() {
 	if err := gatewayServer.Stop(shutdownCtx); err != nil {
 		logger.Error("Failed to stop gateway server", zap.Error(err))
 	}
+	if pool != nil {
+		pool.Stop()
+	}
+	if plugins != nil {
+		plugins.Stop()
+	}
 
 	logger.Info("Server stopped")
 }
+
+func newRenderer(cfg *config.CaptchaConfig) *imaging.Renderer {
+	renderer, err := imaging.NewRenderer(cfg.BackgroundsPath, entity.CanvasWidth, entity.CanvasHeight, imaging.BackgroundOptions{
+		Mode:       cfg.BackgroundMode,
+		PhotoBlend: cfg.BackgroundPhotoBlend,
+	})
+	if err != nil {
+		logger.Fatal("Failed to load backgrounds", zap.Error(err))
+	}
+	return renderer
+}
+
+type challengeStore interface {
+	service.ChallengeRepository
+	GetStats() map[string]interface{}
+}
+
+func newChallengeStore(cfg *config.CaptchaConfig, client *redis.Client) challengeStore {
+	switch cfg.ChallengeStore {
+	case config.ChallengeStoreMemory:
+		return persistence.NewMemoryOptimizedRepository(int(cfg.MaxChallenges))
+	case config.ChallengeStoreRedis:
+		logger.Info("Using Redis challenge store", zap.String("addr", cfg.RedisAddr))
+		return persistence.NewRedisRepository(client, cfg.RedisKeyPrefix)
+	default:
+		logger.Fatal("Unsupported challenge store", zap.String("challenge_store", cfg.ChallengeStore))
+		return nil
+	}
+}
+
+func newRedisClient(cfg *config.CaptchaConfig) *redis.Client {
+	client := redis.NewClient(redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       int(cfg.RedisDB),
+		PoolSize: int(cfg.RedisPoolSize),
+		Timeout:  time.Duration(cfg.RedisTimeoutMs) * time.Millisecond,
+	})
+	if err := client.Ping(context.Background()); err != nil {
+		logger.Fatal("Failed to connect to Redis", zap.String("addr", cfg.RedisAddr), zap.Error(err))
+	}
+	return client
+}
+
+// newTokenIssuer keeps redeemed tokens where the challenges are, so that with
+// Redis a token is accepted once whichever instance verifies it.
+func newTokenIssuer(cfg *config.CaptchaConfig, client *redis.Client) *service.TokenIssuer {
+	secret := []byte(cfg.TokenSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.Fatal("Failed to generate token secret", zap.Error(err))
+		}
+		logger.Warn("TOKEN_SECRET is not set: verification tokens are only accepted by this instance until it restarts")
+	}
+
+	// A secret set by hand is shared with other instances, and so must be the
+	// record of redeemed tokens.
+	if cfg.TokenSecret != "" && client == nil {
+		logger.Fatal("TOKEN_SECRET requires CHALLENGE_STORE=redis: with a store per instance a token could be redeemed once on each")
+	}
+
+	var store service.TokenStore = persistence.NewMemoryTokenStore()
+	if client != nil {
+		store = persistence.NewRedisTokenStore(client, cfg.RedisTokenKeyPrefix)
+	}
+	return service.NewTokenIssuer(secret, time.Duration(cfg.TokenTTLSec)*time.Second, store)
+}
@@ -68,13 +68,17 @@ func (x ClientEvent_EventType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ClientEvent_EventType.Descriptor instead.
 func (ClientEvent_EventType) EnumDescriptor() ([]byte, []int) {
-	return file_captcha_captcha_proto_rawDescGZIP(), []int{4, 0}
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{8, 0}
 }
 
 type ChallengeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Complexity    int32                  `protobuf:"varint,1,opt,name=complexity,proto3" json:"complexity,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ChallengeType string                 `protobuf:"bytes,3,opt,name=challenge_type,json=challengeType,proto3" json:"challenge_type,omitempty"`
+	Locale        string                 `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	// Site the challenge is shown on; its verification token is bound to it.
+	Site          string `protobuf:"bytes,5,opt,name=site,proto3" json:"site,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -123,6 +127,27 @@ func (x *ChallengeRequest) GetUserId() string {
 	return ""
 }
 
+func (x *ChallengeRequest) GetChallengeType() string {
+	if x != nil {
+		return x.ChallengeType
+	}
+	return ""
+}
+
+func (x *ChallengeRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *ChallengeRequest) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
 type ChallengeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ChallengeId   string                 `protobuf:"bytes,1,opt,name=challenge_id,json=challengeId,proto3" json:"challenge_id,omitempty"`
@@ -228,9 +253,11 @@ func (x *ValidateRequest) GetAnswer() string {
 }
 
 type ValidateResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	Confidence    int32                  `protobuf:"varint,2,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Valid      bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Confidence int32                  `protobuf:"varint,2,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	// Signed, single-use proof of the valid answer for the customer backend.
+	Token         string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -279,6 +306,233 @@ func (x *ValidateResponse) GetConfidence() int32 {
 	return 0
 }
 
+func (x *ValidateResponse) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type VerifyTokenRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Site the token is expected to be bound to; checked when the challenge
+	// was created for a site.
+	Site          string `protobuf:"bytes,2,opt,name=site,proto3" json:"site,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTokenRequest) Reset() {
+	*x = VerifyTokenRequest{}
+	mi := &file_captcha_captcha_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTokenRequest) ProtoMessage() {}
+
+func (x *VerifyTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captcha_captcha_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTokenRequest.ProtoReflect.Descriptor instead.
+func (*VerifyTokenRequest) Descriptor() ([]byte, []int) {
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *VerifyTokenRequest) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+type VerifyTokenResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Success     bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	ChallengeId string                 `protobuf:"bytes,2,opt,name=challenge_id,json=challengeId,proto3" json:"challenge_id,omitempty"`
+	UserId      string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Site        string                 `protobuf:"bytes,4,opt,name=site,proto3" json:"site,omitempty"`
+	IssuedAt    int64                  `protobuf:"varint,5,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	// Why the token was rejected: invalid_token, token_expired, token_used or
+	// site_mismatch.
+	Error         string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyTokenResponse) Reset() {
+	*x = VerifyTokenResponse{}
+	mi := &file_captcha_captcha_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyTokenResponse) ProtoMessage() {}
+
+func (x *VerifyTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_captcha_captcha_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyTokenResponse.ProtoReflect.Descriptor instead.
+func (*VerifyTokenResponse) Descriptor() ([]byte, []int) {
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyTokenResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *VerifyTokenResponse) GetChallengeId() string {
+	if x != nil {
+		return x.ChallengeId
+	}
+	return ""
+}
+
+func (x *VerifyTokenResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *VerifyTokenResponse) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *VerifyTokenResponse) GetIssuedAt() int64 {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return 0
+}
+
+func (x *VerifyTokenResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListChallengeTypesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChallengeTypesRequest) Reset() {
+	*x = ListChallengeTypesRequest{}
+	mi := &file_captcha_captcha_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChallengeTypesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChallengeTypesRequest) ProtoMessage() {}
+
+func (x *ListChallengeTypesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_captcha_captcha_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChallengeTypesRequest.ProtoReflect.Descriptor instead.
+func (*ListChallengeTypesRequest) Descriptor() ([]byte, []int) {
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{6}
+}
+
+type ListChallengeTypesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChallengeTypes []string               `protobuf:"bytes,1,rep,name=challenge_types,json=challengeTypes,proto3" json:"challenge_types,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListChallengeTypesResponse) Reset() {
+	*x = ListChallengeTypesResponse{}
+	mi := &file_captcha_captcha_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChallengeTypesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChallengeTypesResponse) ProtoMessage() {}
+
+func (x *ListChallengeTypesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_captcha_captcha_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChallengeTypesResponse.ProtoReflect.Descriptor instead.
+func (*ListChallengeTypesResponse) Descriptor() ([]byte, []int) {
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListChallengeTypesResponse) GetChallengeTypes() []string {
+	if x != nil {
+		return x.ChallengeTypes
+	}
+	return nil
+}
+
 type ClientEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	EventType     ClientEvent_EventType  `protobuf:"varint,1,opt,name=event_type,json=eventType,proto3,enum=captcha.v1.ClientEvent_EventType" json:"event_type,omitempty"`
@@ -291,7 +545,7 @@ type ClientEvent struct {
 
 func (x *ClientEvent) Reset() {
 	*x = ClientEvent{}
-	mi := &file_captcha_captcha_proto_msgTypes[4]
+	mi := &file_captcha_captcha_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -303,7 +557,7 @@ func (x *ClientEvent) String() string {
 func (*ClientEvent) ProtoMessage() {}
 
 func (x *ClientEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_captcha_captcha_proto_msgTypes[4]
+	mi := &file_captcha_captcha_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -316,7 +570,7 @@ func (x *ClientEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClientEvent.ProtoReflect.Descriptor instead.
 func (*ClientEvent) Descriptor() ([]byte, []int) {
-	return file_captcha_captcha_proto_rawDescGZIP(), []int{4}
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ClientEvent) GetEventType() ClientEvent_EventType {
@@ -361,7 +615,7 @@ type ServerEvent struct {
 
 func (x *ServerEvent) Reset() {
 	*x = ServerEvent{}
-	mi := &file_captcha_captcha_proto_msgTypes[5]
+	mi := &file_captcha_captcha_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -373,7 +627,7 @@ func (x *ServerEvent) String() string {
 func (*ServerEvent) ProtoMessage() {}
 
 func (x *ServerEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_captcha_captcha_proto_msgTypes[5]
+	mi := &file_captcha_captcha_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -386,7 +640,7 @@ func (x *ServerEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerEvent.ProtoReflect.Descriptor instead.
 func (*ServerEvent) Descriptor() ([]byte, []int) {
-	return file_captcha_captcha_proto_rawDescGZIP(), []int{5}
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ServerEvent) GetEvent() isServerEvent_Event {
@@ -455,7 +709,7 @@ type ServerEvent_ChallengeResult struct {
 
 func (x *ServerEvent_ChallengeResult) Reset() {
 	*x = ServerEvent_ChallengeResult{}
-	mi := &file_captcha_captcha_proto_msgTypes[6]
+	mi := &file_captcha_captcha_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -467,7 +721,7 @@ func (x *ServerEvent_ChallengeResult) String() string {
 func (*ServerEvent_ChallengeResult) ProtoMessage() {}
 
 func (x *ServerEvent_ChallengeResult) ProtoReflect() protoreflect.Message {
-	mi := &file_captcha_captcha_proto_msgTypes[6]
+	mi := &file_captcha_captcha_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -480,7 +734,7 @@ func (x *ServerEvent_ChallengeResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerEvent_ChallengeResult.ProtoReflect.Descriptor instead.
 func (*ServerEvent_ChallengeResult) Descriptor() ([]byte, []int) {
-	return file_captcha_captcha_proto_rawDescGZIP(), []int{5, 0}
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{9, 0}
 }
 
 func (x *ServerEvent_ChallengeResult) GetChallengeId() string {
@@ -507,7 +761,7 @@ type ServerEvent_RunClientJS struct {
 
 func (x *ServerEvent_RunClientJS) Reset() {
 	*x = ServerEvent_RunClientJS{}
-	mi := &file_captcha_captcha_proto_msgTypes[7]
+	mi := &file_captcha_captcha_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -519,7 +773,7 @@ func (x *ServerEvent_RunClientJS) String() string {
 func (*ServerEvent_RunClientJS) ProtoMessage() {}
 
 func (x *ServerEvent_RunClientJS) ProtoReflect() protoreflect.Message {
-	mi := &file_captcha_captcha_proto_msgTypes[7]
+	mi := &file_captcha_captcha_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -532,7 +786,7 @@ func (x *ServerEvent_RunClientJS) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerEvent_RunClientJS.ProtoReflect.Descriptor instead.
 func (*ServerEvent_RunClientJS) Descriptor() ([]byte, []int) {
-	return file_captcha_captcha_proto_rawDescGZIP(), []int{5, 1}
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{9, 1}
 }
 
 func (x *ServerEvent_RunClientJS) GetChallengeId() string {
@@ -559,7 +813,7 @@ type ServerEvent_SendClientData struct {
 
 func (x *ServerEvent_SendClientData) Reset() {
 	*x = ServerEvent_SendClientData{}
-	mi := &file_captcha_captcha_proto_msgTypes[8]
+	mi := &file_captcha_captcha_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -571,7 +825,7 @@ func (x *ServerEvent_SendClientData) String() string {
 func (*ServerEvent_SendClientData) ProtoMessage() {}
 
 func (x *ServerEvent_SendClientData) ProtoReflect() protoreflect.Message {
-	mi := &file_captcha_captcha_proto_msgTypes[8]
+	mi := &file_captcha_captcha_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -584,7 +838,7 @@ func (x *ServerEvent_SendClientData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ServerEvent_SendClientData.ProtoReflect.Descriptor instead.
 func (*ServerEvent_SendClientData) Descriptor() ([]byte, []int) {
-	return file_captcha_captcha_proto_rawDescGZIP(), []int{5, 2}
+	return file_captcha_captcha_proto_rawDescGZIP(), []int{9, 2}
 }
 
 func (x *ServerEvent_SendClientData) GetChallengeId() string {
@@ -606,23 +860,40 @@ var File_captcha_captcha_proto protoreflect.FileDescriptor
 const file_captcha_captcha_proto_rawDesc = "" +
 	"\n" +
 	"\x15captcha/captcha.proto\x12\n" +
-	"captcha.v1\x1a\x1cgoogle/api/annotations.proto\"K\n" +
+	"captcha.v1\x1a\x1cgoogle/api/annotations.proto\"\x9e\x01\n" +
 	"\x10ChallengeRequest\x12\x1e\n" +
 	"\n" +
 	"complexity\x18\x01 \x01(\x05R\n" +
 	"complexity\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"J\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12%\n" +
+	"\x0echallenge_type\x18\x03 \x01(\tR\rchallengeType\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06locale\x12\x12\n" +
+	"\x04site\x18\x05 \x01(\tR\x04site\"J\n" +
 	"\x11ChallengeResponse\x12!\n" +
 	"\fchallenge_id\x18\x01 \x01(\tR\vchallengeId\x12\x12\n" +
 	"\x04html\x18\x02 \x01(\tR\x04html\"L\n" +
 	"\x0fValidateRequest\x12!\n" +
 	"\fchallenge_id\x18\x01 \x01(\tR\vchallengeId\x12\x16\n" +
-	"\x06answer\x18\x02 \x01(\tR\x06answer\"H\n" +
+	"\x06answer\x18\x02 \x01(\tR\x06answer\"^\n" +
 	"\x10ValidateResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1e\n" +
 	"\n" +
 	"confidence\x18\x02 \x01(\x05R\n" +
-	"confidence\"\xeb\x01\n" +
+	"confidence\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\">\n" +
+	"\x12VerifyTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x12\n" +
+	"\x04site\x18\x02 \x01(\tR\x04site\"\xb2\x01\n" +
+	"\x13VerifyTokenResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12!\n" +
+	"\fchallenge_id\x18\x02 \x01(\tR\vchallengeId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04site\x18\x04 \x01(\tR\x04site\x12\x1b\n" +
+	"\tissued_at\x18\x05 \x01(\x03R\bissuedAt\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"\x1b\n" +
+	"\x19ListChallengeTypesRequest\"E\n" +
+	"\x1aListChallengeTypesResponse\x12'\n" +
+	"\x0fchallenge_types\x18\x01 \x03(\tR\x0echallengeTypes\"\xeb\x01\n" +
 	"\vClientEvent\x12@\n" +
 	"\n" +
 	"event_type\x18\x01 \x01(\x0e2!.captcha.v1.ClientEvent.EventTypeR\teventType\x12!\n" +
@@ -647,10 +918,12 @@ const file_captcha_captcha_proto_rawDesc = "" +
 	"\x0eSendClientData\x12!\n" +
 	"\fchallenge_id\x18\x01 \x01(\tR\vchallengeId\x12\x12\n" +
 	"\x04data\x18\x02 \x01(\fR\x04dataB\a\n" +
-	"\x05event2\xad\x02\n" +
+	"\x05event2\x9d\x04\n" +
 	"\x0eCaptchaService\x12f\n" +
 	"\fNewChallenge\x12\x1c.captcha.v1.ChallengeRequest\x1a\x1d.captcha.v1.ChallengeResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/api/challenge\x12h\n" +
-	"\x11ValidateChallenge\x12\x1b.captcha.v1.ValidateRequest\x1a\x1c.captcha.v1.ValidateResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/api/validate\x12I\n" +
+	"\x11ValidateChallenge\x12\x1b.captcha.v1.ValidateRequest\x1a\x1c.captcha.v1.ValidateResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/api/validate\x12j\n" +
+	"\vVerifyToken\x12\x1e.captcha.v1.VerifyTokenRequest\x1a\x1f.captcha.v1.VerifyTokenResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/api/siteverify\x12\x81\x01\n" +
+	"\x12ListChallengeTypes\x12%.captcha.v1.ListChallengeTypesRequest\x1a&.captcha.v1.ListChallengeTypesResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/api/challenge-types\x12I\n" +
 	"\x0fMakeEventStream\x12\x17.captcha.v1.ClientEvent\x1a\x17.captcha.v1.ServerEvent\"\x00(\x010\x01B&Z$captcha-service/gen/proto/captcha/v1b\x06proto3"
 
 var (
@@ -666,35 +939,43 @@ func file_captcha_captcha_proto_rawDescGZIP() []byte {
 }
 
 var file_captcha_captcha_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_captcha_captcha_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_captcha_captcha_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_captcha_captcha_proto_goTypes = []any{
 	(ClientEvent_EventType)(0),          // 0: captcha.v1.ClientEvent.EventType
 	(*ChallengeRequest)(nil),            // 1: captcha.v1.ChallengeRequest
 	(*ChallengeResponse)(nil),           // 2: captcha.v1.ChallengeResponse
 	(*ValidateRequest)(nil),             // 3: captcha.v1.ValidateRequest
 	(*ValidateResponse)(nil),            // 4: captcha.v1.ValidateResponse
-	(*ClientEvent)(nil),                 // 5: captcha.v1.ClientEvent
-	(*ServerEvent)(nil),                 // 6: captcha.v1.ServerEvent
-	(*ServerEvent_ChallengeResult)(nil), // 7: captcha.v1.ServerEvent.ChallengeResult
-	(*ServerEvent_RunClientJS)(nil),     // 8: captcha.v1.ServerEvent.RunClientJS
-	(*ServerEvent_SendClientData)(nil),  // 9: captcha.v1.ServerEvent.SendClientData
+	(*VerifyTokenRequest)(nil),          // 5: captcha.v1.VerifyTokenRequest
+	(*VerifyTokenResponse)(nil),         // 6: captcha.v1.VerifyTokenResponse
+	(*ListChallengeTypesRequest)(nil),   // 7: captcha.v1.ListChallengeTypesRequest
+	(*ListChallengeTypesResponse)(nil),  // 8: captcha.v1.ListChallengeTypesResponse
+	(*ClientEvent)(nil),                 // 9: captcha.v1.ClientEvent
+	(*ServerEvent)(nil),                 // 10: captcha.v1.ServerEvent
+	(*ServerEvent_ChallengeResult)(nil), // 11: captcha.v1.ServerEvent.ChallengeResult
+	(*ServerEvent_RunClientJS)(nil),     // 12: captcha.v1.ServerEvent.RunClientJS
+	(*ServerEvent_SendClientData)(nil),  // 13: captcha.v1.ServerEvent.SendClientData
 }
 var file_captcha_captcha_proto_depIdxs = []int32{
-	0, // 0: captcha.v1.ClientEvent.event_type:type_name -> captcha.v1.ClientEvent.EventType
-	7, // 1: captcha.v1.ServerEvent.result:type_name -> captcha.v1.ServerEvent.ChallengeResult
-	8, // 2: captcha.v1.ServerEvent.client_js:type_name -> captcha.v1.ServerEvent.RunClientJS
-	9, // 3: captcha.v1.ServerEvent.client_data:type_name -> captcha.v1.ServerEvent.SendClientData
-	1, // 4: captcha.v1.CaptchaService.NewChallenge:input_type -> captcha.v1.ChallengeRequest
-	3, // 5: captcha.v1.CaptchaService.ValidateChallenge:input_type -> captcha.v1.ValidateRequest
-	5, // 6: captcha.v1.CaptchaService.MakeEventStream:input_type -> captcha.v1.ClientEvent
-	2, // 7: captcha.v1.CaptchaService.NewChallenge:output_type -> captcha.v1.ChallengeResponse
-	4, // 8: captcha.v1.CaptchaService.ValidateChallenge:output_type -> captcha.v1.ValidateResponse
-	6, // 9: captcha.v1.CaptchaService.MakeEventStream:output_type -> captcha.v1.ServerEvent
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: captcha.v1.ClientEvent.event_type:type_name -> captcha.v1.ClientEvent.EventType
+	11, // 1: captcha.v1.ServerEvent.result:type_name -> captcha.v1.ServerEvent.ChallengeResult
+	12, // 2: captcha.v1.ServerEvent.client_js:type_name -> captcha.v1.ServerEvent.RunClientJS
+	13, // 3: captcha.v1.ServerEvent.client_data:type_name -> captcha.v1.ServerEvent.SendClientData
+	1,  // 4: captcha.v1.CaptchaService.NewChallenge:input_type -> captcha.v1.ChallengeRequest
+	3,  // 5: captcha.v1.CaptchaService.ValidateChallenge:input_type -> captcha.v1.ValidateRequest
+	5,  // 6: captcha.v1.CaptchaService.VerifyToken:input_type -> captcha.v1.VerifyTokenRequest
+	7,  // 7: captcha.v1.CaptchaService.ListChallengeTypes:input_type -> captcha.v1.ListChallengeTypesRequest
+	9,  // 8: captcha.v1.CaptchaService.MakeEventStream:input_type -> captcha.v1.ClientEvent
+	2,  // 9: captcha.v1.CaptchaService.NewChallenge:output_type -> captcha.v1.ChallengeResponse
+	4,  // 10: captcha.v1.CaptchaService.ValidateChallenge:output_type -> captcha.v1.ValidateResponse
+	6,  // 11: captcha.v1.CaptchaService.VerifyToken:output_type -> captcha.v1.VerifyTokenResponse
+	8,  // 12: captcha.v1.CaptchaService.ListChallengeTypes:output_type -> captcha.v1.ListChallengeTypesResponse
+	10, // 13: captcha.v1.CaptchaService.MakeEventStream:output_type -> captcha.v1.ServerEvent
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_captcha_captcha_proto_init() }
@@ -702,7 +983,7 @@ func file_captcha_captcha_proto_init() {
 	if File_captcha_captcha_proto != nil {
 		return
 	}
-	file_captcha_captcha_proto_msgTypes[5].OneofWrappers = []any{
+	file_captcha_captcha_proto_msgTypes[9].OneofWrappers = []any{
 		(*ServerEvent_Result)(nil),
 		(*ServerEvent_ClientJs)(nil),
 		(*ServerEvent_ClientData)(nil),
@@ -713,7 +994,7 @@ func file_captcha_captcha_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_captcha_captcha_proto_rawDesc), len(file_captcha_captcha_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   9,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
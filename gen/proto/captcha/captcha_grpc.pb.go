@@ -19,9 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CaptchaService_NewChallenge_FullMethodName      = "/captcha.v1.CaptchaService/NewChallenge"
-	CaptchaService_ValidateChallenge_FullMethodName = "/captcha.v1.CaptchaService/ValidateChallenge"
-	CaptchaService_MakeEventStream_FullMethodName   = "/captcha.v1.CaptchaService/MakeEventStream"
+	CaptchaService_NewChallenge_FullMethodName       = "/captcha.v1.CaptchaService/NewChallenge"
+	CaptchaService_ValidateChallenge_FullMethodName  = "/captcha.v1.CaptchaService/ValidateChallenge"
+	CaptchaService_VerifyToken_FullMethodName        = "/captcha.v1.CaptchaService/VerifyToken"
+	CaptchaService_ListChallengeTypes_FullMethodName = "/captcha.v1.CaptchaService/ListChallengeTypes"
+	CaptchaService_MakeEventStream_FullMethodName    = "/captcha.v1.CaptchaService/MakeEventStream"
 )
 
 // CaptchaServiceClient is the client API for CaptchaService service.
@@ -30,6 +32,10 @@ const (
 type CaptchaServiceClient interface {
 	NewChallenge(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*ChallengeResponse, error)
 	ValidateChallenge(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	// VerifyToken redeems the token a valid answer was given. Customer
+	// backends call it once per token; a second call reports it as used.
+	VerifyToken(ctx context.Context, in *VerifyTokenRequest, opts ...grpc.CallOption) (*VerifyTokenResponse, error)
+	ListChallengeTypes(ctx context.Context, in *ListChallengeTypesRequest, opts ...grpc.CallOption) (*ListChallengeTypesResponse, error)
 	MakeEventStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ClientEvent, ServerEvent], error)
 }
 
@@ -61,6 +67,26 @@ func (c *captchaServiceClient) ValidateChallenge(ctx context.Context, in *Valida
 	return out, nil
 }
 
+func (c *captchaServiceClient) VerifyToken(ctx context.Context, in *VerifyTokenRequest, opts ...grpc.CallOption) (*VerifyTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyTokenResponse)
+	err := c.cc.Invoke(ctx, CaptchaService_VerifyToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *captchaServiceClient) ListChallengeTypes(ctx context.Context, in *ListChallengeTypesRequest, opts ...grpc.CallOption) (*ListChallengeTypesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListChallengeTypesResponse)
+	err := c.cc.Invoke(ctx, CaptchaService_ListChallengeTypes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *captchaServiceClient) MakeEventStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ClientEvent, ServerEvent], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &CaptchaService_ServiceDesc.Streams[0], CaptchaService_MakeEventStream_FullMethodName, cOpts...)
@@ -80,6 +106,10 @@ type CaptchaService_MakeEventStreamClient = grpc.BidiStreamingClient[ClientEvent
 type CaptchaServiceServer interface {
 	NewChallenge(context.Context, *ChallengeRequest) (*ChallengeResponse, error)
 	ValidateChallenge(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	// VerifyToken redeems the token a valid answer was given. Customer
+	// backends call it once per token; a second call reports it as used.
+	VerifyToken(context.Context, *VerifyTokenRequest) (*VerifyTokenResponse, error)
+	ListChallengeTypes(context.Context, *ListChallengeTypesRequest) (*ListChallengeTypesResponse, error)
 	MakeEventStream(grpc.BidiStreamingServer[ClientEvent, ServerEvent]) error
 	mustEmbedUnimplementedCaptchaServiceServer()
 }
@@ -97,6 +127,12 @@ func (UnimplementedCaptchaServiceServer) NewChallenge(context.Context, *Challeng
 func (UnimplementedCaptchaServiceServer) ValidateChallenge(context.Context, *ValidateRequest) (*ValidateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ValidateChallenge not implemented")
 }
+func (UnimplementedCaptchaServiceServer) VerifyToken(context.Context, *VerifyTokenRequest) (*VerifyTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyToken not implemented")
+}
+func (UnimplementedCaptchaServiceServer) ListChallengeTypes(context.Context, *ListChallengeTypesRequest) (*ListChallengeTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChallengeTypes not implemented")
+}
 func (UnimplementedCaptchaServiceServer) MakeEventStream(grpc.BidiStreamingServer[ClientEvent, ServerEvent]) error {
 	return status.Errorf(codes.Unimplemented, "method MakeEventStream not implemented")
 }
@@ -157,6 +193,42 @@ func _CaptchaService_ValidateChallenge_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CaptchaService_VerifyToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaptchaServiceServer).VerifyToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CaptchaService_VerifyToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaptchaServiceServer).VerifyToken(ctx, req.(*VerifyTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CaptchaService_ListChallengeTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChallengeTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaptchaServiceServer).ListChallengeTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CaptchaService_ListChallengeTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaptchaServiceServer).ListChallengeTypes(ctx, req.(*ListChallengeTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CaptchaService_MakeEventStream_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(CaptchaServiceServer).MakeEventStream(&grpc.GenericServerStream[ClientEvent, ServerEvent]{ServerStream: stream})
 }
@@ -179,6 +251,14 @@ var CaptchaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ValidateChallenge",
 			Handler:    _CaptchaService_ValidateChallenge_Handler,
 		},
+		{
+			MethodName: "VerifyToken",
+			Handler:    _CaptchaService_VerifyToken_Handler,
+		},
+		{
+			MethodName: "ListChallengeTypes",
+			Handler:    _CaptchaService_ListChallengeTypes_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
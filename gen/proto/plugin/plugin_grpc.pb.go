@@ -0,0 +1,245 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: plugin/plugin.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GeneratorPlugin_Describe_FullMethodName    = "/plugin.v1.GeneratorPlugin/Describe"
+	GeneratorPlugin_Generate_FullMethodName    = "/plugin.v1.GeneratorPlugin/Generate"
+	GeneratorPlugin_Validate_FullMethodName    = "/plugin.v1.GeneratorPlugin/Validate"
+	GeneratorPlugin_HandleEvent_FullMethodName = "/plugin.v1.GeneratorPlugin/HandleEvent"
+)
+
+// GeneratorPluginClient is the client API for GeneratorPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GeneratorPlugin is served by a challenge generator running in its own
+// process. The captcha service keeps the challenges: the plugin renders them,
+// returns whatever it needs to judge an answer as opaque state and gets that
+// state back with every later call. Plugins also serve grpc.health.v1.Health.
+type GeneratorPluginClient interface {
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error)
+	HandleEvent(ctx context.Context, in *HandleEventRequest, opts ...grpc.CallOption) (*HandleEventResponse, error)
+}
+
+type generatorPluginClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGeneratorPluginClient(cc grpc.ClientConnInterface) GeneratorPluginClient {
+	return &generatorPluginClient{cc}
+}
+
+func (c *generatorPluginClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DescribeResponse)
+	err := c.cc.Invoke(ctx, GeneratorPlugin_Describe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorPluginClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, GeneratorPlugin_Generate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorPluginClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateResponse)
+	err := c.cc.Invoke(ctx, GeneratorPlugin_Validate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *generatorPluginClient) HandleEvent(ctx context.Context, in *HandleEventRequest, opts ...grpc.CallOption) (*HandleEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandleEventResponse)
+	err := c.cc.Invoke(ctx, GeneratorPlugin_HandleEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GeneratorPluginServer is the server API for GeneratorPlugin service.
+// All implementations must embed UnimplementedGeneratorPluginServer
+// for forward compatibility.
+//
+// GeneratorPlugin is served by a challenge generator running in its own
+// process. The captcha service keeps the challenges: the plugin renders them,
+// returns whatever it needs to judge an answer as opaque state and gets that
+// state back with every later call. Plugins also serve grpc.health.v1.Health.
+type GeneratorPluginServer interface {
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	Validate(context.Context, *ValidateRequest) (*ValidateResponse, error)
+	HandleEvent(context.Context, *HandleEventRequest) (*HandleEventResponse, error)
+	mustEmbedUnimplementedGeneratorPluginServer()
+}
+
+// UnimplementedGeneratorPluginServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGeneratorPluginServer struct{}
+
+func (UnimplementedGeneratorPluginServer) Describe(context.Context, *DescribeRequest) (*DescribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
+func (UnimplementedGeneratorPluginServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedGeneratorPluginServer) Validate(context.Context, *ValidateRequest) (*ValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (UnimplementedGeneratorPluginServer) HandleEvent(context.Context, *HandleEventRequest) (*HandleEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleEvent not implemented")
+}
+func (UnimplementedGeneratorPluginServer) mustEmbedUnimplementedGeneratorPluginServer() {}
+func (UnimplementedGeneratorPluginServer) testEmbeddedByValue()                         {}
+
+// UnsafeGeneratorPluginServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GeneratorPluginServer will
+// result in compilation errors.
+type UnsafeGeneratorPluginServer interface {
+	mustEmbedUnimplementedGeneratorPluginServer()
+}
+
+func RegisterGeneratorPluginServer(s grpc.ServiceRegistrar, srv GeneratorPluginServer) {
+	// If the following call pancis, it indicates UnimplementedGeneratorPluginServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GeneratorPlugin_ServiceDesc, srv)
+}
+
+func _GeneratorPlugin_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorPluginServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeneratorPlugin_Describe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorPluginServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeneratorPlugin_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorPluginServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeneratorPlugin_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorPluginServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeneratorPlugin_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorPluginServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeneratorPlugin_Validate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorPluginServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GeneratorPlugin_HandleEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandleEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GeneratorPluginServer).HandleEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GeneratorPlugin_HandleEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GeneratorPluginServer).HandleEvent(ctx, req.(*HandleEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GeneratorPlugin_ServiceDesc is the grpc.ServiceDesc for GeneratorPlugin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GeneratorPlugin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.v1.GeneratorPlugin",
+	HandlerType: (*GeneratorPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Describe",
+			Handler:    _GeneratorPlugin_Describe_Handler,
+		},
+		{
+			MethodName: "Generate",
+			Handler:    _GeneratorPlugin_Generate_Handler,
+		},
+		{
+			MethodName: "Validate",
+			Handler:    _GeneratorPlugin_Validate_Handler,
+		},
+		{
+			MethodName: "HandleEvent",
+			Handler:    _GeneratorPlugin_HandleEvent_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin/plugin.proto",
+}
@@ -152,6 +152,50 @@ func (BlockUserResponse_Status) EnumDescriptor() ([]byte, []int) {
 	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{5, 0}
 }
 
+type UnblockUserResponse_Status int32
+
+const (
+	UnblockUserResponse_SUCCESS UnblockUserResponse_Status = 0
+	UnblockUserResponse_ERROR   UnblockUserResponse_Status = 1
+)
+
+var (
+	UnblockUserResponse_Status_name = map[int32]string{
+		0: "SUCCESS",
+		1: "ERROR",
+	}
+	UnblockUserResponse_Status_value = map[string]int32{
+		"SUCCESS": 0,
+		"ERROR":   1,
+	}
+)
+
+func (x UnblockUserResponse_Status) Enum() *UnblockUserResponse_Status {
+	p := new(UnblockUserResponse_Status)
+	*p = x
+	return p
+}
+
+func (x UnblockUserResponse_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UnblockUserResponse_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_balancer_balancer_proto_enumTypes[3].Descriptor()
+}
+
+func (UnblockUserResponse_Status) Type() protoreflect.EnumType {
+	return &file_proto_balancer_balancer_proto_enumTypes[3]
+}
+
+func (x UnblockUserResponse_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+func (UnblockUserResponse_Status) EnumDescriptor() ([]byte, []int) {
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{7, 0}
+}
+
 type RegisterInstanceRequest struct {
 	state         protoimpl.MessageState            `protogen:"open.v1"`
 	EventType     RegisterInstanceRequest_EventType `protobuf:"varint,1,opt,name=event_type,json=eventType,proto3,enum=balancer.v1.RegisterInstanceRequest_EventType" json:"event_type,omitempty"`
@@ -334,6 +378,7 @@ type CheckUserBlockedResponse struct {
 	IsBlocked     bool                   `protobuf:"varint,1,opt,name=is_blocked,json=isBlocked,proto3" json:"is_blocked,omitempty"`
 	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	BlockedUntil  int64                  `protobuf:"varint,3,opt,name=blocked_until,json=blockedUntil,proto3" json:"blocked_until,omitempty"`
+	BlockedAtMs   int64                  `protobuf:"varint,4,opt,name=blocked_at_ms,json=blockedAtMs,proto3" json:"blocked_at_ms,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -388,11 +433,19 @@ func (x *CheckUserBlockedResponse) GetBlockedUntil() int64 {
 	return 0
 }
 
+func (x *CheckUserBlockedResponse) GetBlockedAtMs() int64 {
+	if x != nil {
+		return x.BlockedAtMs
+	}
+	return 0
+}
+
 type BlockUserRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	DurationMinutes int32                  `protobuf:"varint,2,opt,name=duration_minutes,json=durationMinutes,proto3" json:"duration_minutes,omitempty"`
 	Reason          string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	BlockedAtMs     int64                  `protobuf:"varint,4,opt,name=blocked_at_ms,json=blockedAtMs,proto3" json:"blocked_at_ms,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -447,6 +500,13 @@ func (x *BlockUserRequest) GetReason() string {
 	return ""
 }
 
+func (x *BlockUserRequest) GetBlockedAtMs() int64 {
+	if x != nil {
+		return x.BlockedAtMs
+	}
+	return 0
+}
+
 type BlockUserResponse struct {
 	state         protoimpl.MessageState   `protogen:"open.v1"`
 	Status        BlockUserResponse_Status `protobuf:"varint,1,opt,name=status,proto3,enum=balancer.v1.BlockUserResponse_Status" json:"status,omitempty"`
@@ -498,6 +558,253 @@ func (x *BlockUserResponse) GetMessage() string {
 	return ""
 }
 
+type UnblockUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnblockUserRequest) Reset() {
+	*x = UnblockUserRequest{}
+	mi := &file_proto_balancer_balancer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnblockUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnblockUserRequest) ProtoMessage() {}
+
+func (x *UnblockUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balancer_balancer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*UnblockUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UnblockUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UnblockUserResponse struct {
+	state         protoimpl.MessageState     `protogen:"open.v1"`
+	Status        UnblockUserResponse_Status `protobuf:"varint,1,opt,name=status,proto3,enum=balancer.v1.UnblockUserResponse_Status" json:"status,omitempty"`
+	Message       string                     `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnblockUserResponse) Reset() {
+	*x = UnblockUserResponse{}
+	mi := &file_proto_balancer_balancer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnblockUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnblockUserResponse) ProtoMessage() {}
+
+func (x *UnblockUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balancer_balancer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*UnblockUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UnblockUserResponse) GetStatus() UnblockUserResponse_Status {
+	if x != nil {
+		return x.Status
+	}
+	return UnblockUserResponse_SUCCESS
+}
+
+func (x *UnblockUserResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListBlockedUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedUsersRequest) Reset() {
+	*x = ListBlockedUsersRequest{}
+	mi := &file_proto_balancer_balancer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedUsersRequest) ProtoMessage() {}
+
+func (x *ListBlockedUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balancer_balancer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*ListBlockedUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{8}
+}
+
+type BlockedUserInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	BlockedUntil  int64                  `protobuf:"varint,3,opt,name=blocked_until,json=blockedUntil,proto3" json:"blocked_until,omitempty"`
+	BlockedAtMs   int64                  `protobuf:"varint,4,opt,name=blocked_at_ms,json=blockedAtMs,proto3" json:"blocked_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockedUserInfo) Reset() {
+	*x = BlockedUserInfo{}
+	mi := &file_proto_balancer_balancer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockedUserInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockedUserInfo) ProtoMessage() {}
+
+func (x *BlockedUserInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balancer_balancer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*BlockedUserInfo) Descriptor() ([]byte, []int) {
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BlockedUserInfo) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BlockedUserInfo) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BlockedUserInfo) GetBlockedUntil() int64 {
+	if x != nil {
+		return x.BlockedUntil
+	}
+	return 0
+}
+
+func (x *BlockedUserInfo) GetBlockedAtMs() int64 {
+	if x != nil {
+		return x.BlockedAtMs
+	}
+	return 0
+}
+
+type ListBlockedUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*BlockedUserInfo     `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBlockedUsersResponse) Reset() {
+	*x = ListBlockedUsersResponse{}
+	mi := &file_proto_balancer_balancer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBlockedUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlockedUsersResponse) ProtoMessage() {}
+
+func (x *ListBlockedUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_balancer_balancer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*ListBlockedUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListBlockedUsersResponse) GetUsers() []*BlockedUserInfo {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListBlockedUsersResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
 type GetInstancesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -506,7 +813,7 @@ type GetInstancesRequest struct {
 
 func (x *GetInstancesRequest) Reset() {
 	*x = GetInstancesRequest{}
-	mi := &file_proto_balancer_balancer_proto_msgTypes[6]
+	mi := &file_proto_balancer_balancer_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -518,7 +825,7 @@ func (x *GetInstancesRequest) String() string {
 func (*GetInstancesRequest) ProtoMessage() {}
 
 func (x *GetInstancesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_balancer_balancer_proto_msgTypes[6]
+	mi := &file_proto_balancer_balancer_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -530,7 +837,7 @@ func (x *GetInstancesRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*GetInstancesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{6}
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{11}
 }
 
 type InstanceInfo struct {
@@ -547,7 +854,7 @@ type InstanceInfo struct {
 
 func (x *InstanceInfo) Reset() {
 	*x = InstanceInfo{}
-	mi := &file_proto_balancer_balancer_proto_msgTypes[7]
+	mi := &file_proto_balancer_balancer_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -559,7 +866,7 @@ func (x *InstanceInfo) String() string {
 func (*InstanceInfo) ProtoMessage() {}
 
 func (x *InstanceInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_balancer_balancer_proto_msgTypes[7]
+	mi := &file_proto_balancer_balancer_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +878,7 @@ func (x *InstanceInfo) ProtoReflect() protoreflect.Message {
 }
 
 func (*InstanceInfo) Descriptor() ([]byte, []int) {
-	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{7}
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *InstanceInfo) GetInstanceId() string {
@@ -626,7 +933,7 @@ type GetInstancesResponse struct {
 
 func (x *GetInstancesResponse) Reset() {
 	*x = GetInstancesResponse{}
-	mi := &file_proto_balancer_balancer_proto_msgTypes[8]
+	mi := &file_proto_balancer_balancer_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -638,7 +945,7 @@ func (x *GetInstancesResponse) String() string {
 func (*GetInstancesResponse) ProtoMessage() {}
 
 func (x *GetInstancesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_balancer_balancer_proto_msgTypes[8]
+	mi := &file_proto_balancer_balancer_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -650,7 +957,7 @@ func (x *GetInstancesResponse) ProtoReflect() protoreflect.Message {
 }
 
 func (*GetInstancesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{8}
+	return file_proto_balancer_balancer_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetInstancesResponse) GetInstances() []*InstanceInfo {
@@ -694,22 +1001,41 @@ const file_proto_balancer_balancer_proto_rawDesc = "" +
 	"\aSUCCESS\x10\x00\x12\t\n" +
 	"\x05ERROR\x10\x01\"2\n" +
 	"\x17CheckUserBlockedRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"v\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x9a\x01\n" +
 	"\x18CheckUserBlockedResponse\x12\x1d\n" +
 	"\n" +
 	"is_blocked\x18\x01 \x01(\bR\tisBlocked\x12\x16\n" +
 	"\x06reason\x18\x02 \x01(\tR\x06reason\x12#\n" +
-	"\rblocked_until\x18\x03 \x01(\x03R\fblockedUntil\"n\n" +
+	"\rblocked_until\x18\x03 \x01(\x03R\fblockedUntil\x12\"\n" +
+	"\rblocked_at_ms\x18\x04 \x01(\x03R\vblockedAtMs\"\x92\x01\n" +
 	"\x10BlockUserRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12)\n" +
 	"\x10duration_minutes\x18\x02 \x01(\x05R\x0fdurationMinutes\x12\x16\n" +
-	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x8e\x01\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\"\n" +
+	"\rblocked_at_ms\x18\x04 \x01(\x03R\vblockedAtMs\"\x8e\x01\n" +
 	"\x11BlockUserResponse\x12=\n" +
 	"\x06status\x18\x01 \x01(\x0e2%.balancer.v1.BlockUserResponse.StatusR\x06status\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\" \n" +
 	"\x06Status\x12\v\n" +
 	"\aSUCCESS\x10\x00\x12\t\n" +
-	"\x05ERROR\x10\x01\"\x15\n" +
+	"\x05ERROR\x10\x01\"-\n" +
+	"\x12UnblockUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x92\x01\n" +
+	"\x13UnblockUserResponse\x12?\n" +
+	"\x06status\x18\x01 \x01(\x0e2'.balancer.v1.UnblockUserResponse.StatusR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\" \n" +
+	"\x06Status\x12\v\n" +
+	"\aSUCCESS\x10\x00\x12\t\n" +
+	"\x05ERROR\x10\x01\"\x19\n" +
+	"\x17ListBlockedUsersRequest\"\x8b\x01\n" +
+	"\x0fBlockedUserInfo\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12#\n" +
+	"\rblocked_until\x18\x03 \x01(\x03R\fblockedUntil\x12\"\n" +
+	"\rblocked_at_ms\x18\x04 \x01(\x03R\vblockedAtMs\"d\n" +
+	"\x18ListBlockedUsersResponse\x122\n" +
+	"\x05users\x18\x01 \x03(\v2\x1c.balancer.v1.BlockedUserInfoR\x05users\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"\x15\n" +
 	"\x13GetInstancesRequest\"\xc0\x01\n" +
 	"\fInstanceInfo\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\tR\n" +
@@ -722,11 +1048,13 @@ const file_proto_balancer_balancer_proto_rawDesc = "" +
 	"\tlast_seen\x18\x06 \x01(\x03R\blastSeen\"e\n" +
 	"\x14GetInstancesResponse\x127\n" +
 	"\tinstances\x18\x01 \x03(\v2\x19.balancer.v1.InstanceInfoR\tinstances\x12\x14\n" +
-	"\x05count\x18\x02 \x01(\x05R\x05count2\x80\x03\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count2\xb7\x04\n" +
 	"\x0fBalancerService\x12e\n" +
 	"\x10RegisterInstance\x12$.balancer.v1.RegisterInstanceRequest\x1a%.balancer.v1.RegisterInstanceResponse\"\x00(\x010\x01\x12a\n" +
 	"\x10CheckUserBlocked\x12$.balancer.v1.CheckUserBlockedRequest\x1a%.balancer.v1.CheckUserBlockedResponse\"\x00\x12L\n" +
-	"\tBlockUser\x12\x1d.balancer.v1.BlockUserRequest\x1a\x1e.balancer.v1.BlockUserResponse\"\x00\x12U\n" +
+	"\tBlockUser\x12\x1d.balancer.v1.BlockUserRequest\x1a\x1e.balancer.v1.BlockUserResponse\"\x00\x12R\n" +
+	"\vUnblockUser\x12\x1f.balancer.v1.UnblockUserRequest\x1a .balancer.v1.UnblockUserResponse\"\x00\x12a\n" +
+	"\x10ListBlockedUsers\x12$.balancer.v1.ListBlockedUsersRequest\x1a%.balancer.v1.ListBlockedUsersResponse\"\x00\x12U\n" +
 	"\fGetInstances\x12 .balancer.v1.GetInstancesRequest\x1a!.balancer.v1.GetInstancesResponse\"\x00B'Z%captcha-service/gen/proto/balancer/v1b\x06proto3"
 
 var (
@@ -741,40 +1069,52 @@ func file_proto_balancer_balancer_proto_rawDescGZIP() []byte {
 	return file_proto_balancer_balancer_proto_rawDescData
 }
 
-var file_proto_balancer_balancer_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_proto_balancer_balancer_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_balancer_balancer_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_balancer_balancer_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_proto_balancer_balancer_proto_goTypes = []any{
 	(RegisterInstanceRequest_EventType)(0), // 0: balancer.v1.RegisterInstanceRequest.EventType
 	(RegisterInstanceResponse_Status)(0),   // 1: balancer.v1.RegisterInstanceResponse.Status
 	(BlockUserResponse_Status)(0),          // 2: balancer.v1.BlockUserResponse.Status
-	(*RegisterInstanceRequest)(nil),        // 3: balancer.v1.RegisterInstanceRequest
-	(*RegisterInstanceResponse)(nil),       // 4: balancer.v1.RegisterInstanceResponse
-	(*CheckUserBlockedRequest)(nil),        // 5: balancer.v1.CheckUserBlockedRequest
-	(*CheckUserBlockedResponse)(nil),       // 6: balancer.v1.CheckUserBlockedResponse
-	(*BlockUserRequest)(nil),               // 7: balancer.v1.BlockUserRequest
-	(*BlockUserResponse)(nil),              // 8: balancer.v1.BlockUserResponse
-	(*GetInstancesRequest)(nil),            // 9: balancer.v1.GetInstancesRequest
-	(*InstanceInfo)(nil),                   // 10: balancer.v1.InstanceInfo
-	(*GetInstancesResponse)(nil),           // 11: balancer.v1.GetInstancesResponse
+	(UnblockUserResponse_Status)(0),        // 3: balancer.v1.UnblockUserResponse.Status
+	(*RegisterInstanceRequest)(nil),        // 4: balancer.v1.RegisterInstanceRequest
+	(*RegisterInstanceResponse)(nil),       // 5: balancer.v1.RegisterInstanceResponse
+	(*CheckUserBlockedRequest)(nil),        // 6: balancer.v1.CheckUserBlockedRequest
+	(*CheckUserBlockedResponse)(nil),       // 7: balancer.v1.CheckUserBlockedResponse
+	(*BlockUserRequest)(nil),               // 8: balancer.v1.BlockUserRequest
+	(*BlockUserResponse)(nil),              // 9: balancer.v1.BlockUserResponse
+	(*UnblockUserRequest)(nil),             // 10: balancer.v1.UnblockUserRequest
+	(*UnblockUserResponse)(nil),            // 11: balancer.v1.UnblockUserResponse
+	(*ListBlockedUsersRequest)(nil),        // 12: balancer.v1.ListBlockedUsersRequest
+	(*BlockedUserInfo)(nil),                // 13: balancer.v1.BlockedUserInfo
+	(*ListBlockedUsersResponse)(nil),       // 14: balancer.v1.ListBlockedUsersResponse
+	(*GetInstancesRequest)(nil),            // 15: balancer.v1.GetInstancesRequest
+	(*InstanceInfo)(nil),                   // 16: balancer.v1.InstanceInfo
+	(*GetInstancesResponse)(nil),           // 17: balancer.v1.GetInstancesResponse
 }
 var file_proto_balancer_balancer_proto_depIdxs = []int32{
 	0,  // 0: balancer.v1.RegisterInstanceRequest.event_type:type_name -> balancer.v1.RegisterInstanceRequest.EventType
 	1,  // 1: balancer.v1.RegisterInstanceResponse.status:type_name -> balancer.v1.RegisterInstanceResponse.Status
 	2,  // 2: balancer.v1.BlockUserResponse.status:type_name -> balancer.v1.BlockUserResponse.Status
-	10, // 3: balancer.v1.GetInstancesResponse.instances:type_name -> balancer.v1.InstanceInfo
-	3,  // 4: balancer.v1.BalancerService.RegisterInstance:input_type -> balancer.v1.RegisterInstanceRequest
-	5,  // 5: balancer.v1.BalancerService.CheckUserBlocked:input_type -> balancer.v1.CheckUserBlockedRequest
-	7,  // 6: balancer.v1.BalancerService.BlockUser:input_type -> balancer.v1.BlockUserRequest
-	9,  // 7: balancer.v1.BalancerService.GetInstances:input_type -> balancer.v1.GetInstancesRequest
-	4,  // 8: balancer.v1.BalancerService.RegisterInstance:output_type -> balancer.v1.RegisterInstanceResponse
-	6,  // 9: balancer.v1.BalancerService.CheckUserBlocked:output_type -> balancer.v1.CheckUserBlockedResponse
-	8,  // 10: balancer.v1.BalancerService.BlockUser:output_type -> balancer.v1.BlockUserResponse
-	11, // 11: balancer.v1.BalancerService.GetInstances:output_type -> balancer.v1.GetInstancesResponse
-	8,  // [8:12] is the sub-list for method output_type
-	4,  // [4:8] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	3,  // 3: balancer.v1.UnblockUserResponse.status:type_name -> balancer.v1.UnblockUserResponse.Status
+	13, // 4: balancer.v1.ListBlockedUsersResponse.users:type_name -> balancer.v1.BlockedUserInfo
+	16, // 5: balancer.v1.GetInstancesResponse.instances:type_name -> balancer.v1.InstanceInfo
+	4,  // 6: balancer.v1.BalancerService.RegisterInstance:input_type -> balancer.v1.RegisterInstanceRequest
+	6,  // 7: balancer.v1.BalancerService.CheckUserBlocked:input_type -> balancer.v1.CheckUserBlockedRequest
+	8,  // 8: balancer.v1.BalancerService.BlockUser:input_type -> balancer.v1.BlockUserRequest
+	10, // 9: balancer.v1.BalancerService.UnblockUser:input_type -> balancer.v1.UnblockUserRequest
+	12, // 10: balancer.v1.BalancerService.ListBlockedUsers:input_type -> balancer.v1.ListBlockedUsersRequest
+	15, // 11: balancer.v1.BalancerService.GetInstances:input_type -> balancer.v1.GetInstancesRequest
+	5,  // 12: balancer.v1.BalancerService.RegisterInstance:output_type -> balancer.v1.RegisterInstanceResponse
+	7,  // 13: balancer.v1.BalancerService.CheckUserBlocked:output_type -> balancer.v1.CheckUserBlockedResponse
+	9,  // 14: balancer.v1.BalancerService.BlockUser:output_type -> balancer.v1.BlockUserResponse
+	11, // 15: balancer.v1.BalancerService.UnblockUser:output_type -> balancer.v1.UnblockUserResponse
+	14, // 16: balancer.v1.BalancerService.ListBlockedUsers:output_type -> balancer.v1.ListBlockedUsersResponse
+	17, // 17: balancer.v1.BalancerService.GetInstances:output_type -> balancer.v1.GetInstancesResponse
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_proto_balancer_balancer_proto_init() }
@@ -787,8 +1127,8 @@ func file_proto_balancer_balancer_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_balancer_balancer_proto_rawDesc), len(file_proto_balancer_balancer_proto_rawDesc)),
-			NumEnums:      3,
-			NumMessages:   9,
+			NumEnums:      4,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -13,6 +13,8 @@ const (
 	BalancerService_RegisterInstance_FullMethodName = "/balancer.v1.BalancerService/RegisterInstance"
 	BalancerService_CheckUserBlocked_FullMethodName = "/balancer.v1.BalancerService/CheckUserBlocked"
 	BalancerService_BlockUser_FullMethodName        = "/balancer.v1.BalancerService/BlockUser"
+	BalancerService_UnblockUser_FullMethodName      = "/balancer.v1.BalancerService/UnblockUser"
+	BalancerService_ListBlockedUsers_FullMethodName = "/balancer.v1.BalancerService/ListBlockedUsers"
 	BalancerService_GetInstances_FullMethodName     = "/balancer.v1.BalancerService/GetInstances"
 )
 
@@ -20,6 +22,8 @@ type BalancerServiceClient interface {
 	RegisterInstance(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RegisterInstanceRequest, RegisterInstanceResponse], error)
 	CheckUserBlocked(ctx context.Context, in *CheckUserBlockedRequest, opts ...grpc.CallOption) (*CheckUserBlockedResponse, error)
 	BlockUser(ctx context.Context, in *BlockUserRequest, opts ...grpc.CallOption) (*BlockUserResponse, error)
+	UnblockUser(ctx context.Context, in *UnblockUserRequest, opts ...grpc.CallOption) (*UnblockUserResponse, error)
+	ListBlockedUsers(ctx context.Context, in *ListBlockedUsersRequest, opts ...grpc.CallOption) (*ListBlockedUsersResponse, error)
 	GetInstances(ctx context.Context, in *GetInstancesRequest, opts ...grpc.CallOption) (*GetInstancesResponse, error)
 }
 
@@ -63,6 +67,26 @@ func (c *balancerServiceClient) BlockUser(ctx context.Context, in *BlockUserRequ
 	return out, nil
 }
 
+func (c *balancerServiceClient) UnblockUser(ctx context.Context, in *UnblockUserRequest, opts ...grpc.CallOption) (*UnblockUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnblockUserResponse)
+	err := c.cc.Invoke(ctx, BalancerService_UnblockUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *balancerServiceClient) ListBlockedUsers(ctx context.Context, in *ListBlockedUsersRequest, opts ...grpc.CallOption) (*ListBlockedUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBlockedUsersResponse)
+	err := c.cc.Invoke(ctx, BalancerService_ListBlockedUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *balancerServiceClient) GetInstances(ctx context.Context, in *GetInstancesRequest, opts ...grpc.CallOption) (*GetInstancesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetInstancesResponse)
@@ -77,6 +101,8 @@ type BalancerServiceServer interface {
 	RegisterInstance(grpc.BidiStreamingServer[RegisterInstanceRequest, RegisterInstanceResponse]) error
 	CheckUserBlocked(context.Context, *CheckUserBlockedRequest) (*CheckUserBlockedResponse, error)
 	BlockUser(context.Context, *BlockUserRequest) (*BlockUserResponse, error)
+	UnblockUser(context.Context, *UnblockUserRequest) (*UnblockUserResponse, error)
+	ListBlockedUsers(context.Context, *ListBlockedUsersRequest) (*ListBlockedUsersResponse, error)
 	GetInstances(context.Context, *GetInstancesRequest) (*GetInstancesResponse, error)
 	mustEmbedUnimplementedBalancerServiceServer()
 }
@@ -92,6 +118,12 @@ func (UnimplementedBalancerServiceServer) CheckUserBlocked(context.Context, *Che
 func (UnimplementedBalancerServiceServer) BlockUser(context.Context, *BlockUserRequest) (*BlockUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BlockUser not implemented")
 }
+func (UnimplementedBalancerServiceServer) UnblockUser(context.Context, *UnblockUserRequest) (*UnblockUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockUser not implemented")
+}
+func (UnimplementedBalancerServiceServer) ListBlockedUsers(context.Context, *ListBlockedUsersRequest) (*ListBlockedUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockedUsers not implemented")
+}
 func (UnimplementedBalancerServiceServer) GetInstances(context.Context, *GetInstancesRequest) (*GetInstancesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInstances not implemented")
 }
@@ -151,6 +183,42 @@ func _BalancerService_BlockUser_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BalancerService_UnblockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnblockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalancerServiceServer).UnblockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BalancerService_UnblockUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalancerServiceServer).UnblockUser(ctx, req.(*UnblockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BalancerService_ListBlockedUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockedUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BalancerServiceServer).ListBlockedUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BalancerService_ListBlockedUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BalancerServiceServer).ListBlockedUsers(ctx, req.(*ListBlockedUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BalancerService_GetInstances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetInstancesRequest)
 	if err := dec(in); err != nil {
@@ -181,6 +249,14 @@ var BalancerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BlockUser",
 			Handler:    _BalancerService_BlockUser_Handler,
 		},
+		{
+			MethodName: "UnblockUser",
+			Handler:    _BalancerService_UnblockUser_Handler,
+		},
+		{
+			MethodName: "ListBlockedUsers",
+			Handler:    _BalancerService_ListBlockedUsers_Handler,
+		},
 		{
 			MethodName: "GetInstances",
 			Handler:    _BalancerService_GetInstances_Handler,
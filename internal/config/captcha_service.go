@@ -1,9 +1,16 @@
 package config
 
 import (
+	"os"
+
 	"github.com/caarlos0/env/v11"
 )
 
+const (
+	ChallengeStoreMemory = "memory"
+	ChallengeStoreRedis  = "redis"
+)
+
 type CaptchaConfig struct {
 	Host string `env:"HOST" envDefault:"localhost"`
 	Port string `env:"PORT" envDefault:"8080"`
@@ -12,44 +19,121 @@ type CaptchaConfig struct {
 
 	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
 
-	ChallengeType        string `env:"CHALLENGE_TYPE" envDefault:"slider-puzzle"`
-	ComplexityLow        int32  `env:"COMPLEXITY_LOW" envDefault:"30"`
-	ComplexityMedium     int32  `env:"COMPLEXITY_MEDIUM" envDefault:"50"`
-	ComplexityHigh       int32  `env:"COMPLEXITY_HIGH" envDefault:"70"`
-	PuzzleSizeLow        int32  `env:"PUZZLE_SIZE_LOW" envDefault:"200"`
-	PuzzleSizeMedium     int32  `env:"PUZZLE_SIZE_MEDIUM" envDefault:"300"`
-	PuzzleSizeHigh       int32  `env:"PUZZLE_SIZE_HIGH" envDefault:"400"`
-	ToleranceLow         int32  `env:"TOLERANCE_LOW" envDefault:"10"`
-	ToleranceMedium      int32  `env:"TOLERANCE_MEDIUM" envDefault:"5"`
-	ToleranceHigh        int32  `env:"TOLERANCE_HIGH" envDefault:"3"`
-	ExpirationTimeLow    int32  `env:"EXPIRATION_TIME_LOW" envDefault:"300"`
-	ExpirationTimeMedium int32  `env:"EXPIRATION_TIME_MEDIUM" envDefault:"180"`
-	ExpirationTimeHigh   int32  `env:"EXPIRATION_TIME_HIGH" envDefault:"120"`
-
-	MinTimeMs          int32 `env:"MIN_TIME_MS" envDefault:"1000"`
-	MaxTimeMs          int32 `env:"MAX_TIME_MS" envDefault:"30000"`
+	ChallengeType string `env:"CHALLENGE_TYPE" envDefault:"slider-puzzle"`
+	// ComplexityMedium is used when a request has no valid complexity.
+	ComplexityMedium int32 `env:"COMPLEXITY_MEDIUM" envDefault:"50"`
+	// DifficultyProfilesPath is the JSON file mapping complexity to generator
+	// parameters; see service.ParseDifficultyProfiles for the format.
+	DifficultyProfilesPath string `env:"DIFFICULTY_PROFILES_PATH" envDefault:"./difficulty.json"`
+
 	MaxTimeoutAttempts int32 `env:"MAX_TIMEOUT_ATTEMPTS" envDefault:"3"`
 
 	MinOverlapPct int32 `env:"MIN_OVERLAP_PCT" envDefault:"20"`
 
+	EventHistorySize   int32 `env:"EVENT_HISTORY_SIZE" envDefault:"256"`
+	MinTrajectoryScore int32 `env:"MIN_TRAJECTORY_SCORE" envDefault:"40"`
+	// RequireTrajectory rejects pointer answers sent without a recorded
+	// trajectory; turning it off only halves their confidence.
+	RequireTrajectory bool `env:"REQUIRE_TRAJECTORY" envDefault:"true"`
+
+	// Distortions per challenge type, at full strength for complexity 100;
+	// see imaging.ParseDistortionProfile for the format.
+	DistortionSliderPuzzle string `env:"DISTORTION_SLIDER_PUZZLE" envDefault:"from=20,decoys=2,soften=0.7,jitter=0.35,noise=0.4,warp=2.5,shadows=3"`
+	DistortionRotation     string `env:"DISTORTION_ROTATION" envDefault:"from=20,jitter=0.4,noise=0.5,warp=4,shadows=3"`
+	DistortionClickOrder   string `env:"DISTORTION_CLICK_ORDER" envDefault:"from=20,jitter=0.3,noise=0.4,warp=3,shadows=4"`
+
+	// PluginAddrs are gRPC addresses of out-of-process generators whose
+	// challenge types are registered next to the built-in one.
+	PluginAddrs             []string `env:"PLUGIN_ADDRS" envSeparator:","`
+	PluginTimeoutMs         int32    `env:"PLUGIN_TIMEOUT_MS" envDefault:"2000"`
+	PluginHealthIntervalSec int32    `env:"PLUGIN_HEALTH_INTERVAL_SEC" envDefault:"10"`
+
+	GameTickRate     int32 `env:"GAME_TICK_RATE" envDefault:"20"`
+	GameTimeLimitSec int32 `env:"GAME_TIME_LIMIT_SEC" envDefault:"30"`
+
 	CleanupInterval int32 `env:"CLEANUP_INTERVAL" envDefault:"300"`
 	StaleThreshold  int32 `env:"STALE_THRESHOLD" envDefault:"600"`
 
 	MaxAttempts      int32 `env:"MAX_ATTEMPTS" envDefault:"3"`
 	BlockDurationMin int32 `env:"BLOCK_DURATION_MINUTES" envDefault:"5"`
 
+	BackgroundsPath string `env:"BACKGROUNDS_PATH" envDefault:"./backgrounds/"`
+	// BackgroundMode is static (the PNGs only), procedural (synthesized) or
+	// blend (synthesized with a PNG mixed in at BackgroundPhotoBlend).
+	BackgroundMode       string  `env:"BACKGROUND_MODE" envDefault:"blend"`
+	BackgroundPhotoBlend float64 `env:"BACKGROUND_PHOTO_BLEND" envDefault:"0.3"`
+
+	// DebugSeeds records the seed of every challenge and lets challenge
+	// requests pass one back to replay it. The seed gives the answer away, so
+	// this is for debugging and audits only.
+	DebugSeeds bool `env:"DEBUG_SEEDS" envDefault:"false"`
+
+	// TokenSecret signs verification tokens. Instances verifying each other's
+	// tokens need the same one; without it every start picks a random secret.
+	TokenSecret string `env:"TOKEN_SECRET" envDefault:""`
+	TokenTTLSec int32  `env:"TOKEN_TTL_SEC" envDefault:"120"`
+
 	MinPort int32 `env:"MIN_PORT" envDefault:"38000"`
 	MaxPort int32 `env:"MAX_PORT" envDefault:"40000"`
 
+	ChallengeStore string `env:"CHALLENGE_STORE" envDefault:"memory"`
+	RedisAddr      string `env:"REDIS_ADDR" envDefault:"localhost:6379"`
+	RedisPassword  string `env:"REDIS_PASSWORD" envDefault:""`
+	RedisDB        int32  `env:"REDIS_DB" envDefault:"0"`
+	RedisKeyPrefix string `env:"REDIS_KEY_PREFIX" envDefault:"captcha:challenge:"`
+	// RedisTokenKeyPrefix keys the redeemed verification tokens.
+	RedisTokenKeyPrefix string `env:"REDIS_TOKEN_KEY_PREFIX" envDefault:"captcha:token:"`
+	RedisPoolSize       int32  `env:"REDIS_POOL_SIZE" envDefault:"16"`
+	RedisTimeoutMs      int32  `env:"REDIS_TIMEOUT_MS" envDefault:"500"`
+
+	PoolSize int32 `env:"POOL_SIZE" envDefault:"0"`
+	// PoolBucketWidth selects the pooled complexities: only multiples of it
+	// are pre-generated, other complexities are generated per request. Values
+	// below 1 pool every complexity, values above 100 only 0 and 100.
+	PoolBucketWidth int32 `env:"POOL_BUCKET_WIDTH" envDefault:"25"`
+	PoolWorkers     int32 `env:"POOL_WORKERS" envDefault:"0"`
+
 	MaxChallenges      int32  `env:"MAX_CHALLENGES" envDefault:"10000"`
 	MaxSessions        int32  `env:"MAX_SESSIONS" envDefault:"1000"`
 	ShutdownTimeoutSec int32  `env:"SHUTDOWN_TIMEOUT_SEC" envDefault:"30"`
 	BalancerAddr       string `env:"BALANCER_ADDR" envDefault:"localhost:9090"`
+}
+
+// removedSettings maps settings the service no longer reads to what replaced
+// them, so that a leftover override is reported instead of ignored.
+var removedSettings = map[string]string{
+	"COMPLEXITY_LOW":            "complexity points in DIFFICULTY_PROFILES_PATH",
+	"COMPLEXITY_HIGH":           "complexity points in DIFFICULTY_PROFILES_PATH",
+	"PUZZLE_SIZE_LOW":           "piece_size in DIFFICULTY_PROFILES_PATH, the puzzle piece rather than the canvas",
+	"PUZZLE_SIZE_MEDIUM":        "piece_size in DIFFICULTY_PROFILES_PATH, the puzzle piece rather than the canvas",
+	"PUZZLE_SIZE_HIGH":          "piece_size in DIFFICULTY_PROFILES_PATH, the puzzle piece rather than the canvas",
+	"TOLERANCE_LOW":             "tolerance_px in DIFFICULTY_PROFILES_PATH",
+	"TOLERANCE_MEDIUM":          "tolerance_px in DIFFICULTY_PROFILES_PATH",
+	"TOLERANCE_HIGH":            "tolerance_px in DIFFICULTY_PROFILES_PATH",
+	"EXPIRATION_TIME_LOW":       "expiration_sec in DIFFICULTY_PROFILES_PATH",
+	"EXPIRATION_TIME_MEDIUM":    "expiration_sec in DIFFICULTY_PROFILES_PATH",
+	"EXPIRATION_TIME_HIGH":      "expiration_sec in DIFFICULTY_PROFILES_PATH",
+	"MIN_TIME_MS":               "min_time_ms in DIFFICULTY_PROFILES_PATH",
+	"MAX_TIME_MS":               "max_time_ms in DIFFICULTY_PROFILES_PATH",
+	"ROTATION_TOLERANCE_LOW":    "tolerance_deg for rotation in DIFFICULTY_PROFILES_PATH",
+	"ROTATION_TOLERANCE_MEDIUM": "tolerance_deg for rotation in DIFFICULTY_PROFILES_PATH",
+	"ROTATION_TOLERANCE_HIGH":   "tolerance_deg for rotation in DIFFICULTY_PROFILES_PATH",
+	"DEFAULT_TARGET_X":          "a target drawn per challenge; the piece size is piece_size in DIFFICULTY_PROFILES_PATH",
+	"DEFAULT_TARGET_Y":          "a target drawn per challenge; the piece size is piece_size in DIFFICULTY_PROFILES_PATH",
+	"DEFAULT_TOLERANCE":         "tolerance_px for slider-puzzle in DIFFICULTY_PROFILES_PATH",
+	"DEFAULT_CONFIDENCE":        "a confidence scored from the distance to the target and min_time_ms/max_time_ms in DIFFICULTY_PROFILES_PATH",
+}
 
-	DefaultTargetX    int32 `env:"DEFAULT_TARGET_X" envDefault:"200"`
-	DefaultTargetY    int32 `env:"DEFAULT_TARGET_Y" envDefault:"150"`
-	DefaultTolerance  int32 `env:"DEFAULT_TOLERANCE" envDefault:"10"`
-	DefaultConfidence int32 `env:"DEFAULT_CONFIDENCE" envDefault:"85"`
+// RemovedSettings returns the settings set in the environment that the
+// service no longer reads, each with its replacement.
+func RemovedSettings() map[string]string {
+	set := make(map[string]string)
+	for name, replacement := range removedSettings {
+		if _, ok := os.LookupEnv(name); ok {
+			set[name] = replacement
+		}
+	}
+	return set
 }
 
 func LoadCaptchaServiceConfig() (*CaptchaConfig, error) {
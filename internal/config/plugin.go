@@ -0,0 +1,27 @@
+package config
+
+import (
+	"github.com/caarlos0/env/v11"
+)
+
+// PluginConfig configures the reference out-of-process generator.
+type PluginConfig struct {
+	Host string `env:"HOST" envDefault:"0.0.0.0"`
+	Port string `env:"PORT" envDefault:"50100"`
+
+	LogLevel string `env:"LOG_LEVEL" envDefault:"info"`
+
+	ExpirationSec int32 `env:"EXPIRATION_SEC" envDefault:"180"`
+	MinTimeMs     int64 `env:"MIN_TIME_MS" envDefault:"1500"`
+	MaxTimeMs     int64 `env:"MAX_TIME_MS" envDefault:"30000"`
+}
+
+func LoadPluginConfig() (*PluginConfig, error) {
+	config := &PluginConfig{}
+
+	if err := env.Parse(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
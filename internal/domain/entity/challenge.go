@@ -2,8 +2,10 @@ package entity
 
 import (
 	"captcha-service/internal/domain/dto"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -11,16 +13,27 @@ type ChallengeData interface {
 	GetType() string
 }
 
+// BackgroundSeeded is implemented by challenge data drawn over a generated
+// background; the seed is enough to render the same background again.
+type BackgroundSeeded interface {
+	GetBackgroundSeed() int64
+}
+
 type SliderPuzzleData struct {
-	ChallengeData dto.ChallengeData `json:"challenge"`
-	CanvasWidth   int               `json:"canvas_width"`
-	CanvasHeight  int               `json:"canvas_height"`
+	ChallengeData  dto.ChallengeData `json:"challenge"`
+	CanvasWidth    int               `json:"canvas_width"`
+	CanvasHeight   int               `json:"canvas_height"`
+	BackgroundSeed int64             `json:"background_seed"`
 }
 
 func (s SliderPuzzleData) GetType() string {
 	return ChallengeTypeSliderPuzzle
 }
 
+func (s SliderPuzzleData) GetBackgroundSeed() int64 {
+	return s.BackgroundSeed
+}
+
 type Position struct {
 	X int `json:"x"`
 	Y int `json:"y"`
@@ -50,6 +63,148 @@ func (d DragDropData) GetType() string {
 	return ChallengeTypeDragDrop
 }
 
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+func (r Rect) Contains(x, y float64) bool {
+	return x >= float64(r.X) && x <= float64(r.X+r.Width) && y >= float64(r.Y) && y <= float64(r.Y+r.Height)
+}
+
+type SteerGameData struct {
+	Start        Position `json:"start"`
+	Goal         Position `json:"goal"`
+	GoalRadius   int      `json:"goal_radius"`
+	ObjectRadius int      `json:"object_radius"`
+	Obstacles    []Rect   `json:"obstacles"`
+	CanvasWidth  int      `json:"canvas_width"`
+	CanvasHeight int      `json:"canvas_height"`
+}
+
+func (d SteerGameData) GetType() string {
+	return ChallengeTypeSteerGame
+}
+
+// RotationData keeps only the angle the disc was rendered at, clockwise in
+// degrees, and the background seed; the picture itself is not needed to
+// validate an answer.
+type RotationData struct {
+	Angle          int   `json:"angle"`
+	BackgroundSeed int64 `json:"background_seed"`
+}
+
+func (d RotationData) GetType() string {
+	return ChallengeTypeRotation
+}
+
+func (d RotationData) GetBackgroundSeed() int64 {
+	return d.BackgroundSeed
+}
+
+// ClickTarget is an icon the user has to click, with the radius around its
+// centre that counts as a hit. Icon is its index in ClickOrderData.Icons.
+type ClickTarget struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Radius int `json:"radius"`
+	Icon   int `json:"icon"`
+}
+
+// ClickIcon is an icon drawn on the click-order canvas.
+type ClickIcon struct {
+	Shape int     `json:"shape"`
+	Color int     `json:"color"`
+	X     int     `json:"x"`
+	Y     int     `json:"y"`
+	Size  int     `json:"size"`
+	Angle float64 `json:"angle"`
+}
+
+// ClickOrderData lists the targets in the order they must be clicked. All
+// icons, decoys included, are kept so the canvas can be redrawn when one of
+// them moves mid-solve; Moves is how many more times that may happen.
+type ClickOrderData struct {
+	Targets        []ClickTarget `json:"targets"`
+	Icons          []ClickIcon   `json:"icons,omitempty"`
+	Moves          int           `json:"moves,omitempty"`
+	CanvasWidth    int           `json:"canvas_width"`
+	CanvasHeight   int           `json:"canvas_height"`
+	BackgroundSeed int64         `json:"background_seed"`
+}
+
+func (d ClickOrderData) GetType() string {
+	return ChallengeTypeClickOrder
+}
+
+func (d ClickOrderData) GetBackgroundSeed() int64 {
+	return d.BackgroundSeed
+}
+
+// AudioData holds the digits encoded in the clip and the clip length, which is
+// the least time a listener needs before answering.
+type AudioData struct {
+	Code       string `json:"code"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+func (d AudioData) GetType() string {
+	return ChallengeTypeAudio
+}
+
+// PluginData is the opaque state an out-of-process generator returned for a
+// challenge. Plugin names the generator, which also tells stored plugin data
+// apart from the built-in types.
+type PluginData struct {
+	Type   string `json:"type"`
+	Plugin string `json:"plugin"`
+	State  []byte `json:"state"`
+}
+
+func (d PluginData) GetType() string {
+	return d.Type
+}
+
+// DecodeChallengeData restores the typed data of a challenge from its JSON
+// form, for stores that keep challenges outside the process.
+func DecodeChallengeData(challengeType string, raw json.RawMessage) (ChallengeData, error) {
+	switch challengeType {
+	case ChallengeTypeSliderPuzzle:
+		var data SliderPuzzleData
+		err := json.Unmarshal(raw, &data)
+		return data, err
+	case ChallengeTypeDragDrop:
+		var data DragDropData
+		err := json.Unmarshal(raw, &data)
+		return data, err
+	case ChallengeTypeSteerGame:
+		var data SteerGameData
+		err := json.Unmarshal(raw, &data)
+		return data, err
+	case ChallengeTypeRotation:
+		var data RotationData
+		err := json.Unmarshal(raw, &data)
+		return data, err
+	case ChallengeTypeClickOrder:
+		var data ClickOrderData
+		err := json.Unmarshal(raw, &data)
+		return data, err
+	case ChallengeTypeAudio:
+		var data AudioData
+		err := json.Unmarshal(raw, &data)
+		return data, err
+	default:
+		var data PluginData
+		if err := json.Unmarshal(raw, &data); err != nil || data.Plugin == "" {
+			return nil, ErrUnsupportedChallengeType
+		}
+		data.Type = challengeType
+		return data, nil
+	}
+}
+
 func (c *Challenge) GetSliderPuzzleData() (*SliderPuzzleData, error) {
 	data, ok := c.Data.(SliderPuzzleData)
 	if !ok {
@@ -87,6 +242,17 @@ type Challenge struct {
 	TimeoutAttempts    int32
 	MaxTimeoutAttempts int32
 	BlockedUntil       *time.Time
+
+	// Site is where the challenge is shown; verification tokens for it are
+	// only accepted for the same site.
+	Site string
+
+	// Seed reproduces the challenge; it is only recorded in debug mode.
+	Seed int64
+
+	// Events is the interaction history attached right before validation;
+	// it is never persisted with the challenge.
+	Events []BinaryEvent
 }
 
 var (
@@ -96,3 +262,16 @@ var (
 	ErrInvalidAnswer      = errors.New("invalid answer")
 	ErrMaxAttemptsReached = errors.New("max attempts reached")
 )
+
+// ChallengeIDSeparator splits the issuing instance from the rest of a
+// challenge ID, so a proxy can route answers back to that instance.
+const ChallengeIDSeparator = "."
+
+// IssuingInstance returns the instance encoded in a challenge ID, if any.
+func IssuingInstance(challengeID string) (string, bool) {
+	instanceID, _, found := strings.Cut(challengeID, ChallengeIDSeparator)
+	if !found || instanceID == "" {
+		return "", false
+	}
+	return instanceID, true
+}
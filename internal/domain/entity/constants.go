@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 )
@@ -14,6 +15,8 @@ const (
 	MessageTypeGRPCResponse       = "gRPC_response"
 	MessageTypeError              = "error"
 	MessageTypeBlocked            = "blocked"
+	MessageTypeServerData         = "server_data"
+	MessageTypeChallengeResult    = "challenge_result"
 )
 
 const (
@@ -77,6 +80,10 @@ const (
 const (
 	ChallengeTypeSliderPuzzle = "slider-puzzle"
 	ChallengeTypeDragDrop     = "drag-drop"
+	ChallengeTypeSteerGame    = "steer-game"
+	ChallengeTypeRotation     = "rotation"
+	ChallengeTypeClickOrder   = "click-order"
+	ChallengeTypeAudio        = "audio"
 )
 
 const (
@@ -109,8 +116,14 @@ const (
 	EventTypeSliderMove         = "slider_move"
 	EventTypeValidation         = "validation"
 	EventTypeSliderMovedStr     = "slider_moved"
+	EventTypeClickEventStr      = "click_event"
+	EventTypeDragMovedStr       = "drag_moved"
+	EventTypeEventFrameStr      = "event_frame"
+	EventTypeGameStart          = "game_start"
+	EventTypeGameInput          = "game_input"
 	EventTypeFieldEventType     = "eventType"
 	EventTypeValidationComplete = "validation_complete"
+	EventTypeRedraw             = "redraw"
 )
 
 const (
@@ -148,6 +161,15 @@ var PuzzleShapes = []string{
 
 var ErrWebSocketNotConnected = errors.New("websocket not connected")
 var ErrUserBlocked = errors.New("user blocked")
+var ErrUnsupportedChallengeType = errors.New("unsupported challenge type")
+var ErrNotInteractive = errors.New("challenge is not played over the event stream")
+var ErrGeneratorUnavailable = errors.New("challenge generator unavailable")
+var ErrUnknownEvent = errors.New("event not handled by challenge type")
+
+var ErrTokenInvalid = errors.New("invalid verification token")
+var ErrTokenExpired = errors.New("verification token expired")
+var ErrTokenUsed = errors.New("verification token already used")
+var ErrTokenSiteMismatch = errors.New("verification token issued for another site")
 
 type Instance struct {
 	ID           string    `json:"id"`
@@ -165,6 +187,7 @@ type BlockedUser struct {
 	Reason       string    `json:"reason"`
 	Attempts     int32     `json:"attempts"`
 	LastAttempt  time.Time `json:"last_attempt"`
+	BlockedAt    time.Time `json:"blocked_at"`
 }
 
 type RegisterInstanceRequest struct {
@@ -177,9 +200,14 @@ type RegisterInstanceRequest struct {
 }
 
 type WebSocketMessage struct {
-	Type   string                 `json:"type"`
-	UserID string                 `json:"user_id,omitempty"`
-	Data   map[string]interface{} `json:"data,omitempty"`
+	Type        string                 `json:"type"`
+	UserID      string                 `json:"user_id,omitempty"`
+	ChallengeID string                 `json:"challenge_id,omitempty"`
+	EventType   string                 `json:"event_type,omitempty"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	// EventData is the raw payload of a captcha event, which is not always an
+	// object (packed events arrive as arrays or base64 strings).
+	EventData json.RawMessage `json:"event_data,omitempty"`
 }
 
 type UserSession struct {
@@ -0,0 +1,160 @@
+package entity
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+//go:generate go run captcha-service/cmd/eventcodec-js -o ../../../templates/event_codec.js
+
+// Event frames carry pointer samples from the browser in batches. Layout of
+// version 1, all varints as in encoding/binary:
+//
+//	version  byte, EventFrameVersion
+//	count    uvarint, 1..MaxFrameEvents
+//	count times:
+//	  type   byte, a BinaryEventType
+//	  dt     uvarint, ms since the previous event; Unix ms for the first
+//	  dx, dy zigzag varints, change since the previous event; absolute for the first
+//
+// The browser side is generated from this file into templates/event_codec.js.
+const (
+	EventFrameVersion = 1
+	MaxFrameEvents    = 256
+
+	// MaxEventCoordinate bounds x and y, the largest canvas side supported.
+	MaxEventCoordinate = 8191
+	// maxEventTimestamp keeps timestamps exact as JavaScript numbers.
+	maxEventTimestamp = 1<<53 - 1
+
+	lastBinaryEventType = EventTypeDragMoved
+)
+
+// EncodeEventFrame packs events, in the order given, into one frame.
+func EncodeEventFrame(events []BinaryEvent) ([]byte, error) {
+	if len(events) == 0 || len(events) > MaxFrameEvents {
+		return nil, fmt.Errorf("%w: %d events in a frame", ErrInvalidBinaryData, len(events))
+	}
+
+	frame := make([]byte, 0, 2+len(events)*6)
+	frame = append(frame, EventFrameVersion)
+	frame = binary.AppendUvarint(frame, uint64(len(events)))
+
+	var previous BinaryEvent
+	for i, event := range events {
+		if event.Type < 0 || event.Type > lastBinaryEventType {
+			return nil, fmt.Errorf("%w: event type %d", ErrInvalidBinaryData, event.Type)
+		}
+		if event.X < 0 || event.X > MaxEventCoordinate || event.Y < 0 || event.Y > MaxEventCoordinate {
+			return nil, ErrEventOutOfRange
+		}
+		if event.Timestamp < previous.Timestamp || event.Timestamp > maxEventTimestamp {
+			return nil, fmt.Errorf("%w: event %d at %d", ErrEventOutOfOrder, i, event.Timestamp)
+		}
+
+		frame = append(frame, byte(event.Type))
+		frame = binary.AppendUvarint(frame, uint64(event.Timestamp-previous.Timestamp))
+		frame = binary.AppendVarint(frame, int64(event.X-previous.X))
+		frame = binary.AppendVarint(frame, int64(event.Y-previous.Y))
+		previous = event
+	}
+
+	return frame, nil
+}
+
+// DecodeEventFrame unpacks a frame sent for challengeID. Frames of another
+// version, with trailing bytes or with any event out of range are rejected
+// as a whole.
+func DecodeEventFrame(challengeID string, data []byte) ([]BinaryEvent, error) {
+	if len(data) == 0 || data[0] != EventFrameVersion {
+		return nil, fmt.Errorf("%w: unsupported frame version", ErrInvalidBinaryData)
+	}
+	r := frameReader{data: data[1:]}
+
+	count := r.uvarint()
+	if r.err != nil || count == 0 || count > MaxFrameEvents {
+		return nil, fmt.Errorf("%w: bad event count", ErrInvalidBinaryData)
+	}
+
+	events := make([]BinaryEvent, 0, count)
+	var previous BinaryEvent
+	for i := uint64(0); i < count; i++ {
+		eventType := BinaryEventType(r.byte())
+		dt := r.uvarint()
+		dx := r.varint()
+		dy := r.varint()
+		if r.err != nil {
+			return nil, fmt.Errorf("%w: event %d truncated", ErrInvalidBinaryData, i)
+		}
+		if eventType > lastBinaryEventType {
+			return nil, fmt.Errorf("%w: event type %d", ErrInvalidBinaryData, eventType)
+		}
+		if dt > maxEventTimestamp-uint64(previous.Timestamp) {
+			return nil, fmt.Errorf("%w: event %d timestamp overflows", ErrInvalidBinaryData, i)
+		}
+
+		x := int64(previous.X) + dx
+		y := int64(previous.Y) + dy
+		if dx < -MaxEventCoordinate || dx > MaxEventCoordinate || dy < -MaxEventCoordinate || dy > MaxEventCoordinate ||
+			x < 0 || x > MaxEventCoordinate || y < 0 || y > MaxEventCoordinate {
+			return nil, ErrEventOutOfRange
+		}
+
+		event := BinaryEvent{
+			ChallengeID: challengeID,
+			Type:        eventType,
+			X:           int32(x),
+			Y:           int32(y),
+			Timestamp:   previous.Timestamp + int64(dt),
+		}
+		events = append(events, event)
+		previous = event
+	}
+
+	if len(r.data) > 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidBinaryData, len(r.data))
+	}
+	return events, nil
+}
+
+// frameReader reads varints from a frame and remembers the first failure.
+type frameReader struct {
+	data []byte
+	err  error
+}
+
+func (r *frameReader) byte() byte {
+	if r.err != nil || len(r.data) == 0 {
+		r.err = ErrInvalidBinaryData
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+func (r *frameReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = ErrInvalidBinaryData
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *frameReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = ErrInvalidBinaryData
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
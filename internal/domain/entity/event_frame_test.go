@@ -0,0 +1,142 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventFrameRoundTrip(t *testing.T) {
+	cases := map[string][]BinaryEvent{
+		"single click": {
+			{Type: EventTypeClick, X: 120, Y: 45, Timestamp: 1700000000123},
+		},
+		"slider drag": {
+			{Type: EventTypeSliderMoved, X: 0, Timestamp: 1700000000000},
+			{Type: EventTypeSliderMoved, X: 3, Timestamp: 1700000000016},
+			{Type: EventTypeSliderMoved, X: 11, Timestamp: 1700000000033},
+			{Type: EventTypeSliderMoved, X: 9, Timestamp: 1700000000033},
+		},
+		"drag and drop": {
+			{Type: EventTypeDragStart, X: 40, Y: 200, Timestamp: 5},
+			{Type: EventTypeDragMoved, X: 8191, Y: 0, Timestamp: 6},
+			{Type: EventTypeDragMoved, X: 0, Y: 8191, Timestamp: 6},
+			{Type: EventTypeDragEnd, X: 0, Y: 8191, Timestamp: 1<<53 - 1},
+		},
+	}
+
+	for name, events := range cases {
+		t.Run(name, func(t *testing.T) {
+			frame, err := EncodeEventFrame(events)
+			require.NoError(t, err)
+
+			decoded, err := DecodeEventFrame("challenge", frame)
+			require.NoError(t, err)
+			require.Len(t, decoded, len(events))
+			for i := range events {
+				events[i].ChallengeID = "challenge"
+			}
+			assert.Equal(t, events, decoded)
+		})
+	}
+}
+
+func TestEventFrameBatchesCompactly(t *testing.T) {
+	events := make([]BinaryEvent, MaxFrameEvents)
+	for i := range events {
+		events[i] = BinaryEvent{Type: EventTypeSliderMoved, X: int32(i), Timestamp: 1700000000000 + int64(i)*16}
+	}
+
+	frame, err := EncodeEventFrame(events)
+	require.NoError(t, err)
+	// After the first event every sample is a type byte and three one-byte deltas.
+	assert.LessOrEqual(t, len(frame), 3+8+4*MaxFrameEvents)
+
+	_, err = EncodeEventFrame(append(events, events[0]))
+	assert.ErrorIs(t, err, ErrInvalidBinaryData)
+}
+
+// The same frame is produced by templates/event_codec.js; keep them in step.
+func TestEventFrameLayout(t *testing.T) {
+	frame, err := EncodeEventFrame([]BinaryEvent{
+		{Type: EventTypeClick, X: 10, Y: 20, Timestamp: 300},
+		{Type: EventTypeDragMoved, X: 4, Y: 84, Timestamp: 301},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{
+		EventFrameVersion, 2,
+		1, 0xac, 0x02, 20, 40,
+		7, 1, 11, 0x80, 0x01,
+	}, frame)
+}
+
+func TestEncodeEventFrameRejects(t *testing.T) {
+	cases := map[string][]BinaryEvent{
+		"empty":        {},
+		"unknown type": {{Type: 42, Timestamp: 1}},
+		"negative x":   {{Type: EventTypeClick, X: -1, Timestamp: 1}},
+		"too far":      {{Type: EventTypeClick, Y: MaxEventCoordinate + 1, Timestamp: 1}},
+		"back in time": {{Type: EventTypeClick, Timestamp: 10}, {Type: EventTypeClick, Timestamp: 9}},
+		"huge time":    {{Type: EventTypeClick, Timestamp: 1 << 53}},
+	}
+
+	for name, events := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := EncodeEventFrame(events)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeEventFrameRejects(t *testing.T) {
+	valid, err := EncodeEventFrame([]BinaryEvent{{Type: EventTypeClick, X: 1, Y: 2, Timestamp: 3}})
+	require.NoError(t, err)
+
+	cases := map[string][]byte{
+		"empty":          nil,
+		"old click":      {0, 0, 0, 0, 0, 0, 0, 1},
+		"other version":  append([]byte{2}, valid[1:]...),
+		"zero events":    {EventFrameVersion, 0},
+		"too many":       {EventFrameVersion, 0x81, 0x02},
+		"truncated":      valid[:len(valid)-1],
+		"trailing bytes": append(append([]byte{}, valid...), 0),
+		"unknown type":   {EventFrameVersion, 1, 42, 0, 0, 0},
+		"negative x":     {EventFrameVersion, 1, 1, 0, 1, 0},
+		"x overflow":     {EventFrameVersion, 1, 1, 0, 0x80, 0x80, 0x01, 0},
+		"time overflow":  {EventFrameVersion, 2, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x0f, 0, 0, 1, 1, 0, 0},
+	}
+
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodeEventFrame("challenge", data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func FuzzDecodeEventFrame(f *testing.F) {
+	seed, _ := EncodeEventFrame([]BinaryEvent{
+		{Type: EventTypeSliderMoved, X: 10, Timestamp: 1700000000000},
+		{Type: EventTypeSliderMoved, X: 14, Timestamp: 1700000000016},
+	})
+	f.Add(seed)
+	f.Add([]byte{EventFrameVersion, 1, 1, 0, 0, 0})
+	f.Add([]byte{EventFrameVersion, 0x80, 0x02})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		events, err := DecodeEventFrame("challenge", data)
+		if err != nil {
+			return
+		}
+		require.NotEmpty(t, events)
+		require.LessOrEqual(t, len(events), MaxFrameEvents)
+
+		// Anything the decoder accepts must survive a round trip unchanged.
+		frame, err := EncodeEventFrame(events)
+		require.NoError(t, err)
+		again, err := DecodeEventFrame("challenge", frame)
+		require.NoError(t, err)
+		require.Equal(t, events, again)
+	})
+}
@@ -3,6 +3,7 @@ package interfaces
 import (
 	"captcha-service/internal/domain/entity"
 	"context"
+	"time"
 )
 
 type CaptchaService interface {
@@ -18,7 +19,7 @@ type GeneratorRegistry interface {
 
 type ChallengeGenerator interface {
 	Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error)
-	Validate(answer interface{}, data interface{}) (bool, int32, error)
+	Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error)
 }
 
 type EventProcessor interface {
@@ -33,6 +34,7 @@ type ChallengeRepository interface {
 	SaveChallenge(ctx context.Context, challenge *entity.Challenge) error
 	GetChallenge(ctx context.Context, challengeID string) (*entity.Challenge, error)
 	DeleteChallenge(ctx context.Context, challengeID string) error
+	UpdateChallenge(ctx context.Context, challengeID string, update func(challenge *entity.Challenge) (remove bool, err error)) error
 }
 
 type WebSocketSender interface {
@@ -52,7 +54,7 @@ type UserBlockRepository interface {
 	RemoveBlockedUser(userID string) error
 	GetAllBlockedUsers() ([]*entity.BlockedUser, error)
 	IsUserBlocked(userID string) bool
-	BlockUser(userID string, reason string) error
+	BlockUser(userID string, duration time.Duration, reason string) error
 	CleanupExpiredBlocks() error
 }
 
@@ -87,6 +89,8 @@ type BalancerService interface {
 	GetInstances() ([]*entity.Instance, error)
 	IsUserBlocked(userID string) bool
 	BlockUser(userID string, reason string) error
+	UnblockUser(userID string) error
+	GetBlockedUsers() ([]*entity.BlockedUser, error)
 	StartCleanup()
 	Stop()
 }
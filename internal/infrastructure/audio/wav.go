@@ -0,0 +1,41 @@
+package audio
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+)
+
+// EncodeWAV writes samples in [-1, 1] as 8-bit mono PCM, which every browser
+// plays and which is half the size of 16-bit for beeps and noise.
+func EncodeWAV(samples []float64, sampleRate int) []byte {
+	const headerSize = 44
+
+	buf := make([]byte, headerSize+len(samples))
+	copy(buf[0:], "RIFF")
+	binary.LittleEndian.PutUint32(buf[4:], uint32(headerSize-8+len(samples)))
+	copy(buf[8:], "WAVE")
+
+	copy(buf[12:], "fmt ")
+	binary.LittleEndian.PutUint32(buf[16:], 16)
+	binary.LittleEndian.PutUint16(buf[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(buf[22:], 1) // mono
+	binary.LittleEndian.PutUint32(buf[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(buf[28:], uint32(sampleRate)) // byte rate
+	binary.LittleEndian.PutUint16(buf[32:], 1)                  // block align
+	binary.LittleEndian.PutUint16(buf[34:], 8)                  // bits per sample
+
+	copy(buf[36:], "data")
+	binary.LittleEndian.PutUint32(buf[40:], uint32(len(samples)))
+
+	for i, s := range samples {
+		s = math.Max(-1, math.Min(1, s))
+		buf[headerSize+i] = uint8(math.Round(127.5 + s*127.5))
+	}
+
+	return buf
+}
+
+func EncodeWAVDataURI(samples []float64, sampleRate int) string {
+	return "data:audio/wav;base64," + base64.StdEncoding.EncodeToString(EncodeWAV(samples, sampleRate))
+}
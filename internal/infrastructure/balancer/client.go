@@ -9,7 +9,6 @@ import (
 
 	protoBalancer "captcha-service/gen/proto/proto/balancer"
 	"captcha-service/internal/config"
-	"captcha-service/internal/domain/entity"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
@@ -43,6 +42,12 @@ func NewClient(cfg *config.CaptchaConfig) *Client {
 	}
 }
 
+// InstanceID is the ID the instance registers with; challenge IDs carry it so
+// the proxy can route answers back here.
+func (c *Client) InstanceID() string {
+	return c.instanceID
+}
+
 func (c *Client) Connect(ctx context.Context) error {
 	balancerAddr := c.config.BalancerAddress
 	if balancerAddr == "" {
@@ -78,7 +83,7 @@ func (c *Client) sendReadyEvent() error {
 	req := &protoBalancer.RegisterInstanceRequest{
 		EventType:     protoBalancer.RegisterInstanceRequest_READY,
 		InstanceId:    c.instanceID,
-		ChallengeType: entity.ChallengeTypeSliderPuzzleReg,
+		ChallengeType: c.config.ChallengeType,
 		Host:          c.host,
 		PortNumber:    c.port,
 		Timestamp:     time.Now().Unix(),
@@ -95,7 +100,7 @@ func (c *Client) sendStoppedEvent() error {
 	req := &protoBalancer.RegisterInstanceRequest{
 		EventType:     protoBalancer.RegisterInstanceRequest_STOPPED,
 		InstanceId:    c.instanceID,
-		ChallengeType: entity.ChallengeTypeSliderPuzzleReg,
+		ChallengeType: c.config.ChallengeType,
 		Host:          c.host,
 		PortNumber:    c.port,
 		Timestamp:     time.Now().Unix(),
@@ -1,26 +1,189 @@
 package event_processing
 
 import (
-	"context"
-	"log"
+	"sync"
+	"time"
 
+	"captcha-service/internal/config"
 	"captcha-service/internal/domain/entity"
-	"captcha-service/internal/repository"
+	"captcha-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	timestampMask = 0x3FFFFFFFFF
+	coordinateMax = 0x1FFF
 )
 
+// eventRing keeps the most recent events of one challenge. Every event is
+// packed like a click frame (13-bit x, 13-bit y, 38-bit ms timestamp) into a
+// single uint64, plus one byte for its type.
+type eventRing struct {
+	packed        []uint64
+	types         []uint8
+	start         int
+	lastTimestamp int64
+	lastSeen      time.Time
+}
+
+func packEvent(event *entity.BinaryEvent) uint64 {
+	return uint64(event.X)<<51 | uint64(event.Y)<<38 | uint64(event.Timestamp)&timestampMask
+}
+
+func (r *eventRing) push(value uint64, eventType entity.BinaryEventType, capacity int) {
+	if len(r.packed) < capacity {
+		r.packed = append(r.packed, value)
+		r.types = append(r.types, uint8(eventType))
+		return
+	}
+
+	r.packed[r.start] = value
+	r.types[r.start] = uint8(eventType)
+	r.start = (r.start + 1) % capacity
+}
+
+// isReplay reports whether the exact frame is already among the events that
+// share the newest timestamp; older ones are caught by the ordering check.
+func (r *eventRing) isReplay(value uint64, eventType entity.BinaryEventType) bool {
+	for i := len(r.packed) - 1; i >= 0; i-- {
+		idx := (r.start + i) % len(r.packed)
+		if int64(r.packed[idx]&timestampMask) != r.lastTimestamp {
+			return false
+		}
+		if r.packed[idx] == value && r.types[idx] == uint8(eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *eventRing) events(challengeID string) []entity.BinaryEvent {
+	events := make([]entity.BinaryEvent, len(r.packed))
+	for i := range r.packed {
+		idx := (r.start + i) % len(r.packed)
+		value := r.packed[idx]
+		events[i] = entity.BinaryEvent{
+			ChallengeID: challengeID,
+			Type:        entity.BinaryEventType(r.types[idx]),
+			X:           int32(value >> 51 & coordinateMax),
+			Y:           int32(value >> 38 & coordinateMax),
+			Timestamp:   int64(value & timestampMask),
+		}
+	}
+	return events
+}
+
 type EventProcessorService struct {
-	repository     repository.CaptchaPort
-	eventPublisher *EventPublisherService
+	rings         map[string]*eventRing
+	mu            sync.RWMutex
+	config        *config.CaptchaConfig
+	cleanupTicker *time.Ticker
+	stopChan      chan struct{}
 }
 
-func NewEventProcessorService(repo repository.CaptchaPort, eventPublisher *EventPublisherService) *EventProcessorService {
-	return &EventProcessorService{
-		repository:     repo,
-		eventPublisher: eventPublisher,
+func NewEventProcessorService(config *config.CaptchaConfig) *EventProcessorService {
+	processor := &EventProcessorService{
+		rings:    make(map[string]*eventRing),
+		config:   config,
+		stopChan: make(chan struct{}),
 	}
+
+	processor.startCleanup()
+	return processor
 }
 
-func (e *EventProcessorService) ProcessEvent(ctx context.Context, event *entity.BinaryEvent) error {
-	log.Printf("Processing event: %+v", event)
-	return nil
+func (e *EventProcessorService) ProcessEvent(event *entity.BinaryEvent) (*entity.EventResult, error) {
+	if event.X < 0 || event.X > entity.CanvasWidth || event.Y < 0 || event.Y > entity.CanvasHeight {
+		return entity.NewEventResult(false, "Coordinates out of range", nil), entity.ErrEventOutOfRange
+	}
+
+	timestamp := event.Timestamp & timestampMask
+	value := packEvent(event)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ring, exists := e.rings[event.ChallengeID]
+	if !exists {
+		if len(e.rings) >= int(e.config.MaxChallenges) {
+			return entity.NewEventResult(false, "Too many active challenges", nil), entity.ErrEventHistoryFull
+		}
+		ring = &eventRing{}
+		e.rings[event.ChallengeID] = ring
+	}
+
+	if len(ring.packed) > 0 {
+		if timestamp < ring.lastTimestamp {
+			return entity.NewEventResult(false, "Event out of order", nil), entity.ErrEventOutOfOrder
+		}
+		if timestamp == ring.lastTimestamp && ring.isReplay(value, event.Type) {
+			return entity.NewEventResult(false, "Event replayed", nil), entity.ErrEventReplay
+		}
+	}
+
+	ring.push(value, event.Type, max(1, int(e.config.EventHistorySize)))
+	ring.lastTimestamp = timestamp
+	ring.lastSeen = time.Now()
+
+	return entity.NewEventResult(true, "", map[string]interface{}{
+		"events": len(ring.packed),
+	}), nil
+}
+
+func (e *EventProcessorService) History(challengeID string) []entity.BinaryEvent {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	ring, exists := e.rings[challengeID]
+	if !exists {
+		return nil
+	}
+	return ring.events(challengeID)
+}
+
+func (e *EventProcessorService) Forget(challengeID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.rings, challengeID)
+}
+
+func (e *EventProcessorService) startCleanup() {
+	interval := time.Duration(e.config.CleanupInterval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	e.cleanupTicker = time.NewTicker(interval)
+
+	go func() {
+		for {
+			select {
+			case <-e.cleanupTicker.C:
+				e.cleanup()
+			case <-e.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+func (e *EventProcessorService) cleanup() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	threshold := time.Now().Add(-time.Duration(e.config.StaleThreshold) * time.Second)
+	for challengeID, ring := range e.rings {
+		if ring.lastSeen.Before(threshold) {
+			delete(e.rings, challengeID)
+			logger.Debug("Removed stale event history", zap.String("challengeID", challengeID))
+		}
+	}
+}
+
+func (e *EventProcessorService) Stop() {
+	if e.cleanupTicker != nil {
+		e.cleanupTicker.Stop()
+	}
+	close(e.stopChan)
 }
@@ -0,0 +1,83 @@
+package event_processing
+
+import (
+	"testing"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProcessor(t *testing.T, historySize, maxChallenges int32) *EventProcessorService {
+	t.Helper()
+	processor := NewEventProcessorService(&config.CaptchaConfig{
+		EventHistorySize: historySize,
+		MaxChallenges:    maxChallenges,
+	})
+	t.Cleanup(processor.Stop)
+	return processor
+}
+
+func move(challengeID string, x int32, timestamp int64) *entity.BinaryEvent {
+	return &entity.BinaryEvent{ChallengeID: challengeID, Type: entity.EventTypeSliderMoved, X: x, Y: 20, Timestamp: timestamp}
+}
+
+func TestEventRingKeepsNewestEvents(t *testing.T) {
+	processor := newTestProcessor(t, 4, 10)
+
+	for i := int32(0); i < 6; i++ {
+		_, err := processor.ProcessEvent(move("challenge-1", 10*i, 1000+int64(i)))
+		require.NoError(t, err)
+	}
+
+	history := processor.History("challenge-1")
+	require.Len(t, history, 4)
+	for i, event := range history {
+		assert.Equal(t, int32(10*(i+2)), event.X, "oldest events are evicted first")
+		assert.Equal(t, int64(1002+i), event.Timestamp)
+		assert.Equal(t, int32(20), event.Y)
+		assert.Equal(t, "challenge-1", event.ChallengeID)
+	}
+
+	processor.Forget("challenge-1")
+	assert.Empty(t, processor.History("challenge-1"))
+}
+
+func TestEventRingRejectsReorderedAndReplayedEvents(t *testing.T) {
+	processor := newTestProcessor(t, 4, 10)
+
+	_, err := processor.ProcessEvent(move("challenge-1", 10, 1000))
+	require.NoError(t, err)
+
+	_, err = processor.ProcessEvent(move("challenge-1", 20, 999))
+	assert.ErrorIs(t, err, entity.ErrEventOutOfOrder)
+
+	_, err = processor.ProcessEvent(move("challenge-1", 10, 1000))
+	assert.ErrorIs(t, err, entity.ErrEventReplay)
+
+	_, err = processor.ProcessEvent(move("challenge-1", 30, 1000))
+	assert.NoError(t, err, "another event in the same millisecond")
+}
+
+func TestEventHistoryCapacity(t *testing.T) {
+	processor := newTestProcessor(t, 4, 2)
+
+	for _, id := range []string{"challenge-1", "challenge-2"} {
+		_, err := processor.ProcessEvent(move(id, 10, 1000))
+		require.NoError(t, err)
+	}
+
+	result, err := processor.ProcessEvent(move("challenge-3", 10, 1000))
+	assert.ErrorIs(t, err, entity.ErrEventHistoryFull)
+	assert.False(t, result.Success)
+	assert.Empty(t, processor.History("challenge-3"))
+
+	_, err = processor.ProcessEvent(move("challenge-1", 20, 1001))
+	assert.NoError(t, err, "challenges already recording keep their history")
+
+	processor.Forget("challenge-2")
+	_, err = processor.ProcessEvent(move("challenge-3", 10, 1000))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,181 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"captcha-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+type BackgroundStore struct {
+	images []*image.RGBA
+	width  int
+	height int
+}
+
+func NewBackgroundStore(dir string, width, height int) (*BackgroundStore, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backgrounds dir %s: %w", dir, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".png") {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	store := &BackgroundStore{
+		images: make([]*image.RGBA, 0, len(names)),
+		width:  width,
+		height: height,
+	}
+
+	for _, name := range names {
+		img, err := loadPNG(filepath.Join(dir, name))
+		if err != nil {
+			logger.Error("Failed to load background", zap.String("file", name), zap.Error(err))
+			continue
+		}
+		store.images = append(store.images, resize(img, width, height))
+		logger.Debug("Loaded background", zap.String("file", name))
+	}
+
+	if len(store.images) == 0 {
+		return nil, fmt.Errorf("no backgrounds found in %s", dir)
+	}
+
+	return store, nil
+}
+
+func (s *BackgroundStore) Len() int {
+	return len(s.images)
+}
+
+// Get returns a private copy of the i-th background, so callers may draw on it.
+func (s *BackgroundStore) Get(i int) *image.RGBA {
+	src := s.images[i%len(s.images)]
+	dst := image.NewRGBA(src.Bounds())
+	copy(dst.Pix, src.Pix)
+	return dst
+}
+
+const (
+	BackgroundModeStatic     = "static"
+	BackgroundModeProcedural = "procedural"
+	BackgroundModeBlend      = "blend"
+)
+
+type BackgroundOptions struct {
+	Mode string
+	// PhotoBlend is the opacity of the photo over the synthetic image in
+	// blend mode.
+	PhotoBlend float64
+}
+
+// Backgrounds builds the background of every challenge from a seed alone, so
+// an image can be regenerated for audit without keeping its pixels.
+type Backgrounds struct {
+	options BackgroundOptions
+	photos  *BackgroundStore
+	width   int
+	height  int
+}
+
+func NewBackgrounds(dir string, width, height int, options BackgroundOptions) (*Backgrounds, error) {
+	b := &Backgrounds{options: options, width: width, height: height}
+
+	switch options.Mode {
+	case BackgroundModeProcedural:
+	case BackgroundModeStatic, BackgroundModeBlend:
+		photos, err := NewBackgroundStore(dir, width, height)
+		if err != nil {
+			return nil, err
+		}
+		b.photos = photos
+	default:
+		return nil, fmt.Errorf("unknown background mode %q", options.Mode)
+	}
+
+	return b, nil
+}
+
+// Pick draws a seed from rng and returns the background for it.
+func (b *Backgrounds) Pick(rng *rand.Rand) (*image.RGBA, int64) {
+	seed := rng.Int63()
+	return b.Render(seed), seed
+}
+
+func (b *Backgrounds) Render(seed int64) *image.RGBA {
+	if b.options.Mode == BackgroundModeStatic {
+		return b.photos.Get(rand.New(rand.NewSource(seed)).Intn(b.photos.Len()))
+	}
+
+	img := synthesizeBackground(seed, b.width, b.height)
+	if b.options.Mode == BackgroundModeBlend {
+		photo := b.photos.Get(int(uint64(seed) % uint64(b.photos.Len())))
+		blendPhoto(img, photo, b.options.PhotoBlend)
+	}
+	return img
+}
+
+func loadPNG(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return png.Decode(f)
+}
+
+// resize scales img to width x height by averaging the source pixels that
+// fall into every destination pixel, which keeps large photos from aliasing.
+func resize(img image.Image, width, height int) *image.RGBA {
+	src := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * sh / height
+		y1 := max((y+1)*sh/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * sw / width
+			x1 := max((x+1)*sw/width, x0+1)
+
+			var r, g, b, n int
+			for sy := y0; sy < y1; sy++ {
+				off := sy*src.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[off])
+					g += int(src.Pix[off+1])
+					b += int(src.Pix[off+2])
+					off += 4
+					n++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xFF
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,75 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testWidth  = 64
+	testHeight = 48
+)
+
+var testPhotoColor = color.RGBA{R: 200, G: 40, B: 90, A: 0xFF}
+
+// photoDir holds a single plain photo in the test size.
+func photoDir(t *testing.T) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, testWidth, testHeight))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = testPhotoColor.R, testPhotoColor.G, testPhotoColor.B, testPhotoColor.A
+	}
+
+	dir := t.TempDir()
+	f, err := os.Create(filepath.Join(dir, "plain.png"))
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, png.Encode(f, img))
+	return dir
+}
+
+func newTestBackgrounds(t *testing.T, options BackgroundOptions) *Backgrounds {
+	t.Helper()
+	backgrounds, err := NewBackgrounds(photoDir(t), testWidth, testHeight, options)
+	require.NoError(t, err)
+	return backgrounds
+}
+
+func TestBackgroundsReplaySeed(t *testing.T) {
+	for _, mode := range []string{BackgroundModeProcedural, BackgroundModeBlend} {
+		t.Run(mode, func(t *testing.T) {
+			backgrounds := newTestBackgrounds(t, BackgroundOptions{Mode: mode, PhotoBlend: 0.3})
+
+			first := backgrounds.Render(42)
+			assert.Equal(t, first.Pix, backgrounds.Render(42).Pix, "the same seed draws the same pixels")
+			assert.NotEqual(t, first.Pix, backgrounds.Render(43).Pix, "another seed draws another background")
+		})
+	}
+}
+
+func TestBackgroundsPhotoBlend(t *testing.T) {
+	synthetic := newTestBackgrounds(t, BackgroundOptions{Mode: BackgroundModeProcedural}).Render(7)
+
+	transparent := newTestBackgrounds(t, BackgroundOptions{Mode: BackgroundModeBlend, PhotoBlend: 0})
+	assert.Equal(t, synthetic.Pix, transparent.Render(7).Pix, "PHOTO_BLEND=0 leaves the synthetic background")
+
+	opaque := newTestBackgrounds(t, BackgroundOptions{Mode: BackgroundModeBlend, PhotoBlend: 1}).Render(7)
+	for y := 0; y < testHeight; y++ {
+		for x := 0; x < testWidth; x++ {
+			require.Equal(t, testPhotoColor, opaque.RGBAAt(x, y), "PHOTO_BLEND=1 shows only the photo at %d,%d", x, y)
+		}
+	}
+}
+
+func TestBackgroundsUnknownMode(t *testing.T) {
+	_, err := NewBackgrounds(t.TempDir(), testWidth, testHeight, BackgroundOptions{Mode: "photo"})
+	assert.EqualError(t, err, `unknown background mode "photo"`)
+}
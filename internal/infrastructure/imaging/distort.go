@@ -0,0 +1,214 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Distortion is the set of adversarial effects applied to one rendering.
+// Zero values disable an effect.
+type Distortion struct {
+	// DecoyHoles are extra holes of other shapes punched into the slider
+	// background.
+	DecoyHoles int
+	// Soften blurs the outline of holes, 0 to 1.
+	Soften float64
+	// ColorJitter shifts the colour balance of the background and the piece
+	// independently, 0 to 1.
+	ColorJitter float64
+	// Noise is per-pixel noise, 0 to 1.
+	Noise float64
+	// Warp is the amplitude in pixels of a wave displacement of the image.
+	Warp float64
+	// Shadows are soft dark smudges shaped like pieces or icons.
+	Shadows int
+}
+
+// DistortionProfile gives the full-strength Distortion of a challenge type
+// and the complexity From which effects start; in between they grow linearly.
+type DistortionProfile struct {
+	From int32
+	Max  Distortion
+}
+
+// ParseDistortionProfile reads a comma separated list such as
+// "from=20,decoys=3,soften=0.7,jitter=0.3,noise=0.4,warp=3,shadows=2".
+func ParseDistortionProfile(spec string) (DistortionProfile, error) {
+	var profile DistortionProfile
+
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return profile, fmt.Errorf("distortion %q is not key=value", field)
+		}
+
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || number < 0 {
+			return profile, fmt.Errorf("distortion %q has invalid value", field)
+		}
+
+		switch strings.TrimSpace(key) {
+		case "from":
+			profile.From = int32(math.Min(number, 100))
+		case "decoys":
+			profile.Max.DecoyHoles = int(number)
+		case "soften":
+			profile.Max.Soften = math.Min(number, 1)
+		case "jitter":
+			profile.Max.ColorJitter = math.Min(number, 1)
+		case "noise":
+			profile.Max.Noise = math.Min(number, 1)
+		case "warp":
+			profile.Max.Warp = number
+		case "shadows":
+			profile.Max.Shadows = int(number)
+		default:
+			return profile, fmt.Errorf("unknown distortion %q", key)
+		}
+	}
+
+	return profile, nil
+}
+
+// At scales the profile to a complexity between 0 and 100.
+func (p DistortionProfile) At(complexity int32) Distortion {
+	if complexity <= p.From || p.From >= 100 {
+		return Distortion{}
+	}
+	k := math.Min(1, float64(complexity-p.From)/float64(100-p.From))
+
+	return Distortion{
+		DecoyHoles:  int(math.Round(float64(p.Max.DecoyHoles) * k)),
+		Soften:      p.Max.Soften * k,
+		ColorJitter: p.Max.ColorJitter * k,
+		Noise:       p.Max.Noise * k,
+		Warp:        p.Max.Warp * k,
+		Shadows:     int(math.Round(float64(p.Max.Shadows) * k)),
+	}
+}
+
+// jitterColors applies a random gain and offset to every channel, so pixels
+// cut from one image no longer match the other exactly.
+func jitterColors(pix []uint8, rng *rand.Rand, amount float64) {
+	if amount <= 0 {
+		return
+	}
+
+	var gain, offset [3]float64
+	for c := range gain {
+		gain[c] = 1 + (rng.Float64()*2-1)*0.15*amount
+		offset[c] = (rng.Float64()*2 - 1) * 25 * amount
+	}
+
+	for i := 0; i < len(pix); i += 4 {
+		for c := 0; c < 3; c++ {
+			pix[i+c] = clampByte(float64(pix[i+c])*gain[c] + offset[c])
+		}
+	}
+}
+
+func addNoise(pix []uint8, rng *rand.Rand, amount float64) {
+	if amount <= 0 {
+		return
+	}
+
+	spread := 40 * amount
+	for i := 0; i < len(pix); i += 4 {
+		n := (rng.Float64() + rng.Float64() - 1) * spread
+		for c := 0; c < 3; c++ {
+			pix[i+c] = clampByte(float64(pix[i+c]) + n)
+		}
+	}
+}
+
+// warpImage displaces pixels along two crossing sine waves of random phase.
+func warpImage(img *image.RGBA, rng *rand.Rand, amplitude float64) *image.RGBA {
+	if amplitude <= 0 {
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	periodX := 60 + rng.Float64()*80
+	periodY := 60 + rng.Float64()*80
+	phaseX, phaseY := rng.Float64()*2*math.Pi, rng.Float64()*2*math.Pi
+
+	dst := image.NewRGBA(img.Bounds())
+	for y := 0; y < height; y++ {
+		dx := amplitude * math.Sin(2*math.Pi*float64(y)/periodY+phaseY)
+		for x := 0; x < width; x++ {
+			dy := amplitude * math.Sin(2*math.Pi*float64(x)/periodX+phaseX)
+
+			i := y*dst.Stride + x*4
+			sampleBilinear(img, float64(x)+dx, float64(y)+dy, dst.Pix[i:i+3])
+			dst.Pix[i+3] = 0xFF
+		}
+	}
+	return dst
+}
+
+// blurMask is a box blur of the given radius, used to soften outlines.
+func blurMask(mask *image.Alpha, radius int) *image.Alpha {
+	if radius <= 0 {
+		return mask
+	}
+
+	side := mask.Bounds().Dx()
+	dst := image.NewAlpha(mask.Bounds())
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			var sum, n int
+			for sy := max(0, y-radius); sy <= min(side-1, y+radius); sy++ {
+				for sx := max(0, x-radius); sx <= min(side-1, x+radius); sx++ {
+					sum += int(mask.Pix[sy*mask.Stride+sx])
+					n++
+				}
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(sum / n)
+		}
+	}
+	return dst
+}
+
+// paintShadow darkens the area under a blurred mask.
+func paintShadow(img *image.RGBA, mask *image.Alpha, offsetX, offsetY int, strength float64) {
+	side := mask.Bounds().Dx()
+	bounds := img.Bounds()
+
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			a := float64(mask.Pix[y*mask.Stride+x]) / 0xFF * strength
+			if a == 0 || !(image.Point{X: offsetX + x, Y: offsetY + y}).In(bounds) {
+				continue
+			}
+
+			i := (offsetY+y)*img.Stride + (offsetX+x)*4
+			for c := 0; c < 3; c++ {
+				img.Pix[i+c] = uint8(float64(img.Pix[i+c]) * (1 - a))
+			}
+		}
+	}
+}
+
+// paintShadows drops soft smudges of random jigsaw shapes of the given size.
+func paintShadows(img *image.RGBA, rng *rand.Rand, count, size int) {
+	for i := 0; i < count; i++ {
+		mask := blurMask(RandomJigsawShape(rng, size).Mask(), 4)
+		side := mask.Bounds().Dx()
+		x := rng.Intn(max(1, img.Bounds().Dx()-side/2)) - side/4
+		y := rng.Intn(max(1, img.Bounds().Dy()-side/2)) - side/4
+		paintShadow(img, mask, x, y, 0.25+rng.Float64()*0.2)
+	}
+}
+
+func clampByte(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(0xFF, math.Round(v))))
+}
@@ -0,0 +1,68 @@
+package imaging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDistortionProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    DistortionProfile
+		wantErr string
+	}{
+		{
+			name: "full",
+			spec: "from=20,decoys=2,soften=0.7,jitter=0.35,noise=0.4,warp=2.5,shadows=3",
+			want: DistortionProfile{From: 20, Max: Distortion{DecoyHoles: 2, Soften: 0.7, ColorJitter: 0.35, Noise: 0.4, Warp: 2.5, Shadows: 3}},
+		},
+		{name: "empty", spec: "", want: DistortionProfile{}},
+		{name: "spaces and empty fields", spec: " from = 10 ,, noise=0.2 ", want: DistortionProfile{From: 10, Max: Distortion{Noise: 0.2}}},
+		{name: "clamped", spec: "from=150,soften=2,jitter=3,noise=4", want: DistortionProfile{From: 100, Max: Distortion{Soften: 1, ColorJitter: 1, Noise: 1}}},
+		{name: "not key=value", spec: "from=20,noise", wantErr: `distortion "noise" is not key=value`},
+		{name: "not a number", spec: "warp=far", wantErr: `distortion "warp=far" has invalid value`},
+		{name: "negative", spec: "shadows=-1", wantErr: `distortion "shadows=-1" has invalid value`},
+		{name: "unknown", spec: "blur=1", wantErr: `unknown distortion "blur"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile, err := ParseDistortionProfile(tt.spec)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, profile)
+		})
+	}
+}
+
+func TestDistortionProfileAt(t *testing.T) {
+	profile, err := ParseDistortionProfile("from=20,decoys=2,soften=0.7,jitter=0.35,noise=0.4,warp=2.5,shadows=3")
+	require.NoError(t, err)
+
+	for _, complexity := range []int32{0, 10, 20} {
+		assert.Equal(t, Distortion{}, profile.At(complexity), "nothing applies at complexity %d", complexity)
+	}
+	assert.Equal(t, profile.Max, profile.At(100))
+	assert.Equal(t, Distortion{}, DistortionProfile{From: 100, Max: profile.Max}.At(100))
+
+	previous := profile.At(20)
+	for complexity := int32(21); complexity <= 100; complexity++ {
+		current := profile.At(complexity)
+		assert.Greater(t, current.Noise, previous.Noise, "complexity %d", complexity)
+		assert.Greater(t, current.Warp, previous.Warp, "complexity %d", complexity)
+		assert.Greater(t, current.Soften, previous.Soften, "complexity %d", complexity)
+		assert.Greater(t, current.ColorJitter, previous.ColorJitter, "complexity %d", complexity)
+		assert.GreaterOrEqual(t, current.DecoyHoles, previous.DecoyHoles, "complexity %d", complexity)
+		assert.GreaterOrEqual(t, current.Shadows, previous.Shadows, "complexity %d", complexity)
+		previous = current
+	}
+
+	assert.InDelta(t, 0.2, profile.At(60).Noise, 1e-9, "halfway between from and 100")
+	assert.Equal(t, 1, profile.At(60).DecoyHoles)
+}
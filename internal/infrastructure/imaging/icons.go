@@ -0,0 +1,228 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+type IconShape int8
+
+const (
+	IconCircle IconShape = iota
+	IconSquare
+	IconTriangle
+	IconDiamond
+	IconStar
+	IconCross
+	iconShapeCount
+)
+
+const (
+	iconOutline      = 2
+	promptIconSize   = 32
+	promptIconMargin = 8
+)
+
+var IconColors = []color.NRGBA{
+	{R: 0xE5, G: 0x39, B: 0x35, A: 0xFF},
+	{R: 0x1E, G: 0x88, B: 0xE5, A: 0xFF},
+	{R: 0x43, G: 0xA0, B: 0x47, A: 0xFF},
+	{R: 0xFD, G: 0xD8, B: 0x35, A: 0xFF},
+	{R: 0x8E, G: 0x24, B: 0xAA, A: 0xFF},
+}
+
+// Icon is a filled shape of Size pixels centred on X, Y and turned by Angle
+// degrees. Shape and Color together identify it in the prompt.
+type Icon struct {
+	Shape IconShape
+	Color int
+	X     int
+	Y     int
+	Size  int
+	Angle float64
+}
+
+// RandomIcons returns n icons that differ in shape, color or both; the
+// caller places them.
+func RandomIcons(rng *rand.Rand, n int) ([]Icon, error) {
+	total := int(iconShapeCount) * len(IconColors)
+	if n > total {
+		return nil, fmt.Errorf("only %d distinct icons available, %d requested", total, n)
+	}
+
+	icons := make([]Icon, 0, n)
+	for _, k := range rng.Perm(total)[:n] {
+		icons = append(icons, Icon{
+			Shape: IconShape(k % int(iconShapeCount)),
+			Color: k / int(iconShapeCount),
+		})
+	}
+	return icons, nil
+}
+
+type ClickSequenceImages struct {
+	Canvas         string
+	Prompt         string
+	PromptWidth    int
+	BackgroundSeed int64
+}
+
+// RenderClickSequence draws the icons over a random background and the
+// targets, in order, on a separate prompt strip. Both are images so the order
+// cannot be read from the page markup; the distortion applies to the canvas
+// only.
+func (r *Renderer) RenderClickSequence(rng *rand.Rand, icons []Icon, targets []Icon, distortion Distortion) (*ClickSequenceImages, error) {
+	if err := r.checkIcons(icons); err != nil {
+		return nil, err
+	}
+
+	background, seed := r.backgrounds.Pick(rng)
+	canvasURI, err := drawClickCanvas(background, rng, icons, distortion)
+	if err != nil {
+		return nil, err
+	}
+
+	promptWidth := len(targets)*(promptIconSize+promptIconMargin) + promptIconMargin
+	promptHeight := promptIconSize + 2*promptIconMargin
+	prompt := image.NewRGBA(image.Rect(0, 0, promptWidth, promptHeight))
+	for i := range prompt.Pix {
+		prompt.Pix[i] = 0xFF
+	}
+	for i, target := range targets {
+		drawIcon(prompt, Icon{
+			Shape: target.Shape,
+			Color: target.Color,
+			X:     promptIconMargin + i*(promptIconSize+promptIconMargin) + promptIconSize/2,
+			Y:     promptHeight / 2,
+			Size:  promptIconSize - 2*iconOutline,
+		})
+	}
+
+	promptURI, err := EncodePNGDataURI(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode click prompt: %w", err)
+	}
+
+	return &ClickSequenceImages{
+		Canvas:         canvasURI,
+		Prompt:         promptURI,
+		PromptWidth:    promptWidth,
+		BackgroundSeed: seed,
+	}, nil
+}
+
+// RedrawClickCanvas draws the icons again over the background of
+// backgroundSeed, after some of them moved. The distortion is drawn anew from
+// rng, so the new canvas does not show which icon changed place.
+func (r *Renderer) RedrawClickCanvas(rng *rand.Rand, backgroundSeed int64, icons []Icon, distortion Distortion) (string, error) {
+	if err := r.checkIcons(icons); err != nil {
+		return "", err
+	}
+	return drawClickCanvas(r.backgrounds.Render(backgroundSeed), rng, icons, distortion)
+}
+
+func (r *Renderer) checkIcons(icons []Icon) error {
+	for _, icon := range icons {
+		half := icon.Size/2 + iconOutline
+		if icon.X-half < 0 || icon.Y-half < 0 || icon.X+half > r.width || icon.Y+half > r.height {
+			return fmt.Errorf("icon of %d at (%d,%d) does not fit %dx%d canvas", icon.Size, icon.X, icon.Y, r.width, r.height)
+		}
+	}
+	return nil
+}
+
+func drawClickCanvas(background *image.RGBA, rng *rand.Rand, icons []Icon, distortion Distortion) (string, error) {
+	if len(icons) > 0 {
+		paintShadows(background, rng, distortion.Shadows, icons[0].Size)
+	}
+	for _, icon := range icons {
+		drawIcon(background, icon)
+	}
+	background = warpImage(background, rng, distortion.Warp)
+	jitterColors(background.Pix, rng, distortion.ColorJitter)
+	addNoise(background.Pix, rng, distortion.Noise)
+
+	canvasURI, err := EncodeJPEGDataURI(background)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode click canvas: %w", err)
+	}
+	return canvasURI, nil
+}
+
+// drawIcon paints a white outline first and the coloured shape over it, so
+// icons stay visible on any background.
+func drawIcon(dst *image.RGBA, icon Icon) {
+	fill := IconColors[icon.Color%len(IconColors)]
+	outline := color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+
+	paintShape(dst, icon, float64(icon.Size)/2+iconOutline, outline)
+	paintShape(dst, icon, float64(icon.Size)/2, fill)
+}
+
+func paintShape(dst *image.RGBA, icon Icon, radius float64, c color.NRGBA) {
+	sin, cos := math.Sincos(-icon.Angle * math.Pi / 180)
+	samples := maskSupersample * maskSupersample
+	extent := int(math.Ceil(radius)) + 1
+	bounds := dst.Bounds()
+
+	for y := icon.Y - extent; y <= icon.Y+extent; y++ {
+		for x := icon.X - extent; x <= icon.X+extent; x++ {
+			if !(image.Point{X: x, Y: y}).In(bounds) {
+				continue
+			}
+
+			hits := 0
+			for sy := 0; sy < maskSupersample; sy++ {
+				for sx := 0; sx < maskSupersample; sx++ {
+					dx := float64(x-icon.X) + (float64(sx)+0.5)/maskSupersample - 0.5
+					dy := float64(y-icon.Y) + (float64(sy)+0.5)/maskSupersample - 0.5
+					u := (dx*cos - dy*sin) / radius
+					v := (dx*sin + dy*cos) / radius
+					if icon.Shape.contains(u, v) {
+						hits++
+					}
+				}
+			}
+			if hits == 0 {
+				continue
+			}
+
+			a := float64(hits) / float64(samples) * float64(c.A) / 0xFF
+			i := y*dst.Stride + x*4
+			for ch, value := range [3]uint8{c.R, c.G, c.B} {
+				dst.Pix[i+ch] = uint8(float64(dst.Pix[i+ch])*(1-a) + float64(value)*a)
+			}
+		}
+	}
+}
+
+// contains tests a point in the unit box [-1, 1]², y pointing down.
+func (s IconShape) contains(u, v float64) bool {
+	switch s {
+	case IconCircle:
+		return u*u+v*v <= 1
+	case IconSquare:
+		return math.Abs(u) <= 0.8 && math.Abs(v) <= 0.8
+	case IconTriangle:
+		return v <= 0.75 && v >= 2*math.Abs(u)-1
+	case IconDiamond:
+		return math.Abs(u)+math.Abs(v) <= 1
+	case IconStar:
+		// Five points: the radius alternates between 1 and 0.45 around
+		// the centre, with straight edges in between.
+		angle := math.Atan2(u, -v)
+		sector := 2 * math.Pi / 10
+		k := math.Mod(angle+2*math.Pi, 2*sector)
+		t := math.Min(k, 2*sector-k) / sector
+		r := math.Hypot(u, v)
+		inner, outer := 0.45, 1.0
+		return r <= outer*inner/(inner*(1-t)+outer*t)
+	case IconCross:
+		return (math.Abs(u) <= 0.3 && math.Abs(v) <= 0.9) || (math.Abs(v) <= 0.3 && math.Abs(u) <= 0.9)
+	default:
+		return false
+	}
+}
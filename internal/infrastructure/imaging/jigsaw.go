@@ -0,0 +1,139 @@
+package imaging
+
+import (
+	"image"
+	"math"
+	"math/rand"
+)
+
+type EdgeKind int8
+
+const (
+	EdgeBlank EdgeKind = -1
+	EdgeFlat  EdgeKind = 0
+	EdgeTab   EdgeKind = 1
+)
+
+const (
+	knobRadiusRatio = 0.2
+	knobOffsetRatio = 0.35
+	maskSupersample = 4
+)
+
+// JigsawShape is a square body of Size pixels whose top, right, bottom and
+// left edges carry an outward tab, an inward blank or nothing.
+type JigsawShape struct {
+	Size  int
+	Edges [4]EdgeKind
+}
+
+func RandomJigsawShape(rng *rand.Rand, size int) JigsawShape {
+	shape := JigsawShape{Size: size}
+	for {
+		hasTab, hasBlank := false, false
+		for i := range shape.Edges {
+			shape.Edges[i] = EdgeKind(rng.Intn(3) - 1)
+			hasTab = hasTab || shape.Edges[i] == EdgeTab
+			hasBlank = hasBlank || shape.Edges[i] == EdgeBlank
+		}
+		if hasTab && hasBlank {
+			return shape
+		}
+	}
+}
+
+func (s JigsawShape) knobRadius() float64 {
+	return float64(s.Size) * knobRadiusRatio
+}
+
+func (s JigsawShape) padding() int {
+	r := s.knobRadius()
+	return int(math.Ceil(r*(1+knobOffsetRatio))) + 1
+}
+
+// Bounds is the side of the square box that fully contains the shape.
+func (s JigsawShape) Bounds() int {
+	return s.Size + 2*s.padding()
+}
+
+func (s JigsawShape) contains(x, y float64) bool {
+	p := float64(s.padding())
+	size := float64(s.Size)
+	r := s.knobRadius()
+	d := r * knobOffsetRatio
+	mid := p + size/2
+
+	inside := x >= p && x < p+size && y >= p && y < p+size
+
+	// Edge normals in top, right, bottom, left order.
+	centers := [4][2]float64{
+		{mid, p},
+		{p + size, mid},
+		{mid, p + size},
+		{p, mid},
+	}
+	normals := [4][2]float64{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+	for i, kind := range s.Edges {
+		if kind == EdgeFlat {
+			continue
+		}
+		shift := d * float64(kind)
+		cx := centers[i][0] + normals[i][0]*shift
+		cy := centers[i][1] + normals[i][1]*shift
+		inKnob := (x-cx)*(x-cx)+(y-cy)*(y-cy) < r*r
+
+		if kind == EdgeTab && inKnob {
+			return true
+		}
+		if kind == EdgeBlank && inKnob {
+			inside = false
+		}
+	}
+
+	return inside
+}
+
+// Mask renders the shape as an anti-aliased alpha mask of Bounds() x Bounds().
+func (s JigsawShape) Mask() *image.Alpha {
+	side := s.Bounds()
+	mask := image.NewAlpha(image.Rect(0, 0, side, side))
+	samples := maskSupersample * maskSupersample
+
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			hits := 0
+			for sy := 0; sy < maskSupersample; sy++ {
+				for sx := 0; sx < maskSupersample; sx++ {
+					px := float64(x) + (float64(sx)+0.5)/maskSupersample
+					py := float64(y) + (float64(sy)+0.5)/maskSupersample
+					if s.contains(px, py) {
+						hits++
+					}
+				}
+			}
+			mask.Pix[y*mask.Stride+x] = uint8(hits * 0xFF / samples)
+		}
+	}
+
+	return mask
+}
+
+// edgeStrength is how much brighter a mask pixel is than its darkest
+// neighbour; it is non-zero only along the outline of the shape.
+func edgeStrength(mask *image.Alpha, x, y int) uint8 {
+	a := mask.AlphaAt(x, y).A
+	if a == 0 {
+		return 0
+	}
+
+	lowest := a
+	for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		n := mask.AlphaAt(x+d[0], y+d[1]).A
+		if n < lowest {
+			lowest = n
+		}
+	}
+
+	return a - lowest
+}
@@ -0,0 +1,183 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+)
+
+const (
+	holeShade      = 0.55
+	outlineOpacity = 0.7
+)
+
+type Renderer struct {
+	backgrounds *Backgrounds
+	width       int
+	height      int
+}
+
+func NewRenderer(backgroundsPath string, width, height int, options BackgroundOptions) (*Renderer, error) {
+	backgrounds, err := NewBackgrounds(backgroundsPath, width, height, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Renderer{
+		backgrounds: backgrounds,
+		width:       width,
+		height:      height,
+	}, nil
+}
+
+// Background regenerates the background drawn for seed, for auditing a
+// challenge after the fact.
+func (r *Renderer) Background(seed int64) *image.RGBA {
+	return r.backgrounds.Render(seed)
+}
+
+// SliderPuzzle places Shape with the top-left corner of its bounding box at X, Y.
+type SliderPuzzle struct {
+	X          int
+	Y          int
+	Shape      JigsawShape
+	Distortion Distortion
+}
+
+type SliderPuzzleImages struct {
+	Background  string
+	Piece       string
+	PieceWidth  int
+	PieceHeight int
+	// BackgroundSeed regenerates the background before the hole was cut.
+	BackgroundSeed int64
+}
+
+// RenderSliderPuzzle cuts the piece out of a random background and returns the
+// holed background and the piece as data URIs. The piece is a transparent strip
+// as tall as the canvas, so neither coordinate has to be sent to the client.
+func (r *Renderer) RenderSliderPuzzle(rng *rand.Rand, puzzle SliderPuzzle) (*SliderPuzzleImages, error) {
+	side := puzzle.Shape.Bounds()
+	if puzzle.X < 0 || puzzle.Y < 0 || puzzle.X+side > r.width || puzzle.Y+side > r.height {
+		return nil, fmt.Errorf("puzzle piece %dx%d at (%d,%d) does not fit %dx%d canvas",
+			side, side, puzzle.X, puzzle.Y, r.width, r.height)
+	}
+
+	background, seed := r.backgrounds.Pick(rng)
+	mask := puzzle.Shape.Mask()
+	d := puzzle.Distortion
+
+	piece := cutPiece(background, mask, puzzle.X, puzzle.Y, r.height)
+
+	// The hole gets a softened outline while the piece keeps its crisp one,
+	// so the two no longer match edge for edge.
+	softness := int(math.Round(d.Soften * 3))
+	outline := outlineOpacity * (1 - 0.7*d.Soften)
+	punchHole(background, blurMask(mask, softness), puzzle.X, puzzle.Y, outline)
+	punchDecoys(background, rng, puzzle, d.DecoyHoles, softness, outline)
+	paintShadows(background, rng, d.Shadows, puzzle.Shape.Size)
+
+	background = warpImage(background, rng, d.Warp)
+	jitterColors(background.Pix, rng, d.ColorJitter)
+	jitterColors(piece.Pix, rng, d.ColorJitter)
+	addNoise(background.Pix, rng, d.Noise)
+	addNoise(piece.Pix, rng, d.Noise)
+
+	backgroundURI, err := EncodeJPEGDataURI(background)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode background: %w", err)
+	}
+
+	pieceURI, err := EncodePNGDataURI(piece)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode piece: %w", err)
+	}
+
+	return &SliderPuzzleImages{
+		Background:     backgroundURI,
+		Piece:          pieceURI,
+		PieceWidth:     side,
+		PieceHeight:    r.height,
+		BackgroundSeed: seed,
+	}, nil
+}
+
+func cutPiece(background *image.RGBA, mask *image.Alpha, offsetX, offsetY, height int) *image.NRGBA {
+	side := mask.Bounds().Dx()
+	piece := image.NewNRGBA(image.Rect(0, 0, side, height))
+
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			a := mask.Pix[y*mask.Stride+x]
+			if a == 0 {
+				continue
+			}
+
+			src := (offsetY+y)*background.Stride + (offsetX+x)*4
+			dst := (offsetY+y)*piece.Stride + x*4
+			edge := float64(edgeStrength(mask, x, y)) / 0xFF * outlineOpacity
+
+			for c := 0; c < 3; c++ {
+				v := float64(background.Pix[src+c])
+				piece.Pix[dst+c] = uint8(v + (0xFF-v)*edge)
+			}
+			piece.Pix[dst+3] = a
+		}
+	}
+
+	return piece
+}
+
+// punchDecoys punches holes of other random shapes into the row of the real
+// one, where a solver scanning along the slider would look for it.
+func punchDecoys(background *image.RGBA, rng *rand.Rand, puzzle SliderPuzzle, count, softness int, outline float64) {
+	side := puzzle.Shape.Bounds()
+	width, height := background.Bounds().Dx(), background.Bounds().Dy()
+	taken := []int{puzzle.X}
+
+	for placed := 0; placed < count; placed++ {
+		x, ok := 0, false
+		for try := 0; try < 20 && !ok; try++ {
+			x = rng.Intn(width - side + 1)
+			ok = true
+			for _, other := range taken {
+				if x > other-side && x < other+side {
+					ok = false
+					break
+				}
+			}
+		}
+		if !ok {
+			return
+		}
+		taken = append(taken, x)
+
+		y := puzzle.Y + rng.Intn(side/2+1) - side/4
+		y = max(0, min(height-side, y))
+
+		mask := RandomJigsawShape(rng, puzzle.Shape.Size).Mask()
+		punchHole(background, blurMask(mask, softness), x, y, outline)
+	}
+}
+
+func punchHole(background *image.RGBA, mask *image.Alpha, offsetX, offsetY int, outline float64) {
+	side := mask.Bounds().Dx()
+
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			a := float64(mask.Pix[y*mask.Stride+x]) / 0xFF
+			if a == 0 {
+				continue
+			}
+
+			i := (offsetY+y)*background.Stride + (offsetX+x)*4
+			edge := float64(edgeStrength(mask, x, y)) / 0xFF * outline
+
+			for c := 0; c < 3; c++ {
+				v := float64(background.Pix[i+c]) * (1 - holeShade*a)
+				background.Pix[i+c] = uint8(v + (0xFF-v)*edge)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+)
+
+// RotationPuzzle describes a circular crop of a background turned clockwise
+// by Angle degrees.
+type RotationPuzzle struct {
+	Angle      float64
+	Diameter   int
+	Distortion Distortion
+}
+
+type RotationImages struct {
+	Image          string
+	Diameter       int
+	BackgroundSeed int64
+}
+
+// RenderRotation crops a disc out of a random background and returns it
+// rotated as a PNG data URI. Only the rotated pixels reach the client, so the
+// angle has to be recovered from the picture itself.
+func (r *Renderer) RenderRotation(rng *rand.Rand, puzzle RotationPuzzle) (*RotationImages, error) {
+	side := puzzle.Diameter
+	if side <= 0 || side > r.width || side > r.height {
+		return nil, fmt.Errorf("rotation disc %d does not fit %dx%d canvas", side, r.width, r.height)
+	}
+
+	background, seed := r.backgrounds.Pick(rng)
+	originX := rng.Intn(r.width - side + 1)
+	originY := rng.Intn(r.height - side + 1)
+
+	// Warping before the crop bends the lines that give away which way is up.
+	d := puzzle.Distortion
+	paintShadows(background, rng, d.Shadows, side/4)
+	background = warpImage(background, rng, d.Warp)
+
+	disc := rotateDisc(background, originX, originY, side, puzzle.Angle)
+	jitterColors(disc.Pix, rng, d.ColorJitter)
+	addNoise(disc.Pix, rng, d.Noise)
+
+	uri, err := EncodePNGDataURI(disc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rotation disc: %w", err)
+	}
+
+	return &RotationImages{
+		Image:          uri,
+		Diameter:       side,
+		BackgroundSeed: seed,
+	}, nil
+}
+
+// rotateDisc samples the side×side square at originX, originY through an
+// inverse rotation, with bilinear filtering and an antialiased circular edge.
+func rotateDisc(src *image.RGBA, originX, originY, side int, angle float64) *image.NRGBA {
+	disc := image.NewNRGBA(image.Rect(0, 0, side, side))
+
+	radius := float64(side) / 2
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			dx := float64(x) + 0.5 - radius
+			dy := float64(y) + 0.5 - radius
+
+			coverage := clampUnit(radius - math.Hypot(dx, dy))
+			if coverage == 0 {
+				continue
+			}
+
+			sx := float64(originX) + radius + dx*cos + dy*sin - 0.5
+			sy := float64(originY) + radius - dx*sin + dy*cos - 0.5
+
+			i := y*disc.Stride + x*4
+			sampleBilinear(src, sx, sy, disc.Pix[i:i+3])
+			disc.Pix[i+3] = uint8(coverage * 0xFF)
+		}
+	}
+
+	return disc
+}
+
+func sampleBilinear(src *image.RGBA, x, y float64, dst []uint8) {
+	bounds := src.Bounds()
+	x = math.Max(0, math.Min(float64(bounds.Dx()-1), x))
+	y = math.Max(0, math.Min(float64(bounds.Dy()-1), y))
+
+	x0, y0 := int(x), int(y)
+	x1, y1 := min(x0+1, bounds.Dx()-1), min(y0+1, bounds.Dy()-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	for c := 0; c < 3; c++ {
+		top := float64(src.Pix[y0*src.Stride+x0*4+c])*(1-fx) + float64(src.Pix[y0*src.Stride+x1*4+c])*fx
+		bottom := float64(src.Pix[y1*src.Stride+x0*4+c])*(1-fx) + float64(src.Pix[y1*src.Stride+x1*4+c])*fx
+		dst[c] = uint8(math.Round(top*(1-fy) + bottom*fy))
+	}
+}
+
+func clampUnit(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,278 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+const (
+	noiseOctaves  = 4
+	shapesMin     = 6
+	shapesMax     = 14
+	grainStrength = 10
+)
+
+// synthesizeBackground paints a background from nothing but the seed: a
+// gradient, Perlin noise clouds, translucent shapes and a texture on top. The
+// same seed always gives the same pixels.
+func synthesizeBackground(seed int64, width, height int) *image.RGBA {
+	rng := rand.New(rand.NewSource(seed))
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	palette := randomPalette(rng)
+
+	paintGradient(img, rng, palette)
+	paintClouds(img, rng, palette)
+	paintShapes(img, rng, palette)
+	paintTexture(img, rng)
+
+	return img
+}
+
+func randomPalette(rng *rand.Rand) []color.NRGBA {
+	hue := rng.Float64() * 360
+	step := 25 + rng.Float64()*35
+	if rng.Intn(3) == 0 {
+		step = 180 - step
+	}
+
+	// Alternate dark and light colours so that neighbouring layers contrast.
+	palette := make([]color.NRGBA, 4)
+	for i := range palette {
+		value := 0.25 + rng.Float64()*0.3
+		if i%2 == 1 {
+			value = 0.7 + rng.Float64()*0.25
+		}
+		palette[i] = hsv(math.Mod(hue+float64(i)*step, 360), 0.35+rng.Float64()*0.5, value)
+	}
+	return palette
+}
+
+func paintGradient(img *image.RGBA, rng *rand.Rand, palette []color.NRGBA) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	sin, cos := math.Sincos(rng.Float64() * 2 * math.Pi)
+	extent := math.Abs(cos)*float64(width) + math.Abs(sin)*float64(height)
+	from, mid, to := palette[0], palette[1], palette[2]
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dx, dy := float64(x)-float64(width)/2, float64(y)-float64(height)/2
+			t := clampUnit(0.5 + (dx*cos+dy*sin)/extent)
+
+			var c color.NRGBA
+			if t < 0.5 {
+				c = lerpColor(from, mid, t*2)
+			} else {
+				c = lerpColor(mid, to, t*2-1)
+			}
+
+			i := y*img.Stride + x*4
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.R, c.G, c.B, 0xFF
+		}
+	}
+}
+
+// paintClouds mixes the last palette colour in wherever fractal noise is high,
+// which gives every background its own irregular blotches.
+func paintClouds(img *image.RGBA, rng *rand.Rand, palette []color.NRGBA) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	noise := newPerlin(rng)
+	scale := 1 / (25 + rng.Float64()*45)
+	strength := 0.5 + rng.Float64()*0.4
+	cloud := palette[3]
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			n := noise.fractal(float64(x)*scale, float64(y)*scale, noiseOctaves)
+			a := clampUnit(0.5+n) * strength
+
+			i := y*img.Stride + x*4
+			img.Pix[i] = uint8(float64(img.Pix[i])*(1-a) + float64(cloud.R)*a)
+			img.Pix[i+1] = uint8(float64(img.Pix[i+1])*(1-a) + float64(cloud.G)*a)
+			img.Pix[i+2] = uint8(float64(img.Pix[i+2])*(1-a) + float64(cloud.B)*a)
+		}
+	}
+}
+
+// paintShapes scatters large translucent shapes, much bigger and fainter than
+// the click-order icons so they are not mistaken for them.
+func paintShapes(img *image.RGBA, rng *rand.Rand, palette []color.NRGBA) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	shapes := []IconShape{IconCircle, IconSquare, IconTriangle, IconDiamond}
+
+	for n := shapesMin + rng.Intn(shapesMax-shapesMin+1); n > 0; n-- {
+		c := palette[rng.Intn(len(palette))]
+		if rng.Intn(2) == 0 {
+			c = lerpColor(c, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF}, 0.5)
+		} else {
+			c = lerpColor(c, color.NRGBA{}, 0.4)
+		}
+		c.A = uint8(50 + rng.Intn(90))
+
+		size := 40 + rng.Intn(140)
+		paintShape(img, Icon{
+			Shape: shapes[rng.Intn(len(shapes))],
+			X:     rng.Intn(width),
+			Y:     rng.Intn(height),
+			Size:  size,
+			Angle: rng.Float64() * 360,
+		}, float64(size)/2, c)
+	}
+}
+
+// paintTexture adds faint stripes or a checker pattern and film grain, so
+// flat areas still differ pixel by pixel between backgrounds.
+func paintTexture(img *image.RGBA, rng *rand.Rand) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	sin, cos := math.Sincos(rng.Float64() * math.Pi)
+	period := 6 + rng.Float64()*14
+	amplitude := 6 + rng.Float64()*10
+	pattern := rng.Intn(3)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var shade float64
+			switch pattern {
+			case 0:
+				shade = amplitude * math.Sin(2*math.Pi*(float64(x)*cos+float64(y)*sin)/period)
+			case 1:
+				u := math.Floor((float64(x)*cos + float64(y)*sin) / period)
+				v := math.Floor((float64(y)*cos - float64(x)*sin) / period)
+				if int(u+v)%2 == 0 {
+					shade = amplitude / 2
+				} else {
+					shade = -amplitude / 2
+				}
+			}
+			shade += float64(rng.Intn(2*grainStrength+1) - grainStrength)
+
+			i := y*img.Stride + x*4
+			for ch := 0; ch < 3; ch++ {
+				img.Pix[i+ch] = uint8(math.Max(0, math.Min(0xFF, float64(img.Pix[i+ch])+shade)))
+			}
+		}
+	}
+}
+
+// blendPhoto mixes a photo over the synthetic background with the given
+// opacity; both images must have the same size.
+func blendPhoto(dst, photo *image.RGBA, opacity float64) {
+	for i := 0; i < len(dst.Pix); i += 4 {
+		for ch := 0; ch < 3; ch++ {
+			dst.Pix[i+ch] = uint8(float64(dst.Pix[i+ch])*(1-opacity) + float64(photo.Pix[i+ch])*opacity)
+		}
+	}
+}
+
+// perlin is classic two-dimensional gradient noise over a shuffled lattice.
+type perlin struct {
+	perm [512]uint8
+}
+
+func newPerlin(rng *rand.Rand) *perlin {
+	p := &perlin{}
+	for i, v := range rng.Perm(256) {
+		p.perm[i] = uint8(v)
+		p.perm[i+256] = uint8(v)
+	}
+	return p
+}
+
+// fractal sums octaves of noise at doubling frequency and halving amplitude;
+// the result stays roughly within [-1, 1].
+func (p *perlin) fractal(x, y float64, octaves int) float64 {
+	var sum, amplitude, norm float64 = 0, 1, 0
+	for o := 0; o < octaves; o++ {
+		sum += amplitude * p.noise(x, y)
+		norm += amplitude
+		amplitude /= 2
+		x, y = x*2, y*2
+	}
+	return sum / norm * 1.5
+}
+
+func (p *perlin) noise(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	xi, yi := int(x0)&0xFF, int(y0)&0xFF
+
+	u, v := fade(fx), fade(fy)
+	aa := p.perm[int(p.perm[xi])+yi]
+	ab := p.perm[int(p.perm[xi])+yi+1]
+	ba := p.perm[int(p.perm[xi+1])+yi]
+	bb := p.perm[int(p.perm[xi+1])+yi+1]
+
+	return lerp(
+		lerp(grad(aa, fx, fy), grad(ba, fx-1, fy), u),
+		lerp(grad(ab, fx, fy-1), grad(bb, fx-1, fy-1), u),
+		v,
+	)
+}
+
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func grad(hash uint8, x, y float64) float64 {
+	switch hash & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return x - y
+	case 2:
+		return -x + y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	default:
+		return -y
+	}
+}
+
+func lerpColor(a, b color.NRGBA, t float64) color.NRGBA {
+	return color.NRGBA{
+		R: uint8(lerp(float64(a.R), float64(b.R), t)),
+		G: uint8(lerp(float64(a.G), float64(b.G), t)),
+		B: uint8(lerp(float64(a.B), float64(b.B), t)),
+		A: 0xFF,
+	}
+}
+
+func hsv(h, s, v float64) color.NRGBA {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return color.NRGBA{
+		R: uint8(math.Round((r + m) * 0xFF)),
+		G: uint8(math.Round((g + m) * 0xFF)),
+		B: uint8(math.Round((b + m) * 0xFF)),
+		A: 0xFF,
+	}
+}
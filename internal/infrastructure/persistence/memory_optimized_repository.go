@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/domain/interfaces"
 )
 
 type MemoryOptimizedRepository struct {
@@ -17,6 +18,11 @@ type MemoryOptimizedRepository struct {
 	stopChan      chan struct{}
 }
 
+var (
+	_ interfaces.ChallengeRepository = (*MemoryOptimizedRepository)(nil)
+	_ interfaces.ChallengeRepository = (*RedisRepository)(nil)
+)
+
 func NewMemoryOptimizedRepository(maxChallenges int) *MemoryOptimizedRepository {
 	repo := &MemoryOptimizedRepository{
 		challenges:    make(map[string]*entity.Challenge),
@@ -46,17 +52,77 @@ func (r *MemoryOptimizedRepository) GetChallenge(ctx context.Context, challengeI
 
 	challenge, exists := r.challenges[challengeID]
 	if !exists {
-		return nil, fmt.Errorf("challenge with ID %s not found", challengeID)
+		return nil, fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeNotFound)
 	}
 
 	if challenge.ExpiresAt.Before(time.Now()) {
 		delete(r.challenges, challengeID)
-		return nil, fmt.Errorf("challenge with ID %s has expired", challengeID)
+		return nil, fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeExpired)
 	}
 
 	return challenge, nil
 }
 
+// UpdateChallenge runs update on a copy outside the lock, so a slow update
+// such as a render or a plugin call holds up no other challenge, and stores
+// the result only if the challenge was not replaced in the meantime; a stored
+// challenge is never changed in place, so its pointer serves as its version.
+// On a conflict update runs again on the newer challenge.
+func (r *MemoryOptimizedRepository) UpdateChallenge(ctx context.Context, challengeID string, update func(challenge *entity.Challenge) (bool, error)) error {
+	for attempt := 0; attempt < maxUpdateRetries; attempt++ {
+		r.mu.RLock()
+		stored, exists := r.challenges[challengeID]
+		r.mu.RUnlock()
+
+		if !exists {
+			return fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeNotFound)
+		}
+		if stored.ExpiresAt.Before(time.Now()) {
+			r.compareAndDelete(challengeID, stored)
+			return fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeExpired)
+		}
+
+		challenge := *stored
+		remove, err := update(&challenge)
+		if err != nil {
+			return err
+		}
+
+		var swapped bool
+		if remove {
+			swapped = r.compareAndDelete(challengeID, stored)
+		} else {
+			swapped = r.compareAndSet(challengeID, stored, &challenge)
+		}
+		if swapped {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to update challenge %s: changed concurrently %d times", challengeID, maxUpdateRetries)
+}
+
+func (r *MemoryOptimizedRepository) compareAndSet(challengeID string, old, challenge *entity.Challenge) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.challenges[challengeID] != old {
+		return false
+	}
+	r.challenges[challengeID] = challenge
+	return true
+}
+
+func (r *MemoryOptimizedRepository) compareAndDelete(challengeID string, old *entity.Challenge) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.challenges[challengeID] != old {
+		return false
+	}
+	delete(r.challenges, challengeID)
+	return true
+}
+
 func (r *MemoryOptimizedRepository) DeleteChallenge(ctx context.Context, challengeID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
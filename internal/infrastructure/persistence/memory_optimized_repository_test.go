@@ -0,0 +1,105 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryRepository(t *testing.T) *MemoryOptimizedRepository {
+	t.Helper()
+	repo := NewMemoryOptimizedRepository(100)
+	t.Cleanup(repo.Stop)
+	return repo
+}
+
+func TestMemoryRepositoryUpdateRunsOutsideLock(t *testing.T) {
+	repo := newTestMemoryRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("slow", time.Minute)))
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("other", time.Minute)))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- repo.UpdateChallenge(ctx, "slow", func(challenge *entity.Challenge) (bool, error) {
+			close(started)
+			<-release
+			challenge.Attempts++
+			return false, nil
+		})
+	}()
+	<-started
+
+	// A slow update, e.g. a plugin call, holds up no other challenge.
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("new", time.Minute)))
+	require.NoError(t, repo.UpdateChallenge(ctx, "other", func(challenge *entity.Challenge) (bool, error) {
+		challenge.Attempts++
+		return false, nil
+	}))
+
+	close(release)
+	require.NoError(t, <-done)
+	loaded, err := repo.GetChallenge(ctx, "slow")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), loaded.Attempts)
+}
+
+func TestMemoryRepositoryUpdateRetriesOnConflict(t *testing.T) {
+	repo := newTestMemoryRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("slider_update", time.Minute)))
+
+	calls := 0
+	err := repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		calls++
+		if calls == 1 {
+			require.NoError(t, repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+				challenge.Attempts++
+				return false, nil
+			}))
+		}
+		challenge.Attempts++
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "a conflicting write starts the update over")
+
+	loaded, err := repo.GetChallenge(ctx, "slider_update")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), loaded.Attempts)
+
+	require.NoError(t, repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		return true, nil
+	}))
+	_, err = repo.GetChallenge(ctx, "slider_update")
+	assert.Error(t, err)
+}
+
+func TestOptimizedRepositoryV2UpdateChallenge(t *testing.T) {
+	repo := NewOptimizedRepositoryV2()
+	ctx := context.Background()
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("slider_update", time.Minute)))
+
+	require.NoError(t, repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		challenge.Attempts++
+		return false, nil
+	}))
+	loaded, err := repo.GetChallenge(ctx, "slider_update")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), loaded.Attempts)
+
+	require.NoError(t, repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		return true, nil
+	}))
+	err = repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		t.Fatal("update must not run for a removed challenge")
+		return false, nil
+	})
+	assert.Error(t, err)
+}
@@ -21,6 +21,7 @@ type UserBlockInfo struct {
 	UserID       string
 	BlockedUntil time.Time
 	Reason       string
+	BlockedAt    time.Time
 }
 
 type MemoryInstanceRepository struct {
@@ -104,6 +105,7 @@ func (r *MemoryUserBlockRepository) SaveBlockedUser(blockedUser *entity.BlockedU
 		UserID:       blockedUser.UserID,
 		BlockedUntil: blockedUser.BlockedUntil,
 		Reason:       blockedUser.Reason,
+		BlockedAt:    blockedUser.BlockedAt,
 	}
 	return nil
 }
@@ -120,6 +122,7 @@ func (r *MemoryUserBlockRepository) GetBlockedUser(userID string) (*entity.Block
 		BlockedUntil: block.BlockedUntil,
 		Reason:       block.Reason,
 		Attempts:     0, // Default value
+		BlockedAt:    block.BlockedAt,
 	}, nil
 }
 
@@ -140,6 +143,7 @@ func (r *MemoryUserBlockRepository) GetAllBlockedUsers() ([]*entity.BlockedUser,
 			BlockedUntil: block.BlockedUntil,
 			Reason:       block.Reason,
 			Attempts:     0, // Default value
+			BlockedAt:    block.BlockedAt,
 		})
 	}
 	return blocks, nil
@@ -155,13 +159,19 @@ func (r *MemoryUserBlockRepository) IsUserBlocked(userID string) bool {
 	return time.Now().Before(block.BlockedUntil)
 }
 
-func (r *MemoryUserBlockRepository) BlockUser(userID, reason string) error {
+func (r *MemoryUserBlockRepository) BlockUser(userID string, duration time.Duration, reason string) error {
+	if duration <= 0 {
+		return fmt.Errorf("block duration must be positive, got %v", duration)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	now := time.Now()
 	r.blocks[userID] = &UserBlockInfo{
 		UserID:       userID,
-		BlockedUntil: time.Now().Add(5 * time.Minute), // Default block duration
+		BlockedUntil: now.Add(duration),
 		Reason:       reason,
+		BlockedAt:    now,
 	}
 	return nil
 }
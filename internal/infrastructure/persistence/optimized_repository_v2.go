@@ -2,6 +2,7 @@ package persistence
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -45,6 +46,41 @@ func (r *OptimizedRepositoryV2) GetChallenge(ctx context.Context, challengeID st
 	return challenge, nil
 }
 
+// UpdateChallenge works like MemoryOptimizedRepository.UpdateChallenge:
+// update runs on a copy outside the lock and the result is stored only if the
+// challenge was not replaced meanwhile.
+func (r *OptimizedRepositoryV2) UpdateChallenge(ctx context.Context, challengeID string, update func(challenge *entity.Challenge) (bool, error)) error {
+	for attempt := 0; attempt < maxUpdateRetries; attempt++ {
+		r.mu.RLock()
+		stored, exists := r.challenges[challengeID]
+		r.mu.RUnlock()
+
+		if !exists || time.Now().After(stored.ExpiresAt) {
+			return fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeNotFound)
+		}
+
+		challenge := *stored
+		remove, err := update(&challenge)
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		swapped := r.challenges[challengeID] == stored
+		if swapped && remove {
+			delete(r.challenges, challengeID)
+		} else if swapped {
+			r.challenges[challengeID] = &challenge
+		}
+		r.mu.Unlock()
+
+		if swapped {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to update challenge %s: changed concurrently %d times", challengeID, maxUpdateRetries)
+}
+
 func (r *OptimizedRepositoryV2) DeleteChallenge(ctx context.Context, challengeID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -0,0 +1,254 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/redis"
+)
+
+// challengeRecord is what a challenge looks like in Redis: the answer and the
+// bookkeeping needed to validate it, without the rendered HTML.
+type challengeRecord struct {
+	ID                 string          `json:"id"`
+	UserID             string          `json:"user_id"`
+	Type               string          `json:"type"`
+	Complexity         int32           `json:"complexity"`
+	Data               json.RawMessage `json:"data"`
+	Answer             interface{}     `json:"answer,omitempty"`
+	ExpiresAt          time.Time       `json:"expires_at"`
+	CreatedAt          time.Time       `json:"created_at"`
+	StartTime          *time.Time      `json:"start_time,omitempty"`
+	Attempts           int32           `json:"attempts"`
+	MaxAttempts        int32           `json:"max_attempts"`
+	MinTime            int64           `json:"min_time"`
+	MaxTime            int64           `json:"max_time"`
+	IsBlocked          bool            `json:"is_blocked,omitempty"`
+	BlockReason        string          `json:"block_reason,omitempty"`
+	TimeoutAttempts    int32           `json:"timeout_attempts"`
+	MaxTimeoutAttempts int32           `json:"max_timeout_attempts"`
+	BlockedUntil       *time.Time      `json:"blocked_until,omitempty"`
+	Site               string          `json:"site,omitempty"`
+	Seed               int64           `json:"seed,omitempty"`
+}
+
+// The scripts change a challenge only if it is still stored as it was read,
+// which makes a read followed by either of them a compare-and-swap.
+const (
+	compareAndSetScript = `if redis.call('GET', KEYS[1]) ~= ARGV[1] then return 0 end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1`
+	compareAndDeleteScript = `if redis.call('GET', KEYS[1]) ~= ARGV[1] then return 0 end
+redis.call('DEL', KEYS[1])
+return 1`
+)
+
+// maxUpdateRetries bounds how often UpdateChallenge starts over while other
+// instances keep changing the same challenge.
+const maxUpdateRetries = 10
+
+// RedisRepository keeps challenges in a Redis-compatible server so any
+// instance can validate them. Keys expire together with the challenge.
+type RedisRepository struct {
+	client    *redis.Client
+	keyPrefix string
+
+	saves  int64
+	hits   int64
+	misses int64
+	errors int64
+}
+
+func NewRedisRepository(client *redis.Client, keyPrefix string) *RedisRepository {
+	return &RedisRepository{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (r *RedisRepository) SaveChallenge(ctx context.Context, challenge *entity.Challenge) error {
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl < time.Millisecond {
+		return fmt.Errorf("challenge with ID %s: %w", challenge.ID, entity.ErrChallengeExpired)
+	}
+
+	value, err := encodeChallenge(challenge)
+	if err != nil {
+		return err
+	}
+
+	if err := r.client.SetPX(ctx, r.key(challenge.ID), value, ttl); err != nil {
+		atomic.AddInt64(&r.errors, 1)
+		return fmt.Errorf("failed to save challenge %s: %w", challenge.ID, err)
+	}
+
+	atomic.AddInt64(&r.saves, 1)
+	return nil
+}
+
+func (r *RedisRepository) GetChallenge(ctx context.Context, challengeID string) (*entity.Challenge, error) {
+	value, err := r.client.Get(ctx, r.key(challengeID))
+	if errors.Is(err, redis.ErrNil) {
+		atomic.AddInt64(&r.misses, 1)
+		return nil, fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeNotFound)
+	}
+	if err != nil {
+		atomic.AddInt64(&r.errors, 1)
+		return nil, fmt.Errorf("failed to load challenge %s: %w", challengeID, err)
+	}
+
+	challenge, err := decodeChallenge(value)
+	if err != nil {
+		atomic.AddInt64(&r.errors, 1)
+		return nil, fmt.Errorf("failed to decode challenge %s: %w", challengeID, err)
+	}
+
+	atomic.AddInt64(&r.hits, 1)
+	return challenge, nil
+}
+
+func (r *RedisRepository) DeleteChallenge(ctx context.Context, challengeID string) error {
+	if _, err := r.client.Del(ctx, r.key(challengeID)); err != nil {
+		atomic.AddInt64(&r.errors, 1)
+		return fmt.Errorf("failed to delete challenge %s: %w", challengeID, err)
+	}
+	return nil
+}
+
+func (r *RedisRepository) UpdateChallenge(ctx context.Context, challengeID string, update func(challenge *entity.Challenge) (bool, error)) error {
+	key := r.key(challengeID)
+
+	for i := 0; i < maxUpdateRetries; i++ {
+		current, err := r.client.Get(ctx, key)
+		if errors.Is(err, redis.ErrNil) {
+			atomic.AddInt64(&r.misses, 1)
+			return fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeNotFound)
+		}
+		if err != nil {
+			atomic.AddInt64(&r.errors, 1)
+			return fmt.Errorf("failed to load challenge %s: %w", challengeID, err)
+		}
+
+		challenge, err := decodeChallenge(current)
+		if err != nil {
+			atomic.AddInt64(&r.errors, 1)
+			return fmt.Errorf("failed to decode challenge %s: %w", challengeID, err)
+		}
+
+		remove, err := update(challenge)
+		if err != nil {
+			return err
+		}
+
+		var reply interface{}
+		if remove {
+			reply, err = r.client.Eval(ctx, compareAndDeleteScript, []string{key}, string(current))
+		} else {
+			ttl := time.Until(challenge.ExpiresAt)
+			if ttl < time.Millisecond {
+				return fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeExpired)
+			}
+
+			value, encodeErr := encodeChallenge(challenge)
+			if encodeErr != nil {
+				return encodeErr
+			}
+			reply, err = r.client.Eval(ctx, compareAndSetScript, []string{key},
+				string(current), string(value), strconv.FormatInt(ttl.Milliseconds(), 10))
+		}
+		if err != nil {
+			atomic.AddInt64(&r.errors, 1)
+			return fmt.Errorf("failed to update challenge %s: %w", challengeID, err)
+		}
+		if reply == int64(1) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to update challenge %s: changed concurrently %d times", challengeID, maxUpdateRetries)
+}
+
+func (r *RedisRepository) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"backend": "redis",
+		"saves":   atomic.LoadInt64(&r.saves),
+		"hits":    atomic.LoadInt64(&r.hits),
+		"misses":  atomic.LoadInt64(&r.misses),
+		"errors":  atomic.LoadInt64(&r.errors),
+	}
+}
+
+func (r *RedisRepository) key(challengeID string) string {
+	return r.keyPrefix + challengeID
+}
+
+func encodeChallenge(challenge *entity.Challenge) ([]byte, error) {
+	data, err := json.Marshal(challenge.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode challenge data: %w", err)
+	}
+
+	return json.Marshal(challengeRecord{
+		ID:                 challenge.ID,
+		UserID:             challenge.UserID,
+		Type:               challenge.Type,
+		Complexity:         challenge.Complexity,
+		Data:               data,
+		Answer:             challenge.Answer,
+		ExpiresAt:          challenge.ExpiresAt,
+		CreatedAt:          challenge.CreatedAt,
+		StartTime:          challenge.StartTime,
+		Attempts:           challenge.Attempts,
+		MaxAttempts:        challenge.MaxAttempts,
+		MinTime:            challenge.MinTime,
+		MaxTime:            challenge.MaxTime,
+		IsBlocked:          challenge.IsBlocked,
+		BlockReason:        challenge.BlockReason,
+		TimeoutAttempts:    challenge.TimeoutAttempts,
+		MaxTimeoutAttempts: challenge.MaxTimeoutAttempts,
+		BlockedUntil:       challenge.BlockedUntil,
+		Site:               challenge.Site,
+		Seed:               challenge.Seed,
+	})
+}
+
+func decodeChallenge(value []byte) (*entity.Challenge, error) {
+	var record challengeRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, err
+	}
+
+	data, err := entity.DecodeChallengeData(record.Type, record.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Challenge{
+		ID:                 record.ID,
+		UserID:             record.UserID,
+		Type:               record.Type,
+		Complexity:         record.Complexity,
+		Data:               data,
+		Answer:             record.Answer,
+		ExpiresAt:          record.ExpiresAt,
+		CreatedAt:          record.CreatedAt,
+		StartTime:          record.StartTime,
+		Attempts:           record.Attempts,
+		MaxAttempts:        record.MaxAttempts,
+		MinTime:            record.MinTime,
+		MaxTime:            record.MaxTime,
+		IsBlocked:          record.IsBlocked,
+		BlockReason:        record.BlockReason,
+		TimeoutAttempts:    record.TimeoutAttempts,
+		MaxTimeoutAttempts: record.MaxTimeoutAttempts,
+		BlockedUntil:       record.BlockedUntil,
+		Site:               record.Site,
+		Seed:               record.Seed,
+	}, nil
+}
@@ -0,0 +1,403 @@
+package persistence
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"captcha-service/internal/domain/dto"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is an in-process server speaking just enough RESP for the
+// repository: PING, AUTH, SELECT, SET with PX and NX, GET, DEL and EVAL of
+// the repository's own scripts.
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	values   map[string]string
+	expires  map[string]time.Time
+	ttls     map[string]time.Duration
+}
+
+func startFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	f := &fakeRedis{
+		listener: listener,
+		values:   make(map[string]string),
+		expires:  make(map[string]time.Time),
+		ttls:     make(map[string]time.Duration),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go f.serve()
+	return f
+}
+
+func (f *fakeRedis) addr() string {
+	return f.listener.Addr().String()
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, f.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "AUTH", "SELECT":
+		return "+OK\r\n"
+	case "SET":
+		nx := len(args) == 6 && strings.ToUpper(args[3]) == "NX"
+		if nx {
+			args = append(args[:3], args[4:]...)
+		}
+		if len(args) != 5 || strings.ToUpper(args[3]) != "PX" {
+			return "-ERR syntax error\r\n"
+		}
+		ms, err := strconv.ParseInt(args[4], 10, 64)
+		if err != nil || ms <= 0 {
+			return "-ERR invalid expire time in 'set' command\r\n"
+		}
+		if _, exists := f.lookup(args[1]); exists && nx {
+			return "$-1\r\n"
+		}
+		ttl := time.Duration(ms) * time.Millisecond
+		f.values[args[1]] = args[2]
+		f.expires[args[1]] = time.Now().Add(ttl)
+		f.ttls[args[1]] = ttl
+		return "+OK\r\n"
+	case "GET":
+		value, ok := f.lookup(args[1])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "DEL":
+		var deleted int
+		for _, key := range args[1:] {
+			if _, ok := f.lookup(key); ok {
+				delete(f.values, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "EVAL":
+		if len(args) < 5 || args[2] != "1" {
+			return "-ERR wrong number of arguments for 'eval' command\r\n"
+		}
+		if value, ok := f.lookup(args[3]); !ok || value != args[4] {
+			return ":0\r\n"
+		}
+		switch {
+		case args[1] == compareAndDeleteScript && len(args) == 5:
+			delete(f.values, args[3])
+		case args[1] == compareAndSetScript && len(args) == 7:
+			ms, err := strconv.ParseInt(args[6], 10, 64)
+			if err != nil || ms <= 0 {
+				return "-ERR invalid expire time in 'set' command\r\n"
+			}
+			ttl := time.Duration(ms) * time.Millisecond
+			f.values[args[3]] = args[5]
+			f.expires[args[3]] = time.Now().Add(ttl)
+			f.ttls[args[3]] = ttl
+		default:
+			return "-ERR unknown script\r\n"
+		}
+		return ":1\r\n"
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func (f *fakeRedis) lookup(key string) (string, bool) {
+	value, ok := f.values[key]
+	if ok && time.Now().After(f.expires[key]) {
+		delete(f.values, key)
+		return "", false
+	}
+	return value, ok
+}
+
+func (f *fakeRedis) ttl(key string) time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ttls[key]
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil || line[0] != '*' || n < 1 {
+		return nil, fmt.Errorf("bad command header %q", line)
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func newTestRedisRepository(addr string) *RedisRepository {
+	client := redis.NewClient(redis.Options{Addr: addr, PoolSize: 2, Timeout: time.Second})
+	return NewRedisRepository(client, "test:challenge:")
+}
+
+func sliderChallenge(id string, ttl time.Duration) *entity.Challenge {
+	now := time.Now()
+	return &entity.Challenge{
+		ID:         id,
+		UserID:     "user-1",
+		Type:       entity.ChallengeTypeSliderPuzzle,
+		Complexity: 50,
+		Data: entity.SliderPuzzleData{
+			ChallengeData: dto.ChallengeData{TargetX: 123, TargetY: 45},
+			CanvasWidth:   entity.CanvasWidth,
+			CanvasHeight:  entity.CanvasHeight,
+		},
+		HTML:        "<html>not stored</html>",
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+		MaxAttempts: 3,
+		MinTime:     1000,
+		MaxTime:     30000,
+	}
+}
+
+func TestRedisRepositoryRoundTrip(t *testing.T) {
+	server := startFakeRedis(t)
+	repo := newTestRedisRepository(server.addr())
+	ctx := context.Background()
+
+	challenges := []*entity.Challenge{
+		sliderChallenge("slider_1", time.Minute),
+		{
+			ID:         "dragdrop_1",
+			UserID:     "user-2",
+			Type:       entity.ChallengeTypeDragDrop,
+			Complexity: 70,
+			Data: entity.DragDropData{
+				ChallengeData: entity.DragDropChallenge{
+					UserID:         "user-2",
+					TargetPosition: entity.Position{X: 250, Y: 100},
+					ObjectPosition: entity.Position{X: 30, Y: 40},
+					ObjectSize:     entity.Size{Width: 40, Height: 40},
+					TargetSize:     entity.Size{Width: 50, Height: 50},
+					Tolerance:      5,
+				},
+				CanvasWidth:  entity.CanvasWidth,
+				CanvasHeight: entity.CanvasHeight,
+			},
+			Site:      "shop.example",
+			ExpiresAt: time.Now().Add(time.Minute),
+			CreatedAt: time.Now(),
+		},
+		{
+			ID:   "steer_1",
+			Type: entity.ChallengeTypeSteerGame,
+			Data: entity.SteerGameData{
+				Start:      entity.Position{X: 40, Y: 150},
+				Goal:       entity.Position{X: 350, Y: 60},
+				GoalRadius: 20,
+				Obstacles:  []entity.Rect{{X: 150, Y: 0, Width: 20, Height: 120}},
+			},
+			ExpiresAt: time.Now().Add(time.Minute),
+			CreatedAt: time.Now(),
+		},
+	}
+
+	for _, challenge := range challenges {
+		require.NoError(t, repo.SaveChallenge(ctx, challenge))
+
+		loaded, err := repo.GetChallenge(ctx, challenge.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, challenge.ID, loaded.ID)
+		assert.Equal(t, challenge.UserID, loaded.UserID)
+		assert.Equal(t, challenge.Type, loaded.Type)
+		assert.Equal(t, challenge.Complexity, loaded.Complexity)
+		assert.Equal(t, challenge.Data, loaded.Data)
+		assert.Equal(t, challenge.Site, loaded.Site)
+		assert.True(t, challenge.ExpiresAt.Equal(loaded.ExpiresAt))
+		assert.Empty(t, loaded.HTML, "rendered HTML must not be persisted")
+	}
+}
+
+func TestRedisRepositoryTTLFollowsExpiresAt(t *testing.T) {
+	server := startFakeRedis(t)
+	repo := newTestRedisRepository(server.addr())
+	ctx := context.Background()
+
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("slider_ttl", 90*time.Second)))
+	assert.InDelta(t, float64(90*time.Second), float64(server.ttl("test:challenge:slider_ttl")), float64(time.Second))
+
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("slider_short", 50*time.Millisecond)))
+	time.Sleep(100 * time.Millisecond)
+
+	_, err := repo.GetChallenge(ctx, "slider_short")
+	assert.Error(t, err)
+
+	err = repo.SaveChallenge(ctx, sliderChallenge("slider_expired", -time.Second))
+	assert.Error(t, err)
+}
+
+func TestRedisRepositorySharedBetweenInstances(t *testing.T) {
+	server := startFakeRedis(t)
+	issuing := newTestRedisRepository(server.addr())
+	validating := newTestRedisRepository(server.addr())
+	ctx := context.Background()
+
+	require.NoError(t, issuing.SaveChallenge(ctx, sliderChallenge("slider_shared", time.Minute)))
+
+	loaded, err := validating.GetChallenge(ctx, "slider_shared")
+	require.NoError(t, err)
+	assert.Equal(t, 123, loaded.Data.(entity.SliderPuzzleData).ChallengeData.TargetX)
+
+	require.NoError(t, validating.DeleteChallenge(ctx, "slider_shared"))
+	_, err = issuing.GetChallenge(ctx, "slider_shared")
+	assert.Error(t, err)
+
+	stats := issuing.GetStats()
+	assert.Equal(t, int64(1), stats["saves"])
+	assert.Equal(t, int64(1), stats["misses"])
+}
+
+func TestRedisRepositoryUpdateChallenge(t *testing.T) {
+	server := startFakeRedis(t)
+	repo := newTestRedisRepository(server.addr())
+	other := newTestRedisRepository(server.addr())
+	ctx := context.Background()
+
+	require.NoError(t, repo.SaveChallenge(ctx, sliderChallenge("slider_update", time.Minute)))
+
+	calls := 0
+	err := repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		calls++
+		if calls == 1 {
+			// Another instance records an attempt between the read and the write.
+			require.NoError(t, other.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+				challenge.Attempts++
+				return false, nil
+			}))
+		}
+		challenge.Attempts++
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "a conflicting write starts the update over")
+
+	loaded, err := repo.GetChallenge(ctx, "slider_update")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), loaded.Attempts)
+	assert.InDelta(t, float64(time.Minute), float64(server.ttl("test:challenge:slider_update")), float64(time.Second))
+
+	err = repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		return false, entity.ErrChallengeBlocked
+	})
+	assert.ErrorIs(t, err, entity.ErrChallengeBlocked)
+
+	require.NoError(t, repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		return true, nil
+	}))
+	_, err = repo.GetChallenge(ctx, "slider_update")
+	assert.Error(t, err)
+
+	err = repo.UpdateChallenge(ctx, "slider_update", func(challenge *entity.Challenge) (bool, error) {
+		t.Fatal("update must not run for a removed challenge")
+		return false, nil
+	})
+	assert.Error(t, err)
+}
+
+func TestRedisRepositoryUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	repo := newTestRedisRepository(addr)
+	ctx := context.Background()
+
+	assert.Error(t, repo.SaveChallenge(ctx, sliderChallenge("slider_down", time.Minute)))
+	_, err = repo.GetChallenge(ctx, "slider_down")
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), repo.GetStats()["errors"])
+}
+
+func TestRedisTokenStoreRedeemsOnce(t *testing.T) {
+	server := startFakeRedis(t)
+	client := redis.NewClient(redis.Options{Addr: server.addr(), PoolSize: 2, Timeout: time.Second})
+	first := NewRedisTokenStore(client, "test:token:")
+	second := NewRedisTokenStore(client, "test:token:")
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Minute)
+
+	unused, err := first.Redeem(ctx, "token-1", expiresAt)
+	require.NoError(t, err)
+	assert.True(t, unused)
+
+	unused, err = second.Redeem(ctx, "token-1", expiresAt)
+	require.NoError(t, err)
+	assert.False(t, unused, "a token redeemed on one instance is used on all")
+
+	unused, err = second.Redeem(ctx, "token-2", expiresAt)
+	require.NoError(t, err)
+	assert.True(t, unused)
+	assert.Greater(t, server.ttl("test:token:token-1"), 59*time.Second)
+}
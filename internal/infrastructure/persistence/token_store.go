@@ -0,0 +1,71 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"captcha-service/internal/infrastructure/redis"
+)
+
+// MemoryTokenStore remembers redeemed verification tokens until they expire.
+// It only sees the tokens redeemed on this instance.
+type MemoryTokenStore struct {
+	mu        sync.Mutex
+	redeemed  map[string]time.Time
+	nextSweep time.Time
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		redeemed: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryTokenStore) Redeem(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextSweep) {
+		for id, expiry := range s.redeemed {
+			if now.After(expiry) {
+				delete(s.redeemed, id)
+			}
+		}
+		s.nextSweep = now.Add(time.Minute)
+	}
+
+	if _, used := s.redeemed[tokenID]; used {
+		return false, nil
+	}
+	s.redeemed[tokenID] = expiresAt
+	return true, nil
+}
+
+// RedisTokenStore shares redeemed tokens between instances, so a token can
+// be redeemed only once whichever instance verifies it.
+type RedisTokenStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func NewRedisTokenStore(client *redis.Client, keyPrefix string) *RedisTokenStore {
+	return &RedisTokenStore{
+		client:    client,
+		keyPrefix: keyPrefix,
+	}
+}
+
+func (s *RedisTokenStore) Redeem(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error) {
+	// Expired tokens are rejected before they get here; the key only has to
+	// outlive the token.
+	ttl := time.Until(expiresAt) + time.Second
+
+	first, err := s.client.SetNXPX(ctx, s.keyPrefix+tokenID, []byte("1"), ttl)
+	if err != nil {
+		return false, fmt.Errorf("failed to redeem token %s: %w", tokenID, err)
+	}
+	return first, nil
+}
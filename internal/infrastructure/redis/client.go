@@ -0,0 +1,268 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ErrNil is returned for a null reply, e.g. GET of a missing key.
+var ErrNil = errors.New("redis: nil reply")
+
+// Error is an error reply sent by the server.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+type Options struct {
+	Addr     string
+	Password string
+	DB       int
+	PoolSize int
+	Timeout  time.Duration
+}
+
+// Client is a minimal RESP2 client covering the commands the service needs.
+// Connections are pooled; a connection that saw any I/O error is dropped.
+type Client struct {
+	opts Options
+	pool chan *conn
+}
+
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func NewClient(opts Options) *Client {
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = time.Second
+	}
+
+	return &Client{
+		opts: opts,
+		pool: make(chan *conn, opts.PoolSize),
+	}
+}
+
+// Do sends one command and returns its reply: string for simple and bulk
+// strings, int64 for integers and []interface{} for arrays.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, c.opts.Timeout, args)
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) && !errors.Is(err, ErrNil) {
+		cn.Close()
+		return nil, err
+	}
+
+	c.put(cn)
+	return reply, err
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := c.Do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return []byte(value), nil
+}
+
+func (c *Client) SetPX(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.Do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+// SetNXPX sets key only if it does not exist yet and reports whether it did.
+func (c *Client) SetNXPX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	_, err := c.Do(ctx, "SET", key, string(value), "NX", "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if errors.Is(err, ErrNil) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Eval runs a Lua script on the server, which executes it atomically.
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...string) (interface{}, error) {
+	command := append([]string{"EVAL", script, strconv.Itoa(len(keys))}, keys...)
+	return c.Do(ctx, append(command, args...)...)
+}
+
+func (c *Client) Del(ctx context.Context, keys ...string) (int64, error) {
+	reply, err := c.Do(ctx, append([]string{"DEL"}, keys...)...)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := reply.(int64)
+	return n, nil
+}
+
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.pool:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.opts.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if c.opts.Password != "" {
+		if _, err := cn.do(ctx, c.opts.Timeout, []string{"AUTH", c.opts.Password}); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err := cn.do(ctx, c.opts.Timeout, []string{"SELECT", strconv.Itoa(c.opts.DB)}); err != nil {
+			cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.pool <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+func (cn *conn) do(ctx context.Context, timeout time.Duration, args []string) (interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if _, err := cn.Write(encodeCommand(args)); err != nil {
+		return nil, err
+	}
+	return readReply(cn.reader)
+}
+
+func encodeCommand(args []string) []byte {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	return buf
+}
+
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			item, err := readReply(r)
+			var replyErr Error
+			switch {
+			case errors.As(err, &replyErr):
+				items[i] = replyErr
+			case errors.Is(err, ErrNil):
+				items[i] = nil
+			case err != nil:
+				return nil, err
+			default:
+				items[i] = item
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
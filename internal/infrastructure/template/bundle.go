@@ -0,0 +1,279 @@
+package template
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Challenge pages must be self-contained: the page is all the client gets,
+// so nothing may be loaded from the proxy or anywhere else. Templates are
+// therefore bundled when they are loaded: stylesheets, scripts and images
+// referenced by a local path are inlined, images as data URIs, and all CSS,
+// JS and markup is minified. The bundle is parsed once and kept as the page
+// skeleton; rendering a challenge only executes it with the challenge data.
+//
+// Local paths are relative to the file that references them; paths starting
+// with / are relative to the parent of the templates directory, the working
+// directory of the services, so /backgrounds/1.png is backgrounds/1.png.
+
+var (
+	stylesheetTag = regexp.MustCompile(`(?is)<link\s[^>]*\brel=["']?stylesheet\b[^>]*>`)
+	scriptSrcTag  = regexp.MustCompile(`(?is)<script\s[^>]*\bsrc=["']([^"']*)["'][^>]*>\s*</script\s*>`)
+	hrefAttr      = regexp.MustCompile(`(?is)\bhref=["']([^"']*)["']`)
+	srcAttr       = regexp.MustCompile(`(?is)(\ssrc=)(["'])([^"']*)["']`)
+	cssURL        = regexp.MustCompile(`(?is)\burl\(\s*(["']?)([^"')]*)["']?\s*\)`)
+	embeddedBlock = regexp.MustCompile(`(?is)<(script|style)\b([^>]*)>(.*?)</(script|style)\s*>`)
+	urlScheme     = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+	htmlComment   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	scriptType    = regexp.MustCompile(`(?i)\btype=["']?([^"'\s>]*)`)
+)
+
+type bundler struct {
+	root string
+}
+
+// Compile bundles the template at path and parses it.
+func Compile(path string) (*template.Template, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return CompileSource(filepath.Base(path), filepath.Dir(path), string(source))
+}
+
+// CompileSource bundles source as if it were a template in dir and parses it
+// under name.
+func CompileSource(name, dir, source string) (*template.Template, error) {
+	b := bundler{root: filepath.Dir(filepath.Clean(dir))}
+
+	bundled, err := b.bundle(dir, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bundle %s: %w", name, err)
+	}
+	return template.New(name).Parse(bundled)
+}
+
+func (b bundler) bundle(dir, source string) (string, error) {
+	var failed error
+	fail := func(err error) string {
+		if failed == nil {
+			failed = err
+		}
+		return ""
+	}
+
+	source = stylesheetTag.ReplaceAllStringFunc(source, func(tag string) string {
+		href := hrefAttr.FindStringSubmatch(tag)
+		if href == nil || !isLocalRef(href[1]) {
+			return tag
+		}
+		path, err := b.resolve(dir, href[1])
+		if err != nil {
+			return fail(err)
+		}
+		css, err := os.ReadFile(path)
+		if err != nil {
+			return fail(err)
+		}
+		// url() inside the stylesheet is relative to the stylesheet.
+		inlined, err := b.inlineCSSURLs(filepath.Dir(path), string(css))
+		if err != nil {
+			return fail(err)
+		}
+		return "<style>" + inlined + "</style>"
+	})
+
+	source = scriptSrcTag.ReplaceAllStringFunc(source, func(tag string) string {
+		src := scriptSrcTag.FindStringSubmatch(tag)[1]
+		if !isLocalRef(src) {
+			return tag
+		}
+		path, err := b.resolve(dir, src)
+		if err != nil {
+			return fail(err)
+		}
+		code, err := os.ReadFile(path)
+		if err != nil {
+			return fail(err)
+		}
+		return "<script>" + string(code) + "</script>"
+	})
+	if failed != nil {
+		return "", failed
+	}
+
+	// Scripts and styles are minified as such; only the markup between them
+	// can reference assets in attributes.
+	var out strings.Builder
+	last := 0
+	for _, loc := range embeddedBlock.FindAllStringSubmatchIndex(source, -1) {
+		markup, err := b.bundleMarkup(dir, source[last:loc[0]])
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(markup)
+
+		tag := strings.ToLower(source[loc[2]:loc[3]])
+		if tag != strings.ToLower(source[loc[8]:loc[9]]) {
+			return "", fmt.Errorf("<%s> closed by </%s>", tag, source[loc[8]:loc[9]])
+		}
+		attrs, body := source[loc[4]:loc[5]], source[loc[6]:loc[7]]
+
+		switch {
+		case tag == "style":
+			inlined, err := b.inlineCSSURLs(dir, body)
+			if err != nil {
+				return "", err
+			}
+			body = MinifyCSS(inlined)
+		case isJavaScript(attrs):
+			body = MinifyJS(body)
+		}
+		out.WriteString("<" + tag + attrs + ">" + body + "</" + tag + ">")
+		last = loc[1]
+	}
+
+	markup, err := b.bundleMarkup(dir, source[last:])
+	if err != nil {
+		return "", err
+	}
+	out.WriteString(markup)
+	return out.String(), nil
+}
+
+func (b bundler) bundleMarkup(dir, markup string) (string, error) {
+	var failed error
+	markup = srcAttr.ReplaceAllStringFunc(markup, func(attr string) string {
+		m := srcAttr.FindStringSubmatch(attr)
+		if !isLocalRef(m[3]) {
+			return attr
+		}
+		uri, err := b.dataURI(dir, m[3])
+		if err != nil {
+			if failed == nil {
+				failed = err
+			}
+			return attr
+		}
+		return m[1] + m[2] + uri + m[2]
+	})
+	if failed != nil {
+		return "", failed
+	}
+	return minifyMarkup(markup), nil
+}
+
+func (b bundler) inlineCSSURLs(dir, css string) (string, error) {
+	var failed error
+	css = cssURL.ReplaceAllStringFunc(css, func(ref string) string {
+		url := cssURL.FindStringSubmatch(ref)[2]
+		if !isLocalRef(url) {
+			return ref
+		}
+		uri, err := b.dataURI(dir, url)
+		if err != nil {
+			if failed == nil {
+				failed = err
+			}
+			return ref
+		}
+		return `url("` + uri + `")`
+	})
+	return css, failed
+}
+
+func (b bundler) dataURI(dir, ref string) (string, error) {
+	path, err := b.resolve(dir, ref)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	mediaType := mime.TypeByExtension(filepath.Ext(path))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(data)
+	}
+	return "data:" + mediaType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// resolve maps a local reference to a file within the bundle root.
+func (b bundler) resolve(dir, ref string) (string, error) {
+	ref = strings.SplitN(strings.SplitN(ref, "#", 2)[0], "?", 2)[0]
+
+	var path string
+	if strings.HasPrefix(ref, "/") {
+		path = filepath.Join(b.root, filepath.FromSlash(ref))
+	} else {
+		path = filepath.Join(dir, filepath.FromSlash(ref))
+	}
+
+	rel, err := filepath.Rel(b.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside of %s", ref, b.root)
+	}
+	return path, nil
+}
+
+// isLocalRef reports whether ref names a file to inline: not empty, not a
+// template action, not a URL with a scheme such as data: or https: and not
+// protocol-relative or a fragment.
+func isLocalRef(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	return ref != "" && !strings.Contains(ref, "{{") && !urlScheme.MatchString(ref) &&
+		!strings.HasPrefix(ref, "//") && !strings.HasPrefix(ref, "#")
+}
+
+func isJavaScript(attrs string) bool {
+	m := scriptType.FindStringSubmatch(attrs)
+	if m == nil {
+		return true
+	}
+	switch strings.ToLower(m[1]) {
+	case "", "module", "text/javascript", "application/javascript":
+		return true
+	default:
+		return false
+	}
+}
+
+// InsertBeforeBodyEnd adds fragment at the end of the page body, or at the
+// end of the page if it has no closing body tag.
+func InsertBeforeBodyEnd(page, fragment string) string {
+	at := strings.LastIndex(strings.ToLower(page), "</body")
+	if at < 0 {
+		return page + fragment
+	}
+	return page[:at] + fragment + page[at:]
+}
+
+// minifyMarkup drops comments and indentation. Pages with preformatted text
+// are left alone, since whitespace is part of it.
+func minifyMarkup(markup string) string {
+	lower := strings.ToLower(markup)
+	if strings.Contains(lower, "<pre") || strings.Contains(lower, "<textarea") {
+		return markup
+	}
+
+	markup = htmlComment.ReplaceAllString(markup, "")
+	lines := strings.Split(markup, "\n")
+	kept := lines[:0]
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		// Keep the line breaks at the edges, which separate the markup from
+		// the scripts and styles around it.
+		if line != "" || i == 0 || i == len(lines)-1 {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,156 @@
+package template
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestCompileInlinesAssets(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"backgrounds/bg.png": "png",
+		"templates/icon.svg": "<svg/>",
+		"templates/page.css": "/* page */\nbody {\n    background: url('/backgrounds/bg.png');\n}\n",
+		"templates/page.js":  "// helper\nfunction twice(x) {\n    return x * 2;\n}\n",
+		"templates/challenge.html": `<!DOCTYPE html>
+<html>
+<head>
+    <link rel="stylesheet" href="page.css">
+    <!-- no external requests -->
+</head>
+<body>
+    <img src="icon.svg" alt="">
+    <img src="{{.Canvas}}" alt="">
+    <img src="https://example.com/logo.png" alt="">
+    <script src="page.js"></script>
+    <script>
+        const id = '{{.ID}}'; // set per challenge
+    </script>
+</body>
+</html>
+`,
+	})
+
+	tmpl, err := Compile(filepath.Join(root, "templates", "challenge.html"))
+	require.NoError(t, err)
+
+	var page bytes.Buffer
+	require.NoError(t, tmpl.Execute(&page, map[string]interface{}{
+		"ID":     "abc",
+		"Canvas": template.URL("data:image/png;base64,AA=="),
+	}))
+	html := page.String()
+
+	assert.Contains(t, html, `<style>body{background:url("data:image/png;base64,cG5n")}</style>`)
+	assert.Contains(t, html, `<img src="data:image/svg+xml;base64,PHN2Zy8+" alt="">`)
+	assert.Contains(t, html, `<img src="data:image/png;base64,AA==" alt="">`)
+	assert.Contains(t, html, `<img src="https://example.com/logo.png" alt="">`)
+	assert.Contains(t, html, "<script>function twice(x) {\nreturn x * 2;\n}</script>")
+	assert.Contains(t, html, `<script>const id = 'abc';</script>`)
+	assert.NotContains(t, html, "no external requests")
+	assert.NotContains(t, html, "    ")
+}
+
+func TestCompileRejectsMissingAssets(t *testing.T) {
+	root := t.TempDir()
+	cases := map[string]string{
+		"stylesheet": `<link rel="stylesheet" href="missing.css">`,
+		"script":     `<script src="missing.js"></script>`,
+		"image":      `<img src="/backgrounds/missing.png">`,
+		"css url":    `<style>body { background: url(missing.png) }</style>`,
+		"outside":    `<img src="../../etc/passwd">`,
+	}
+
+	for name, source := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := CompileSource(name, filepath.Join(root, "templates"), source)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMinifyJS(t *testing.T) {
+	cases := map[string]struct{ in, out string }{
+		"comments and indentation": {
+			in:  "    var a = 1;   // one\n\n    /* two */ var b = 2;\n",
+			out: "var a = 1;\nvar b = 2;",
+		},
+		"comment spanning lines ends a statement": {
+			in:  "var a = 1 /*\n*/ var b = 2",
+			out: "var a = 1\nvar b = 2",
+		},
+		"strings": {
+			in:  `var s = "a  // b", t = 'c /* d */';`,
+			out: `var s = "a  // b", t = 'c /* d */';`,
+		},
+		"template literal": {
+			in:  "var s = `a  ${ {x: '}'}.x }  // b`;",
+			out: "var s = `a  ${ {x: '}'}.x }  // b`;",
+		},
+		"regexp": {
+			in:  "var r = /[/]\\/ //g; return /a  b/.test(s);",
+			out: "var r = /[/]\\/ //g; return /a  b/.test(s);",
+		},
+		"division": {
+			in:  "var x = a / b // c\nvar y = (a) / 2 / c;",
+			out: "var x = a / b\nvar y = (a) / 2 / c;",
+		},
+		"template actions": {
+			in:  "var id = {{.ID}} / 2; // {{.Comment}}\nvar s = '{{.Name}}';",
+			out: "var id = {{.ID}} / 2;\nvar s = '{{.Name}}';",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.out, MinifyJS(c.in))
+		})
+	}
+}
+
+func TestMinifyCSS(t *testing.T) {
+	cases := map[string]struct{ in, out string }{
+		"whitespace": {
+			in:  "body {\n    margin : 0 ;\n    font-family: Arial, sans-serif;\n}\n",
+			out: "body{margin :0;font-family:Arial,sans-serif}",
+		},
+		"descendant pseudo-class": {
+			in:  ".a :hover { color: red }",
+			out: ".a :hover{color:red}",
+		},
+		"strings and calc": {
+			in:  `.a::before { content: "a  {b}"; width: calc(100% - 10px) }`,
+			out: `.a::before{content:"a  {b}";width:calc(100% - 10px)}`,
+		},
+		"template actions": {
+			in:  ".canvas { width: {{.Width}}px; /* canvas */ }",
+			out: ".canvas{width:{{.Width}}px}",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.out, MinifyCSS(c.in))
+		})
+	}
+}
+
+func TestInsertBeforeBodyEnd(t *testing.T) {
+	assert.Equal(t, "<body>a<script></script></BODY>", InsertBeforeBodyEnd("<body>a</BODY>", "<script></script>"))
+	assert.Equal(t, "a<script></script>", InsertBeforeBodyEnd("a", "<script></script>"))
+}
@@ -0,0 +1,244 @@
+package template
+
+import "strings"
+
+// The minifiers only drop what is certainly insignificant, so they need no
+// real parser: tokens are never joined or reordered. Template actions are
+// copied as they are, wherever they appear.
+
+var regexpKeywords = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true,
+	"in": true, "of": true, "new": true, "delete": true, "void": true,
+	"throw": true, "yield": true, "await": true,
+}
+
+// MinifyJS strips comments, indentation, trailing blanks and blank lines,
+// and collapses other runs of blanks to one space. Line breaks are kept, so
+// automatic semicolon insertion works as before.
+func MinifyJS(src string) string {
+	var out strings.Builder
+	out.Grow(len(src))
+
+	var prev byte // last byte written, to tell a regexp from a division
+	var word string
+	lineStart, blank := true, false
+
+	write := func(s string) {
+		if blank && !lineStart {
+			out.WriteByte(' ')
+		}
+		out.WriteString(s)
+		lineStart, blank = false, false
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n' || c == '\r':
+			if !lineStart {
+				out.WriteByte('\n')
+			}
+			lineStart, blank = true, false
+			i++
+		case c == ' ' || c == '\t' || c == '\f' || c == '\v':
+			blank = true
+			i++
+		case strings.HasPrefix(src[i:], "{{"):
+			end := actionEnd(src, i)
+			write(src[i:end])
+			prev, word = 'a', ""
+			i = end
+		case strings.HasPrefix(src[i:], "//"):
+			if end := strings.IndexAny(src[i:], "\r\n"); end >= 0 {
+				i += end
+			} else {
+				i = len(src)
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+				break
+			}
+			comment := src[i : i+2+end+2]
+			i += len(comment)
+			// A comment spanning lines ends a statement just like a line break.
+			if strings.ContainsAny(comment, "\r\n") {
+				if !lineStart {
+					out.WriteByte('\n')
+				}
+				lineStart, blank = true, false
+			} else {
+				blank = true
+			}
+		case c == '\'' || c == '"' || c == '`':
+			end := quotedEnd(src, i)
+			write(src[i:end])
+			prev, word = c, ""
+			i = end
+		case c == '/' && regexpAllowed(prev, word):
+			end := regexpEnd(src, i)
+			write(src[i:end])
+			prev, word = 'a', ""
+			i = end
+		default:
+			if isIdentByte(c) {
+				if isIdentByte(prev) && !blank && !lineStart {
+					word += string(c)
+				} else {
+					word = string(c)
+				}
+			} else {
+				word = ""
+			}
+			write(src[i : i+1])
+			prev = c
+			i++
+		}
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
+
+// MinifyCSS strips comments and collapses whitespace, dropping it around
+// braces, semicolons and commas, after colons and before a closing brace
+// along with the last semicolon.
+func MinifyCSS(src string) string {
+	var out []byte
+	blank := false
+
+	write := func(s string) {
+		if blank && len(out) > 0 && !strings.ContainsRune("{};,:", rune(out[len(out)-1])) &&
+			!strings.ContainsRune("{};,", rune(s[0])) {
+			out = append(out, ' ')
+		}
+		out = append(out, s...)
+		blank = false
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case strings.HasPrefix(src[i:], "{{"):
+			end := actionEnd(src, i)
+			write(src[i:end])
+			i = end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += 2 + end + 2
+			}
+			blank = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			blank = true
+			i++
+		case c == '\'' || c == '"':
+			end := quotedEnd(src, i)
+			write(src[i:end])
+			i = end
+		default:
+			if c == '}' && len(out) > 0 && out[len(out)-1] == ';' {
+				out = out[:len(out)-1]
+			}
+			write(src[i : i+1])
+			i++
+		}
+	}
+	return string(out)
+}
+
+// actionEnd returns the end of the template action starting at i.
+func actionEnd(src string, i int) int {
+	end := strings.Index(src[i:], "}}")
+	if end < 0 {
+		return len(src)
+	}
+	return i + end + 2
+}
+
+// quotedEnd returns the end of the string or template literal starting at
+// i. An unterminated string ends at the line break.
+func quotedEnd(src string, i int) int {
+	quote := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch c := src[j]; {
+		case c == '\\':
+			j++
+		case c == quote:
+			return j + 1
+		case quote == '`' && c == '$' && j+1 < len(src) && src[j+1] == '{':
+			j = substitutionEnd(src, j+2) - 1
+		case (c == '\n' || c == '\r') && quote != '`':
+			return j
+		}
+	}
+	return len(src)
+}
+
+// substitutionEnd returns the end of a ${...} substitution whose body starts
+// at i.
+func substitutionEnd(src string, i int) int {
+	depth := 0
+	for j := i; j < len(src); j++ {
+		switch src[j] {
+		case '\'', '"', '`':
+			j = quotedEnd(src, j) - 1
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return j + 1
+			}
+			depth--
+		}
+	}
+	return len(src)
+}
+
+// regexpEnd returns the end of the regexp literal, flags included, starting
+// at i.
+func regexpEnd(src string, i int) int {
+	inClass := false
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if inClass {
+				break
+			}
+			for j++; j < len(src) && isIdentByte(src[j]); j++ {
+			}
+			return j
+		case '\n', '\r':
+			return j
+		}
+	}
+	return len(src)
+}
+
+// regexpAllowed reports whether a slash after prev, the last byte written,
+// and word, the identifier it ends, starts a regexp rather than a division.
+func regexpAllowed(prev byte, word string) bool {
+	if word != "" {
+		return regexpKeywords[word]
+	}
+	switch {
+	case prev == 0:
+		return true
+	case isIdentByte(prev):
+		return false
+	default:
+		return !strings.ContainsRune(")]}'\"`", rune(prev))
+	}
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
@@ -5,7 +5,10 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"captcha-service/internal/domain/entity"
 	"captcha-service/pkg/logger"
@@ -13,6 +16,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// The client runtime injected into every challenge page: the event frame
+// codec, generated from entity.EncodeEventFrame by cmd/eventcodec-js, and the
+// postMessage bridge built on it.
+const (
+	eventCodecFile = "event_codec.js"
+	runtimeFile    = "captcha_runtime.js"
+)
+
 type NewTemplateData interface {
 	GetData() map[string]interface{}
 }
@@ -23,6 +34,7 @@ type TemplateEngine interface {
 
 type TemplateEngineService struct {
 	templates map[string]*template.Template
+	runtime   string
 	basePath  string
 }
 
@@ -41,12 +53,18 @@ func (tes *TemplateEngineService) loadTemplates() {
 	templateFiles := map[string]string{
 		"slider_puzzle": "slider_puzzle.html",
 		"drag_drop":     "drag_drop.html",
+		"steer_game":    "steer_game.html",
+		"rotation":      "rotation.html",
+		"click_order":   "click_order.html",
+		"audio":         "audio.html",
 		"blocked":       "blocked.html",
 		"demo":          "demo.html",
 	}
 
+	// Each template is bundled and parsed once; rendering only executes the
+	// result with the data of a challenge.
 	for name, filename := range templateFiles {
-		tmpl, err := template.ParseFiles(filepath.Join(tes.basePath, filename))
+		tmpl, err := Compile(filepath.Join(tes.basePath, filename))
 		if err != nil {
 			logger.Error("Failed to load template",
 				zap.String("template", name),
@@ -58,6 +76,44 @@ func (tes *TemplateEngineService) loadTemplates() {
 		tes.templates[name] = tmpl
 		logger.Debug("Loaded template", zap.String("template", name))
 	}
+
+	var runtime strings.Builder
+	for _, filename := range []string{eventCodecFile, runtimeFile} {
+		code, err := os.ReadFile(filepath.Join(tes.basePath, filename))
+		if err != nil {
+			logger.Error("Failed to load client runtime", zap.String("file", filename), zap.Error(err))
+			return
+		}
+		runtime.Write(code)
+		runtime.WriteString("\n")
+	}
+	tes.runtime = "<script>" + MinifyJS(runtime.String()) + "</script>\n"
+}
+
+// runtimeAnchors are the tags the runtime goes after, in order of preference,
+// so that it runs before any script of the page.
+var runtimeAnchors = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)<head(\s[^>]*)?>`),
+	regexp.MustCompile(`(?i)<html(\s[^>]*)?>`),
+	regexp.MustCompile(`(?i)<!doctype[^>]*>`),
+}
+
+// InjectRuntime adds the client runtime to a challenge page, so that pages,
+// including those of plugins, only carry their own rendering code. Pages
+// that already have it are returned unchanged.
+func (tes *TemplateEngineService) InjectRuntime(html string) string {
+	if tes.runtime == "" || strings.Contains(html, "var CaptchaRuntime =") {
+		return html
+	}
+
+	at := 0
+	for _, anchor := range runtimeAnchors {
+		if loc := anchor.FindStringIndex(html); loc != nil {
+			at = loc[1]
+			break
+		}
+	}
+	return html[:at] + "\n" + tes.runtime + html[at:]
 }
 
 func (tes *TemplateEngineService) RenderSliderPuzzle(ctx context.Context, challenge *entity.Challenge) (string, error) {
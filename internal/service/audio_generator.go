@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"math"
+	"math/rand"
+	"strings"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/audio"
+)
+
+const (
+	// Share of the clip that has to have played before an answer is taken;
+	// the tail is silence.
+	audioMinListenShare = 0.8
+)
+
+type AudioRenderer interface {
+	RenderBeepSequence(rng *rand.Rand, seq audio.BeepSequence) (*audio.Clip, error)
+}
+
+// AudioGenerator is the accessible alternative to the visual challenges: the
+// user listens to groups of beeps and types how many beeps each group has.
+type AudioGenerator struct {
+	config         *config.CaptchaConfig
+	difficulty     *DifficultyProfiles
+	repo           ChallengeRepository
+	templateEngine TemplateEngine
+	renderer       AudioRenderer
+
+	challengeSource
+}
+
+func NewAudioGenerator(config *config.CaptchaConfig, difficulty *DifficultyProfiles, repo ChallengeRepository, templateEngine TemplateEngine, renderer AudioRenderer) *AudioGenerator {
+	return &AudioGenerator{
+		config:         config,
+		difficulty:     difficulty,
+		repo:           repo,
+		templateEngine: templateEngine,
+		renderer:       renderer,
+	}
+}
+
+func (g *AudioGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	if complexity < 0 || complexity > 100 {
+		complexity = g.config.ComplexityMedium
+	}
+
+	params := g.difficulty.At(entity.ChallengeTypeAudio, complexity)
+	seed, err := g.newSeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	count := max(1, params.Int("digits"))
+	noise := params.Float("noise")
+
+	digits := make([]int, count)
+	var code strings.Builder
+	for i := range digits {
+		digits[i] = 1 + rng.Intn(9)
+		code.WriteByte(byte('0' + digits[i]))
+	}
+
+	clip, err := g.renderer.RenderBeepSequence(rng, audio.BeepSequence{
+		Digits: digits,
+		Noise:  noise,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render audio: %w", err)
+	}
+
+	challengeID, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := g.templateEngine.Render("audio", map[string]interface{}{
+		"Lang":        LocaleFromContext(ctx),
+		"Text":        localizedTexts(ctx),
+		"ChallengeID": challengeID,
+		"UserID":      userID,
+		"DigitCount":  count,
+		"AudioURI":    template.URL(clip.URI),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render audio template: %w", err)
+	}
+
+	now := g.now()
+	challenge := &entity.Challenge{
+		ID:         challengeID,
+		Type:       entity.ChallengeTypeAudio,
+		UserID:     userID,
+		Complexity: complexity,
+		Data: entity.AudioData{
+			Code:       code.String(),
+			DurationMs: clip.DurationMs,
+		},
+		HTML:               html,
+		ExpiresAt:          now.Add(params.Expiration()),
+		CreatedAt:          now,
+		Attempts:           0,
+		MaxAttempts:        g.config.MaxAttempts,
+		MinTime:            int64(clip.DurationMs) + params.MinTimeMs(),
+		MaxTime:            int64(clip.DurationMs) + params.MaxTimeMs(),
+		IsBlocked:          false,
+		BlockReason:        "",
+		TimeoutAttempts:    0,
+		MaxTimeoutAttempts: g.config.MaxTimeoutAttempts,
+		Seed:               g.recordedSeed(seed),
+	}
+
+	return challenge, nil
+}
+
+// TypedAnswer marks the answer as typed: there is no pointer trajectory to
+// score.
+func (g *AudioGenerator) TypedAnswer() {}
+
+// Validate compares the typed digits with the code, ignoring spaces and other
+// separators, and rejects answers given before the clip could have been heard.
+func (g *AudioGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	answerMap, ok := answer.(map[string]interface{})
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат ответа")
+	}
+
+	typed, ok := answerMap["code"].(string)
+	if !ok {
+		return false, 0, fmt.Errorf("неверный код ответа")
+	}
+
+	data, ok := challenge.Data.(entity.AudioData)
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат данных челленджа")
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, typed)
+	if digits != data.Code {
+		return false, 0, nil
+	}
+
+	now := g.now()
+	start := challenge.CreatedAt
+	if challenge.StartTime != nil {
+		start = *challenge.StartTime
+	}
+	if float64(now.Sub(start).Milliseconds()) < audioMinListenShare*float64(data.DurationMs) {
+		return false, 0, nil
+	}
+
+	timing := timingScore(challenge, now)
+	confidence := int32(math.Round(100 * (0.6 + 0.4*timing)))
+
+	return true, confidence, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudioValidate(t *testing.T) {
+	_, challenge := generateAt(t, entity.ChallengeTypeAudio, 50, 0)
+	data := challenge.Data.(entity.AudioData)
+	require.Len(t, data.Code, 4)
+	assert.GreaterOrEqual(t, challenge.MinTime, int64(data.DurationMs), "MinTime covers the clip")
+
+	listened := time.Duration(data.DurationMs)*time.Millisecond + time.Second
+	wrong := []byte(data.Code)
+	wrong[0] = '0' + (wrong[0]-'0')%9 + 1
+
+	tests := []struct {
+		name    string
+		code    string
+		elapsed time.Duration
+		valid   bool
+	}{
+		{name: "correct", code: data.Code, elapsed: listened, valid: true},
+		{name: "separators", code: string(data.Code[0]) + " " + data.Code[1:2] + "-" + data.Code[2:], elapsed: listened, valid: true},
+		{name: "wrong count", code: string(wrong), elapsed: listened, valid: false},
+		{name: "missing digit", code: data.Code[1:], elapsed: listened, valid: false},
+		{name: "extra digit", code: data.Code + "1", elapsed: listened, valid: false},
+		{name: "before the clip played", code: data.Code, elapsed: time.Duration(data.DurationMs) * time.Millisecond / 2, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, challenge := generateAt(t, entity.ChallengeTypeAudio, 50, tt.elapsed)
+
+			valid, _, err := generator.Validate(map[string]interface{}{"code": tt.code}, challenge)
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, valid)
+		})
+	}
+}
@@ -25,7 +25,7 @@ type UserBlockRepository interface {
 	RemoveBlockedUser(userID string) error
 	GetAllBlockedUsers() ([]*entity.BlockedUser, error)
 	IsUserBlocked(userID string) bool
-	BlockUser(userID string, reason string) error
+	BlockUser(userID string, duration time.Duration, reason string) error
 	CleanupExpiredBlocks() error
 }
 
@@ -34,6 +34,8 @@ type BalancerServiceInterface interface {
 	GetInstances() ([]*entity.Instance, error)
 	IsUserBlocked(userID string) bool
 	BlockUser(userID string, reason string) error
+	UnblockUser(userID string) error
+	GetBlockedUsers() ([]*entity.BlockedUser, error)
 	StartCleanup()
 	Stop()
 }
@@ -115,12 +117,15 @@ func (s *BalancerService) GetInstancesGRPC(ctx context.Context, req *protoBalanc
 }
 
 func (s *BalancerService) CheckUserBlocked(ctx context.Context, req *protoBalancer.CheckUserBlockedRequest) (*protoBalancer.CheckUserBlockedResponse, error) {
-	isBlocked := s.userBlockRepo.IsUserBlocked(req.UserId)
-	var blockedUser *entity.BlockedUser
-	if isBlocked {
-		blockedUser, _ = s.userBlockRepo.GetBlockedUser(req.UserId)
+	if !s.userBlockRepo.IsUserBlocked(req.UserId) {
+		return &protoBalancer.CheckUserBlockedResponse{
+			IsBlocked: false,
+		}, nil
 	}
-	if !isBlocked {
+
+	blockedUser, err := s.userBlockRepo.GetBlockedUser(req.UserId)
+	if err != nil {
+		// Unblocked between the two lookups.
 		return &protoBalancer.CheckUserBlockedResponse{
 			IsBlocked: false,
 		}, nil
@@ -130,6 +135,7 @@ func (s *BalancerService) CheckUserBlocked(ctx context.Context, req *protoBalanc
 		IsBlocked:    true,
 		Reason:       blockedUser.Reason,
 		BlockedUntil: blockedUser.BlockedUntil.Unix(),
+		BlockedAtMs:  blockedUser.BlockedAt.UnixMilli(),
 	}, nil
 }
 
@@ -138,24 +144,133 @@ func (s *BalancerService) IsUserBlocked(userID string) bool {
 }
 
 func (s *BalancerService) BlockUser(userID, reason string) error {
-	return s.userBlockRepo.BlockUser(userID, reason)
+	return s.userBlockRepo.BlockUser(userID, s.blockDuration(0), reason)
 }
 
-func (s *BalancerService) BlockUserGRPC(ctx context.Context, req *protoBalancer.BlockUserRequest) (*protoBalancer.BlockUserResponse, error) {
-	err := s.BlockUser(req.UserId, req.Reason)
+func (s *BalancerService) UnblockUser(userID string) error {
+	return s.userBlockRepo.RemoveBlockedUser(userID)
+}
+
+// GetBlockedUsers returns the users whose block is still in force.
+func (s *BalancerService) GetBlockedUsers() ([]*entity.BlockedUser, error) {
+	blockedUsers, err := s.userBlockRepo.GetAllBlockedUsers()
 	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	active := make([]*entity.BlockedUser, 0, len(blockedUsers))
+	for _, blockedUser := range blockedUsers {
+		if now.Before(blockedUser.BlockedUntil) {
+			active = append(active, blockedUser)
+		}
+	}
+	return active, nil
+}
+
+// blockDuration falls back to the configured duration when the caller did
+// not ask for a specific one.
+func (s *BalancerService) blockDuration(minutes int32) time.Duration {
+	if minutes <= 0 {
+		minutes = s.config.BlockDurationMin
+	}
+	if minutes <= 0 {
+		minutes = 5
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func (s *BalancerService) BlockUserGRPC(ctx context.Context, req *protoBalancer.BlockUserRequest) (*protoBalancer.BlockUserResponse, error) {
+	if req.UserId == "" {
+		return &protoBalancer.BlockUserResponse{
+			Status:  protoBalancer.BlockUserResponse_ERROR,
+			Message: "user_id is required",
+		}, nil
+	}
+
+	blockedAt := time.Now()
+	if req.BlockedAtMs > 0 {
+		blockedAt = time.UnixMilli(req.BlockedAtMs)
+	}
+
+	// Proxies relay the blocks they set; only a newer one replaces the
+	// recorded block, so relaying never extends it.
+	if blockedUser, err := s.userBlockRepo.GetBlockedUser(req.UserId); err == nil && !blockedAt.After(blockedUser.BlockedAt) {
+		return &protoBalancer.BlockUserResponse{
+			Status:  protoBalancer.BlockUserResponse_SUCCESS,
+			Message: "User already blocked",
+		}, nil
+	}
+
+	duration := s.blockDuration(req.DurationMinutes)
+	if err := s.userBlockRepo.SaveBlockedUser(&entity.BlockedUser{
+		UserID:       req.UserId,
+		BlockedUntil: blockedAt.Add(duration),
+		Reason:       req.Reason,
+		BlockedAt:    blockedAt,
+	}); err != nil {
 		return &protoBalancer.BlockUserResponse{
 			Status:  protoBalancer.BlockUserResponse_ERROR,
 			Message: err.Error(),
 		}, nil
 	}
 
+	logger.Info("User blocked",
+		zap.String("user_id", req.UserId),
+		zap.String("reason", req.Reason),
+		zap.Duration("duration", duration))
+
 	return &protoBalancer.BlockUserResponse{
 		Status:  protoBalancer.BlockUserResponse_SUCCESS,
 		Message: "User blocked successfully",
 	}, nil
 }
 
+func (s *BalancerService) UnblockUserGRPC(ctx context.Context, req *protoBalancer.UnblockUserRequest) (*protoBalancer.UnblockUserResponse, error) {
+	if req.UserId == "" {
+		return &protoBalancer.UnblockUserResponse{
+			Status:  protoBalancer.UnblockUserResponse_ERROR,
+			Message: "user_id is required",
+		}, nil
+	}
+
+	if err := s.UnblockUser(req.UserId); err != nil {
+		return &protoBalancer.UnblockUserResponse{
+			Status:  protoBalancer.UnblockUserResponse_ERROR,
+			Message: err.Error(),
+		}, nil
+	}
+
+	logger.Info("User unblocked", zap.String("user_id", req.UserId))
+
+	return &protoBalancer.UnblockUserResponse{
+		Status:  protoBalancer.UnblockUserResponse_SUCCESS,
+		Message: "User unblocked successfully",
+	}, nil
+}
+
+func (s *BalancerService) ListBlockedUsersGRPC(ctx context.Context, req *protoBalancer.ListBlockedUsersRequest) (*protoBalancer.ListBlockedUsersResponse, error) {
+	blockedUsers, err := s.GetBlockedUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*protoBalancer.BlockedUserInfo, 0, len(blockedUsers))
+	for _, blockedUser := range blockedUsers {
+		users = append(users, &protoBalancer.BlockedUserInfo{
+			UserId:       blockedUser.UserID,
+			Reason:       blockedUser.Reason,
+			BlockedUntil: blockedUser.BlockedUntil.Unix(),
+			BlockedAtMs:  blockedUser.BlockedAt.UnixMilli(),
+		})
+	}
+
+	return &protoBalancer.ListBlockedUsersResponse{
+		Users: users,
+		Count: int32(len(users)),
+	}, nil
+}
+
 func (s *BalancerService) StartCleanup() {
 	ticker := time.NewTicker(time.Duration(s.config.CleanupInterval) * time.Second)
 	go func() {
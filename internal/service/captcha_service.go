@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"math"
+	"reflect"
+	"time"
 
 	"captcha-service/internal/config"
 	"captcha-service/internal/domain/entity"
@@ -14,6 +18,29 @@ type ChallengeRepository interface {
 	SaveChallenge(ctx context.Context, challenge *entity.Challenge) error
 	GetChallenge(ctx context.Context, challengeID string) (*entity.Challenge, error)
 	DeleteChallenge(ctx context.Context, challengeID string) error
+	// UpdateChallenge changes a stored challenge atomically: update gets the
+	// current state and runs again if the challenge changed in the meantime.
+	// Returning remove deletes the challenge; an error leaves it as it is.
+	UpdateChallenge(ctx context.Context, challengeID string, update func(challenge *entity.Challenge) (remove bool, err error)) error
+}
+
+// errUnchanged leaves a challenge as it is in UpdateChallenge.
+var errUnchanged = errors.New("challenge unchanged")
+
+// errStateChanged rejects an event handled for a generator state that another
+// event replaced in the meantime.
+var errStateChanged = errors.New("challenge state changed while the event was handled")
+
+type EventHistory interface {
+	ProcessEvent(event *entity.BinaryEvent) (*entity.EventResult, error)
+	History(challengeID string) []entity.BinaryEvent
+	Forget(challengeID string)
+}
+
+// ClientRuntime adds the client side shared by all challenge pages, the
+// postMessage bridge and the event frame codec, to the HTML of a challenge.
+type ClientRuntime interface {
+	InjectRuntime(html string) string
 }
 
 type WebSocketSender interface {
@@ -26,13 +53,25 @@ type CaptchaService struct {
 	config        *config.CaptchaConfig
 	userAttempts  *entity.UserAttempts
 	globalBlocker *GlobalUserBlocker
+	events        EventHistory
+	runtime       ClientRuntime
+	tokens        *TokenIssuer
+}
+
+// Verdict is the outcome of a validation. A valid answer carries a
+// verification token when tokens are enabled.
+type Verdict struct {
+	Valid      bool
+	Confidence int32
+	Token      string
 }
 
-func NewCaptchaService(repo ChallengeRepository, registry *GeneratorRegistry, cfg *config.CaptchaConfig) *CaptchaService {
+func NewCaptchaService(repo ChallengeRepository, registry *GeneratorRegistry, cfg *config.CaptchaConfig, events EventHistory) *CaptchaService {
 	return &CaptchaService{
 		repo:     repo,
 		registry: registry,
 		config:   cfg,
+		events:   events,
 		userAttempts: entity.NewUserAttempts(&config.DemoConfig{
 			MaxAttempts:   cfg.MaxAttempts,
 			BlockDuration: cfg.BlockDurationMin,
@@ -46,15 +85,29 @@ func NewCaptchaService(repo ChallengeRepository, registry *GeneratorRegistry, cf
 	}
 }
 
+// SetClientRuntime makes every created challenge carry the client runtime.
+func (s *CaptchaService) SetClientRuntime(runtime ClientRuntime) {
+	s.runtime = runtime
+}
+
+// SetTokenIssuer enables verification tokens for valid answers.
+func (s *CaptchaService) SetTokenIssuer(tokens *TokenIssuer) {
+	s.tokens = tokens
+}
+
 func (s *CaptchaService) CreateChallenge(ctx context.Context, challengeType string, complexity int32, userID string) (*entity.Challenge, error) {
 	if s.globalBlocker.IsUserBlocked(userID) {
 		logger.Warn("User is globally blocked, cannot create challenge", zap.String("userID", userID))
 		return nil, entity.ErrUserBlocked
 	}
 
+	if challengeType == "" {
+		challengeType = s.config.ChallengeType
+	}
+
 	generator, exists := s.registry.Get(challengeType)
 	if !exists {
-		return nil, entity.ErrChallengeNotFound
+		return nil, entity.ErrUnsupportedChallengeType
 	}
 
 	challenge, err := generator.Generate(ctx, complexity, userID)
@@ -62,36 +115,76 @@ func (s *CaptchaService) CreateChallenge(ctx context.Context, challengeType stri
 		return nil, err
 	}
 
-	if err := s.repo.SaveChallenge(ctx, challenge); err != nil {
+	if challenge.HTML == "" {
+		challenge.HTML = fallbackChallengeHTML(challenge)
+	}
+	if s.runtime != nil {
+		challenge.HTML = s.runtime.InjectRuntime(challenge.HTML)
+	}
+	challenge.Site = SiteFromContext(ctx)
+
+	// Only the answer is kept in memory; the rendered HTML goes to the client.
+	stored := *challenge
+	stored.HTML = ""
+	if err := s.repo.SaveChallenge(ctx, &stored); err != nil {
 		return nil, err
 	}
 
-	challenge.HTML = "<!-- HTML will be generated by the frontend -->"
 	return challenge, nil
 }
 
 func (s *CaptchaService) ValidateChallenge(ctx context.Context, challengeID string, answer interface{}) (bool, int32, error) {
+	verdict, err := s.Validate(ctx, challengeID, answer)
+	return verdict.Valid, verdict.Confidence, err
+}
+
+// Validate judges an answer like ValidateChallenge and issues a verification
+// token for a valid one.
+func (s *CaptchaService) Validate(ctx context.Context, challengeID string, answer interface{}) (Verdict, error) {
 	challenge, err := s.repo.GetChallenge(ctx, challengeID)
 	if err != nil {
-		return false, 0, err
+		return Verdict{}, err
 	}
 
 	if s.globalBlocker.IsUserBlocked(challenge.UserID) {
 		logger.Warn("User is globally blocked, cannot validate challenge", zap.String("userID", challenge.UserID))
-		return false, 0, entity.ErrUserBlocked
+		return Verdict{}, entity.ErrUserBlocked
+	}
+
+	// An answer that cannot be judged still has to be recorded; the movement
+	// history is kept for the next one.
+	if attemptBlocked(challenge, time.Now()) {
+		if err := s.recordAttempt(ctx, challengeID, nil); err != nil {
+			return Verdict{}, err
+		}
 	}
 
 	generator, exists := s.registry.Get(challenge.Type)
 	if !exists {
-		return false, 0, entity.ErrChallengeNotFound
+		return Verdict{}, entity.ErrChallengeNotFound
 	}
 
-	valid, confidence, err := generator.Validate(answer, challenge.Data)
+	// Each attempt is judged on the movement that led to it, so the history is
+	// dropped once it has been handed to the generator.
+	attempt := *challenge
+	attempt.Events = s.events.History(challengeID)
+	s.events.Forget(challengeID)
+
+	valid, confidence, err := generator.Validate(answer, &attempt)
 	if err != nil {
-		return false, 0, err
+		return Verdict{}, err
+	}
+
+	if valid && !skipsTrajectory(generator) {
+		valid, confidence = s.applyTrajectory(&attempt, confidence)
 	}
 
-	challenge.Attempts++
+	err = s.recordAttempt(ctx, challengeID, func(challenge *entity.Challenge, now time.Time) bool {
+		return settleAttempt(challenge, valid, now)
+	})
+	if err != nil {
+		return Verdict{}, err
+	}
 
 	if !valid {
 		isBlocked, remainingAttempts := s.globalBlocker.RecordAttempt(challenge.UserID, challengeID)
@@ -109,7 +202,234 @@ func (s *CaptchaService) ValidateChallenge(ctx context.Context, challengeID stri
 		logger.Info("User attempts reset globally after successful validation", zap.String("userID", challenge.UserID))
 	}
 
-	return valid, confidence, nil
+	verdict := Verdict{Valid: valid, Confidence: confidence}
+	if valid && s.tokens != nil {
+		if verdict.Token, err = s.tokens.Issue(challenge); err != nil {
+			return Verdict{}, err
+		}
+	}
+
+	return verdict, nil
+}
+
+// recordAttempt applies record to the stored challenge if admitAttempt lets
+// the answer through, atomically with other answers to the challenge, and
+// returns the error a rejected answer gets. Without record the challenge only
+// takes note of a rejection.
+func (s *CaptchaService) recordAttempt(ctx context.Context, challengeID string, record func(challenge *entity.Challenge, now time.Time) (consumed bool)) error {
+	var rejected error
+	err := s.repo.UpdateChallenge(ctx, challengeID, func(challenge *entity.Challenge) (bool, error) {
+		now := time.Now()
+		if rejected = admitAttempt(challenge, now); rejected != nil || record == nil {
+			return false, nil
+		}
+		return record(challenge, now), nil
+	})
+	if err != nil {
+		return err
+	}
+	return rejected
+}
+
+// VerifyToken redeems a verification token for a customer backend. Rejected
+// tokens return one of the entity.ErrToken errors.
+func (s *CaptchaService) VerifyToken(ctx context.Context, token, site string) (*TokenClaims, error) {
+	if s.tokens == nil {
+		return nil, entity.ErrTokenInvalid
+	}
+	return s.tokens.Verify(ctx, token, site)
+}
+
+// skipsTrajectory reports whether the pointer trajectory says nothing more
+// about the answer: interactive challenges run on the server, event sequences
+// are the answer and typed answers have no trajectory.
+func skipsTrajectory(generator ChallengeGenerator) bool {
+	switch unwrapGenerator(generator).(type) {
+	case InteractiveGenerator, EventSequenceGenerator, TypedAnswerGenerator:
+		return true
+	default:
+		return false
+	}
+}
+
+// GameSession returns the running simulation of an interactive challenge,
+// starting it on first use.
+func (s *CaptchaService) GameSession(ctx context.Context, challengeID string) (GameSession, error) {
+	challenge, err := s.repo.GetChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.globalBlocker.IsUserBlocked(challenge.UserID) {
+		return nil, entity.ErrUserBlocked
+	}
+
+	generator, exists := s.registry.Get(challenge.Type)
+	if !exists {
+		return nil, entity.ErrChallengeNotFound
+	}
+
+	interactive, ok := unwrapGenerator(generator).(InteractiveGenerator)
+	if !ok {
+		return nil, entity.ErrNotInteractive
+	}
+
+	return interactive.StartSession(challenge)
+}
+
+// HandleChallengeEvent passes a frontend event the transport does not know to
+// the generator of the challenge and stores the challenge afterwards, since
+// handling it may change the generator state. The generator sees the event
+// once, outside the store's update: a plugin call is not repeated when
+// another write to the challenge forces the update to start over. If the
+// generator state itself changed meanwhile, the reply was made for a stale
+// state and the event fails with errStateChanged.
+func (s *CaptchaService) HandleChallengeEvent(ctx context.Context, challengeID, eventType string, data []byte) ([]byte, error) {
+	challenge, err := s.repo.GetChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.globalBlocker.IsUserBlocked(challenge.UserID) {
+		return nil, entity.ErrUserBlocked
+	}
+
+	generator, exists := s.registry.Get(challenge.Type)
+	if !exists {
+		return nil, entity.ErrChallengeNotFound
+	}
+
+	handler, ok := unwrapGenerator(generator).(EventHandlingGenerator)
+	if !ok {
+		return nil, entity.ErrUnknownEvent
+	}
+
+	handled := *challenge
+	reply, err := handler.HandleEvent(ctx, &handled, eventType, data)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(handled.Data, challenge.Data) {
+		return reply, nil
+	}
+
+	err = s.repo.UpdateChallenge(ctx, challengeID, func(stored *entity.Challenge) (bool, error) {
+		if !reflect.DeepEqual(stored.Data, challenge.Data) {
+			return false, errStateChanged
+		}
+		stored.Data = handled.Data
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// RecordInteraction stores a frame of pointer events sent while the challenge
+// is being solved. Events are recorded in order up to the first one rejected,
+// and only for challenges that exist, so made-up IDs cannot crowd out the
+// history of real ones. If the challenge changed in response, the delta to
+// push to the client is returned.
+func (s *CaptchaService) RecordInteraction(ctx context.Context, challengeID string, data []byte) ([]byte, error) {
+	events, err := entity.DecodeEventFrame(challengeID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.repo.GetChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range events {
+		if _, err := s.events.ProcessEvent(&events[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.mutateChallenge(ctx, challenge, events)
+}
+
+// mutateChallenge lets the generator of a challenge that changes mid-solve
+// react to a recorded frame and stores the challenge if it did.
+func (s *CaptchaService) mutateChallenge(ctx context.Context, challenge *entity.Challenge, events []entity.BinaryEvent) ([]byte, error) {
+	challengeID := challenge.ID
+
+	generator, exists := s.registry.Get(challenge.Type)
+	if !exists {
+		return nil, entity.ErrChallengeNotFound
+	}
+
+	mutating, ok := unwrapGenerator(generator).(MutatingGenerator)
+	if !ok {
+		return nil, nil
+	}
+
+	if s.globalBlocker.IsUserBlocked(challenge.UserID) {
+		return nil, entity.ErrUserBlocked
+	}
+
+	var delta []byte
+	err := s.repo.UpdateChallenge(ctx, challengeID, func(updated *entity.Challenge) (bool, error) {
+		var err error
+		updated.Events = s.events.History(challengeID)
+		if delta, err = mutating.React(ctx, updated, events); err != nil {
+			return false, err
+		}
+		if len(delta) == 0 {
+			return false, errUnchanged
+		}
+		updated.Events = nil
+		return false, nil
+	})
+	if errors.Is(err, errUnchanged) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return delta, nil
+}
+
+// applyTrajectory rejects answers whose movement looks scripted and blends the
+// trajectory score into the generator confidence. An answer with fewer than
+// minTrajectoryPoints recorded points is rejected, unless REQUIRE_TRAJECTORY is
+// turned off; then it keeps half of its confidence.
+func (s *CaptchaService) applyTrajectory(challenge *entity.Challenge, confidence int32) (bool, int32) {
+	score := ScoreTrajectory(challenge)
+	if score.Points < minTrajectoryPoints {
+		if s.config.RequireTrajectory {
+			logger.Warn("Answer without trajectory rejected",
+				zap.String("challengeID", challenge.ID),
+				zap.Int("points", score.Points))
+			return false, 0
+		}
+		return true, confidence / 2
+	}
+
+	logger.Debug("Trajectory scored",
+		zap.String("challengeID", challenge.ID),
+		zap.Int("points", score.Points),
+		zap.Int64("durationMs", score.DurationMs),
+		zap.Float64("velocity", score.Velocity),
+		zap.Float64("jitter", score.Jitter),
+		zap.Float64("acceleration", score.Acceleration),
+		zap.Float64("overshoot", score.Overshoot),
+		zap.Float64("total", score.Total))
+
+	if score.Total*100 < float64(s.config.MinTrajectoryScore) {
+		logger.Warn("Answer rejected by trajectory analysis",
+			zap.String("challengeID", challenge.ID),
+			zap.Float64("score", score.Total))
+		return false, 0
+	}
+
+	return true, int32(math.Round(float64(confidence)/2 + score.Total*50))
+}
+
+func (s *CaptchaService) ListChallengeTypes() []string {
+	return s.registry.Names()
 }
 
 func (s *CaptchaService) GetChallenge(ctx context.Context, challengeID string) (*entity.Challenge, error) {
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedEvents struct {
+	noEvents
+	events []entity.BinaryEvent
+}
+
+func (r *recordedEvents) ProcessEvent(event *entity.BinaryEvent) (*entity.EventResult, error) {
+	r.events = append(r.events, *event)
+	return entity.NewEventResult(true, "", nil), nil
+}
+
+func TestRecordInteractionOnlyForExistingChallenges(t *testing.T) {
+	svc, _ := newAttemptsService(t, &entity.Challenge{MaxAttempts: 3})
+	events := &recordedEvents{}
+	svc.events = events
+	ctx := context.Background()
+
+	frame, err := entity.EncodeEventFrame([]entity.BinaryEvent{
+		{Type: entity.EventTypeSliderMoved, X: 10, Y: 20, Timestamp: 1000},
+		{Type: entity.EventTypeSliderMoved, X: 15, Y: 20, Timestamp: 1020},
+	})
+	require.NoError(t, err)
+
+	_, err = svc.RecordInteraction(ctx, "made-up", frame)
+	assert.Error(t, err)
+	assert.Empty(t, events.events, "no history is kept for unknown challenges")
+
+	_, err = svc.RecordInteraction(ctx, "challenge-1", frame)
+	require.NoError(t, err)
+	assert.Len(t, events.events, 2)
+}
+
+type sourcedGenerator struct {
+	passwordGenerator
+	challengeSource
+}
+
+func TestRegistryPrefixesChallengeIDs(t *testing.T) {
+	registry := NewGeneratorRegistry()
+	early := &sourcedGenerator{}
+	registry.Register("early", early)
+	registry.SetIDPrefix("captcha-instance-a")
+	late := &sourcedGenerator{}
+	registry.Register("late", late)
+
+	for _, generator := range []*sourcedGenerator{early, late} {
+		id, err := generator.newID()
+		require.NoError(t, err)
+		instanceID, ok := entity.IssuingInstance(id)
+		assert.True(t, ok)
+		assert.Equal(t, "captcha-instance-a", instanceID)
+	}
+
+	id, err := (&sourcedGenerator{}).newID()
+	require.NoError(t, err)
+	_, ok := entity.IssuingInstance(id)
+	assert.False(t, ok, "no prefix unless the registry sets one")
+}
+
+// counterPlugin stands in for a plugin: every event appends to its state.
+type counterPlugin struct {
+	passwordGenerator
+	calls   int
+	onEvent func()
+}
+
+func (p *counterPlugin) HandleEvent(ctx context.Context, challenge *entity.Challenge, eventType string, data []byte) ([]byte, error) {
+	p.calls++
+	if p.onEvent != nil {
+		p.onEvent()
+	}
+	state := challenge.Data.(entity.PluginData)
+	state.State = append(append([]byte{}, state.State...), data...)
+	challenge.Data = state
+	return []byte("ok"), nil
+}
+
+// retryingChallenges runs every update twice, as a store does when another
+// write forces it to start over.
+type retryingChallenges struct {
+	*memoryChallenges
+}
+
+func (r retryingChallenges) UpdateChallenge(ctx context.Context, challengeID string, update func(challenge *entity.Challenge) (bool, error)) error {
+	if err := r.memoryChallenges.UpdateChallenge(ctx, challengeID, func(challenge *entity.Challenge) (bool, error) {
+		_, err := update(challenge)
+		return false, errors.Join(err, errUnchanged)
+	}); !errors.Is(err, errUnchanged) {
+		return err
+	}
+	return r.memoryChallenges.UpdateChallenge(ctx, challengeID, update)
+}
+
+func newPluginEventService(t *testing.T) (*CaptchaService, *memoryChallenges, *counterPlugin) {
+	t.Helper()
+	svc, repo := newAttemptsService(t, &entity.Challenge{MaxAttempts: 3})
+	repo.challenges["challenge-1"].Data = entity.PluginData{Type: "password", Plugin: "counter", State: []byte("a")}
+
+	plugin := &counterPlugin{}
+	svc.registry.Register("password", plugin)
+	return svc, repo, plugin
+}
+
+func TestHandleChallengeEventCallsPluginOnce(t *testing.T) {
+	svc, repo, plugin := newPluginEventService(t)
+	svc.repo = retryingChallenges{repo}
+
+	reply, err := svc.HandleChallengeEvent(context.Background(), "challenge-1", "tick", []byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ok"), reply)
+	assert.Equal(t, 1, plugin.calls, "a retried update does not repeat the plugin call")
+	assert.Equal(t, []byte("ab"), repo.challenges["challenge-1"].Data.(entity.PluginData).State)
+}
+
+func TestHandleChallengeEventRejectsStaleState(t *testing.T) {
+	svc, repo, plugin := newPluginEventService(t)
+	plugin.onEvent = func() {
+		// Another event is handled while the plugin works on this one.
+		repo.challenges["challenge-1"].Data = entity.PluginData{Type: "password", Plugin: "counter", State: []byte("ac")}
+	}
+
+	_, err := svc.HandleChallengeEvent(context.Background(), "challenge-1", "tick", []byte("b"))
+	assert.ErrorIs(t, err, errStateChanged)
+	assert.Equal(t, []byte("ac"), repo.challenges["challenge-1"].Data.(entity.PluginData).State)
+}
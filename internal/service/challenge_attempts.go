@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+)
+
+// A challenge can be answered correctly once: a valid answer consumes it. A
+// failed answer counts against MaxAttempts and starts a cooldown of MinTime,
+// the least time a person needs for another try, so answers cannot be
+// guessed at machine speed. Answers sent during the cooldown are rejected
+// without being judged and count as timeout attempts. A challenge out of
+// either kind of attempt stays locked until it expires.
+
+const (
+	blockReasonAttempts        = "too many attempts"
+	blockReasonTimeoutAttempts = "too many attempts during cooldown"
+)
+
+// admitAttempt decides whether an answer to challenge may be judged at now.
+// A rejected answer may still change the challenge, which then has to be
+// stored.
+func admitAttempt(challenge *entity.Challenge, now time.Time) error {
+	switch {
+	case challenge.IsBlocked:
+		if challenge.BlockReason == blockReasonAttempts {
+			return entity.ErrMaxAttemptsReached
+		}
+		return entity.ErrChallengeBlocked
+	case !now.Before(challenge.ExpiresAt):
+		return entity.ErrChallengeExpired
+	case challenge.BlockedUntil != nil && now.Before(*challenge.BlockedUntil):
+		challenge.TimeoutAttempts++
+		if limitReached(challenge.TimeoutAttempts, challenge.MaxTimeoutAttempts) {
+			lockChallenge(challenge, blockReasonTimeoutAttempts)
+		}
+		return entity.ErrChallengeBlocked
+	default:
+		return nil
+	}
+}
+
+// settleAttempt records a judged answer and reports whether it consumed the
+// challenge.
+func settleAttempt(challenge *entity.Challenge, valid bool, now time.Time) bool {
+	challenge.Attempts++
+	if valid {
+		return true
+	}
+
+	if limitReached(challenge.Attempts, challenge.MaxAttempts) {
+		lockChallenge(challenge, blockReasonAttempts)
+	} else if challenge.MinTime > 0 {
+		until := now.Add(time.Duration(challenge.MinTime) * time.Millisecond)
+		challenge.BlockedUntil = &until
+	}
+	return false
+}
+
+// attemptBlocked reports whether admitAttempt would reject an answer at now,
+// without changing the challenge.
+func attemptBlocked(challenge *entity.Challenge, now time.Time) bool {
+	probe := *challenge
+	return admitAttempt(&probe, now) != nil
+}
+
+// IsAttemptRejected reports whether an answer was turned away because the
+// challenge is cooling down or locked.
+func IsAttemptRejected(err error) bool {
+	return errors.Is(err, entity.ErrChallengeBlocked) || errors.Is(err, entity.ErrMaxAttemptsReached)
+}
+
+// IsChallengeGone reports whether an answer came for a challenge that no
+// longer exists, because it expired or a valid answer consumed it.
+func IsChallengeGone(err error) bool {
+	return errors.Is(err, entity.ErrChallengeNotFound) || errors.Is(err, entity.ErrChallengeExpired)
+}
+
+// limitReached treats a limit of zero as no limit.
+func limitReached(count, limit int32) bool {
+	return limit > 0 && count >= limit
+}
+
+func lockChallenge(challenge *entity.Challenge, reason string) {
+	challenge.IsBlocked = true
+	challenge.BlockReason = reason
+	until := challenge.ExpiresAt
+	challenge.BlockedUntil = &until
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryChallenges struct {
+	mu         sync.Mutex
+	challenges map[string]*entity.Challenge
+}
+
+func (m *memoryChallenges) SaveChallenge(ctx context.Context, challenge *entity.Challenge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.challenges[challenge.ID] = challenge
+	return nil
+}
+
+func (m *memoryChallenges) GetChallenge(ctx context.Context, challengeID string) (*entity.Challenge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	challenge, ok := m.challenges[challengeID]
+	if !ok {
+		return nil, fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeNotFound)
+	}
+	copied := *challenge
+	return &copied, nil
+}
+
+func (m *memoryChallenges) DeleteChallenge(ctx context.Context, challengeID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.challenges, challengeID)
+	return nil
+}
+
+func (m *memoryChallenges) UpdateChallenge(ctx context.Context, challengeID string, update func(challenge *entity.Challenge) (bool, error)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.challenges[challengeID]
+	if !ok {
+		return fmt.Errorf("challenge with ID %s: %w", challengeID, entity.ErrChallengeNotFound)
+	}
+	challenge := *stored
+	remove, err := update(&challenge)
+	if err != nil {
+		return err
+	}
+	if remove {
+		delete(m.challenges, challengeID)
+	} else {
+		m.challenges[challengeID] = &challenge
+	}
+	return nil
+}
+
+type noEvents struct{}
+
+func (noEvents) ProcessEvent(event *entity.BinaryEvent) (*entity.EventResult, error) { return nil, nil }
+func (noEvents) History(challengeID string) []entity.BinaryEvent                     { return nil }
+func (noEvents) Forget(challengeID string)                                           {}
+
+// passwordGenerator accepts "right" and nothing else.
+type passwordGenerator struct{}
+
+func (passwordGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	return nil, entity.ErrUnsupportedChallengeType
+}
+
+func (passwordGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	if answer == "right" {
+		return true, 100, nil
+	}
+	return false, 0, nil
+}
+
+func newAttemptsService(t *testing.T, challenge *entity.Challenge) (*CaptchaService, *memoryChallenges) {
+	t.Helper()
+
+	registry := NewGeneratorRegistry()
+	registry.Register("password", passwordGenerator{})
+
+	challenge.ID = "challenge-1"
+	challenge.UserID = "user-1"
+	challenge.Type = "password"
+	challenge.ExpiresAt = time.Now().Add(time.Minute)
+
+	repo := &memoryChallenges{challenges: map[string]*entity.Challenge{challenge.ID: challenge}}
+	cfg := &config.CaptchaConfig{MaxAttempts: 100, BlockDurationMin: 5}
+	return NewCaptchaService(repo, registry, cfg, noEvents{}), repo
+}
+
+func TestValidateConsumesChallenge(t *testing.T) {
+	svc, repo := newAttemptsService(t, &entity.Challenge{MaxAttempts: 3})
+	ctx := context.Background()
+
+	verdict, err := svc.Validate(ctx, "challenge-1", "right")
+	require.NoError(t, err)
+	assert.True(t, verdict.Valid)
+	assert.Empty(t, repo.challenges)
+
+	_, err = svc.Validate(ctx, "challenge-1", "right")
+	assert.ErrorIs(t, err, entity.ErrChallengeNotFound, "a solved challenge cannot be replayed")
+	assert.True(t, IsChallengeGone(err))
+}
+
+func TestValidateLocksAfterMaxAttempts(t *testing.T) {
+	svc, repo := newAttemptsService(t, &entity.Challenge{MaxAttempts: 3})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		verdict, err := svc.Validate(ctx, "challenge-1", "wrong")
+		require.NoError(t, err)
+		assert.False(t, verdict.Valid)
+	}
+
+	_, err := svc.Validate(ctx, "challenge-1", "right")
+	assert.ErrorIs(t, err, entity.ErrMaxAttemptsReached)
+
+	stored := repo.challenges["challenge-1"]
+	assert.Equal(t, int32(3), stored.Attempts)
+	assert.True(t, stored.IsBlocked)
+}
+
+func TestValidateCooldownAfterFailure(t *testing.T) {
+	svc, repo := newAttemptsService(t, &entity.Challenge{MaxAttempts: 5, MinTime: 60000, MaxTimeoutAttempts: 2})
+	ctx := context.Background()
+
+	verdict, err := svc.Validate(ctx, "challenge-1", "wrong")
+	require.NoError(t, err)
+	assert.False(t, verdict.Valid)
+
+	_, err = svc.Validate(ctx, "challenge-1", "right")
+	assert.ErrorIs(t, err, entity.ErrChallengeBlocked, "answers during the cooldown are not judged")
+	assert.Equal(t, int32(1), repo.challenges["challenge-1"].TimeoutAttempts)
+
+	past := time.Now().Add(-time.Second)
+	repo.challenges["challenge-1"].BlockedUntil = &past
+	verdict, err = svc.Validate(ctx, "challenge-1", "wrong")
+	require.NoError(t, err)
+	assert.False(t, verdict.Valid, "answers after the cooldown are judged")
+
+	_, err = svc.Validate(ctx, "challenge-1", "right")
+	assert.ErrorIs(t, err, entity.ErrChallengeBlocked)
+
+	stored := repo.challenges["challenge-1"]
+	assert.True(t, stored.IsBlocked, "the second timeout attempt locks the challenge")
+	assert.Equal(t, int32(2), stored.Attempts)
+
+	repo.challenges["challenge-1"].BlockedUntil = &past
+	_, err = svc.Validate(ctx, "challenge-1", "right")
+	assert.ErrorIs(t, err, entity.ErrChallengeBlocked, "a locked challenge stays locked")
+}
+
+func TestValidateConcurrentAnswersSolveOnce(t *testing.T) {
+	svc, _ := newAttemptsService(t, &entity.Challenge{MaxAttempts: 3})
+
+	var solved int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			verdict, err := svc.Validate(context.Background(), "challenge-1", "right")
+			if err == nil && verdict.Valid {
+				atomic.AddInt32(&solved, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), solved)
+}
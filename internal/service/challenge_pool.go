@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// pooledUserID stands in for the user while a pooled challenge is rendered;
+// it is replaced in the HTML when the challenge is handed out.
+const pooledUserID = "pooled_user_placeholder"
+
+// Only user IDs that every template context leaves unescaped can be swapped
+// into pre-rendered HTML; other requests are generated directly.
+var poolableUserID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+type poolKey struct {
+	challengeType string
+	complexity    int32
+	locale        string
+}
+
+type poolBucket struct {
+	key     poolKey
+	ready   chan *entity.Challenge
+	pending int32
+
+	hits           int64
+	misses         int64
+	refills        int64
+	refillNanos    int64
+	lastRefillNano int64
+}
+
+// PoolStats describes one bucket of the pool for /stats.
+type PoolStats struct {
+	ChallengeType string  `json:"challenge_type"`
+	Complexity    int32   `json:"complexity"`
+	Locale        string  `json:"locale"`
+	Depth         int     `json:"depth"`
+	Capacity      int     `json:"capacity"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	Refills       int64   `json:"refills"`
+	AvgRefillMs   float64 `json:"avg_refill_ms"`
+	LastRefillMs  float64 `json:"last_refill_ms"`
+}
+
+// ChallengePool keeps ready challenges per type, complexity bucket and locale
+// so that image rendering happens ahead of the requests. Buckets are created
+// on first use and topped up by a fixed set of workers; each challenge sits in
+// a channel and is therefore handed out at most once.
+type ChallengePool struct {
+	capacity    int
+	bucketWidth int32
+	defaultComp int32
+
+	generators map[string]ChallengeGenerator
+	buckets    map[poolKey]*poolBucket
+	mu         sync.Mutex
+
+	jobs   chan poolKey
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewChallengePool(cfg *config.CaptchaConfig) *ChallengePool {
+	workers := int(cfg.PoolWorkers)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	bucketWidth := cfg.PoolBucketWidth
+	if bucketWidth <= 0 {
+		bucketWidth = 1
+	}
+	if bucketWidth > 100 {
+		bucketWidth = 100
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &ChallengePool{
+		capacity:    int(cfg.PoolSize),
+		bucketWidth: bucketWidth,
+		defaultComp: cfg.ComplexityMedium,
+		generators:  make(map[string]ChallengeGenerator),
+		buckets:     make(map[poolKey]*poolBucket),
+		jobs:        make(chan poolKey, workers*int(cfg.PoolSize)),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+
+	return p
+}
+
+// Attach puts the pool in front of every generator in the registry and starts
+// filling the default bucket of each, if the default complexity is pooled.
+func (p *ChallengePool) Attach(registry *GeneratorRegistry) {
+	for _, name := range registry.Names() {
+		generator, _ := registry.Get(name)
+
+		p.mu.Lock()
+		p.generators[name] = generator
+		p.mu.Unlock()
+
+		registry.Register(name, &pooledGenerator{pool: p, name: name, generator: generator})
+		if complexity, ok := p.bucketFor(p.defaultComp); ok {
+			p.refill(p.bucket(poolKey{challengeType: name, complexity: complexity, locale: DefaultLocale}))
+		}
+	}
+}
+
+func (p *ChallengePool) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *ChallengePool) Stats() []PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make([]PoolStats, 0, len(p.buckets))
+	for key, bucket := range p.buckets {
+		refills := atomic.LoadInt64(&bucket.refills)
+		var avg float64
+		if refills > 0 {
+			avg = float64(atomic.LoadInt64(&bucket.refillNanos)) / float64(refills) / float64(time.Millisecond)
+		}
+
+		stats = append(stats, PoolStats{
+			ChallengeType: key.challengeType,
+			Complexity:    key.complexity,
+			Locale:        key.locale,
+			Depth:         len(bucket.ready),
+			Capacity:      p.capacity,
+			Hits:          atomic.LoadInt64(&bucket.hits),
+			Misses:        atomic.LoadInt64(&bucket.misses),
+			Refills:       refills,
+			AvgRefillMs:   avg,
+			LastRefillMs:  float64(atomic.LoadInt64(&bucket.lastRefillNano)) / float64(time.Millisecond),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.ChallengeType != b.ChallengeType {
+			return a.ChallengeType < b.ChallengeType
+		}
+		if a.Complexity != b.Complexity {
+			return a.Complexity < b.Complexity
+		}
+		return a.Locale < b.Locale
+	})
+	return stats
+}
+
+// bucketFor applies the same normalisation as the generators and reports
+// whether the complexity is pooled: only multiples of the bucket width are, so
+// a pooled challenge always has exactly the complexity that was requested.
+func (p *ChallengePool) bucketFor(complexity int32) (int32, bool) {
+	if complexity < 0 || complexity > 100 {
+		complexity = p.defaultComp
+	}
+	return complexity, complexity%p.bucketWidth == 0
+}
+
+func (p *ChallengePool) bucket(key poolKey) *poolBucket {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	bucket, ok := p.buckets[key]
+	if !ok {
+		bucket = &poolBucket{key: key, ready: make(chan *entity.Challenge, p.capacity)}
+		p.buckets[key] = bucket
+	}
+	return bucket
+}
+
+// take hands out a pooled challenge bound to the user, or nil when the bucket
+// is empty or the complexity is not pooled, and schedules a top-up of the
+// bucket.
+func (p *ChallengePool) take(ctx context.Context, challengeType string, complexity int32, userID string, now time.Time) *entity.Challenge {
+	complexity, ok := p.bucketFor(complexity)
+	if !ok {
+		return nil
+	}
+	key := poolKey{
+		challengeType: challengeType,
+		complexity:    complexity,
+		locale:        LocaleFromContext(ctx),
+	}
+	bucket := p.bucket(key)
+	defer p.refill(bucket)
+
+	select {
+	case challenge := <-bucket.ready:
+		atomic.AddInt64(&bucket.hits, 1)
+		return bindChallenge(challenge, userID, now)
+	default:
+		atomic.AddInt64(&bucket.misses, 1)
+		return nil
+	}
+}
+
+// refill queues one job per free slot that no earlier job is already filling.
+func (p *ChallengePool) refill(bucket *poolBucket) {
+	for {
+		pending := atomic.LoadInt32(&bucket.pending)
+		if len(bucket.ready)+int(pending) >= p.capacity {
+			return
+		}
+		if !atomic.CompareAndSwapInt32(&bucket.pending, pending, pending+1) {
+			continue
+		}
+
+		select {
+		case p.jobs <- bucket.key:
+		default:
+			atomic.AddInt32(&bucket.pending, -1)
+			return
+		}
+	}
+}
+
+func (p *ChallengePool) worker() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case key := <-p.jobs:
+			p.generate(key)
+		}
+	}
+}
+
+func (p *ChallengePool) generate(key poolKey) {
+	bucket := p.bucket(key)
+	defer atomic.AddInt32(&bucket.pending, -1)
+
+	p.mu.Lock()
+	generator, ok := p.generators[key.challengeType]
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	started := time.Now()
+	challenge, err := generator.Generate(WithLocale(p.ctx, key.locale), key.complexity, pooledUserID)
+	if err != nil {
+		logger.Warn("Failed to pre-generate challenge",
+			zap.String("challengeType", key.challengeType),
+			zap.Int32("complexity", key.complexity),
+			zap.Error(err))
+		return
+	}
+	elapsed := time.Since(started)
+
+	atomic.AddInt64(&bucket.refills, 1)
+	atomic.AddInt64(&bucket.refillNanos, int64(elapsed))
+	atomic.StoreInt64(&bucket.lastRefillNano, int64(elapsed))
+
+	select {
+	case bucket.ready <- challenge:
+	default:
+	}
+}
+
+// bindChallenge hands a pooled challenge to its user and restarts its clock,
+// so timing and expiry count from the request rather than from rendering.
+func bindChallenge(challenge *entity.Challenge, userID string, now time.Time) *entity.Challenge {
+	lifetime := challenge.ExpiresAt.Sub(challenge.CreatedAt)
+
+	challenge.UserID = userID
+	challenge.HTML = strings.ReplaceAll(challenge.HTML, pooledUserID, userID)
+	challenge.CreatedAt = now
+	challenge.ExpiresAt = now.Add(lifetime)
+	return challenge
+}
+
+// pooledGenerator serves Generate from the pool when it can and falls back to
+// the wrapped generator otherwise, always for replays of a given seed.
+type pooledGenerator struct {
+	pool      *ChallengePool
+	name      string
+	generator ChallengeGenerator
+}
+
+func (g *pooledGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	if _, replay := SeedFromContext(ctx); !replay && poolableUserID.MatchString(userID) {
+		if challenge := g.pool.take(ctx, g.name, complexity, userID, g.now()); challenge != nil {
+			return challenge, nil
+		}
+	}
+	return g.generator.Generate(ctx, complexity, userID)
+}
+
+func (g *pooledGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	return g.generator.Validate(answer, challenge)
+}
+
+// now reads the clock of the wrapped generator, the one the challenge was
+// generated with.
+func (g *pooledGenerator) now() time.Time {
+	if clocked, ok := unwrapGenerator(g.generator).(interface{ now() time.Time }); ok {
+		return clocked.now()
+	}
+	return time.Now()
+}
+
+func (g *pooledGenerator) Unwrap() ChallengeGenerator {
+	return g.generator
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pageGenerator renders the user ID into the page, as the templates do.
+type pageGenerator struct {
+	passwordGenerator
+	challengeSource
+}
+
+func (g *pageGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	id, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
+	now := g.now()
+	return &entity.Challenge{
+		ID:         id,
+		UserID:     userID,
+		Type:       "page",
+		Complexity: complexity,
+		HTML:       `<div data-user="` + userID + `"></div>`,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(5 * time.Minute),
+	}, nil
+}
+
+func newTestPool(t *testing.T, size int32) (*GeneratorRegistry, *pageGenerator, *ChallengePool) {
+	t.Helper()
+
+	generator := &pageGenerator{}
+	generator.SetClock(goldenTime)
+	registry := NewGeneratorRegistry()
+	registry.Register("page", generator)
+
+	pool := NewChallengePool(&config.CaptchaConfig{PoolSize: size, PoolWorkers: 2, ComplexityMedium: 50})
+	t.Cleanup(pool.Stop)
+	pool.Attach(registry)
+
+	require.Eventually(t, func() bool {
+		stats := pool.Stats()
+		return len(stats) == 1 && stats[0].Depth == int(size)
+	}, time.Second, 5*time.Millisecond)
+	return registry, generator, pool
+}
+
+func TestPooledChallengeBindsToRequester(t *testing.T) {
+	registry, generator, _ := newTestPool(t, 2)
+	requested := time.Time(goldenTime).Add(time.Hour)
+	generator.SetClock(fixedClock(requested))
+
+	pooled, _ := registry.Get("page")
+	challenge, err := pooled.Generate(context.Background(), 50, "user-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "user-1", challenge.UserID)
+	assert.Equal(t, `<div data-user="user-1"></div>`, challenge.HTML)
+	assert.Equal(t, requested, challenge.CreatedAt, "the clock restarts when the challenge is handed out")
+	assert.Equal(t, requested.Add(5*time.Minute), challenge.ExpiresAt)
+}
+
+func TestPooledChallengeHandedOutOnce(t *testing.T) {
+	registry, _, pool := newTestPool(t, 8)
+	pooled, _ := registry.Get("page")
+
+	const requests = 32
+	challenges := make([]*entity.Challenge, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			challenge, err := pooled.Generate(context.Background(), 50, fmt.Sprintf("user-%d", i))
+			if err == nil {
+				challenges[i] = challenge
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, requests)
+	for i, challenge := range challenges {
+		require.NotNil(t, challenge)
+		assert.False(t, seen[challenge.ID], "challenge %s handed out twice", challenge.ID)
+		seen[challenge.ID] = true
+
+		userID := fmt.Sprintf("user-%d", i)
+		assert.Equal(t, userID, challenge.UserID)
+		assert.Contains(t, challenge.HTML, userID)
+		assert.False(t, strings.Contains(challenge.HTML, pooledUserID))
+	}
+
+	stats := pool.Stats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(requests), stats[0].Hits+stats[0].Misses)
+	assert.Positive(t, stats[0].Hits)
+}
+
+func TestPooledChallengeKeepsRequestedComplexity(t *testing.T) {
+	registry, _, pool := newTestPool(t, 2)
+	pooled, _ := registry.Get("page")
+
+	challenge, err := pooled.Generate(context.Background(), 50, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(50), challenge.Complexity)
+
+	// The first request at a new complexity misses and creates its bucket;
+	// once filled, the pooled challenges carry that same complexity.
+	challenge, err = pooled.Generate(context.Background(), 37, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(37), challenge.Complexity)
+
+	require.Eventually(t, func() bool {
+		for _, stats := range pool.Stats() {
+			if stats.Complexity == 37 && stats.Depth == 2 {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 5*time.Millisecond)
+
+	challenge, err = pooled.Generate(context.Background(), 37, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(37), challenge.Complexity)
+	for _, stats := range pool.Stats() {
+		if stats.Complexity == 37 {
+			assert.Equal(t, int64(1), stats.Hits)
+		}
+	}
+}
+
+func TestPoolBucketWidth(t *testing.T) {
+	tests := []struct {
+		width      int32
+		complexity int32
+		bucket     int32
+		pooled     bool
+	}{
+		{width: 25, complexity: 50, bucket: 50, pooled: true},
+		{width: 25, complexity: 49, bucket: 49, pooled: false},
+		{width: 25, complexity: 51, bucket: 51, pooled: false},
+		{width: 25, complexity: 100, bucket: 100, pooled: true},
+		{width: 25, complexity: -1, bucket: 50, pooled: true},
+		{width: 0, complexity: 37, bucket: 37, pooled: true},
+		{width: 500, complexity: 50, bucket: 50, pooled: false},
+		{width: 500, complexity: 100, bucket: 100, pooled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("width %d complexity %d", tt.width, tt.complexity), func(t *testing.T) {
+			pool := NewChallengePool(&config.CaptchaConfig{PoolWorkers: 1, PoolBucketWidth: tt.width, ComplexityMedium: 50})
+			defer pool.Stop()
+
+			bucket, pooled := pool.bucketFor(tt.complexity)
+			assert.Equal(t, tt.bucket, bucket)
+			assert.Equal(t, tt.pooled, pooled)
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"math/rand"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/imaging"
+)
+
+const (
+	// A click within this share of the icon size from its centre is a hit,
+	// a little more than the icon itself to forgive touch screens.
+	clickHitRadiusRatio = 0.65
+	clickIconSpacing    = 1.5
+
+	minClickIntervalMs = 150
+	maxClickIntervalMs = 10000
+
+	// Coefficient of variation of the pauses between clicks below which the
+	// rhythm looks scripted.
+	humanClickVariation = 0.15
+)
+
+type ClickOrderRenderer interface {
+	RenderClickSequence(rng *rand.Rand, icons []imaging.Icon, targets []imaging.Icon, distortion imaging.Distortion) (*imaging.ClickSequenceImages, error)
+	RedrawClickCanvas(rng *rand.Rand, backgroundSeed int64, icons []imaging.Icon, distortion imaging.Distortion) (string, error)
+}
+
+// ClickOrderGenerator draws icons on a background and asks for the targets to
+// be clicked in the order shown on a separate prompt. The answer is the
+// sequence of packed click events recorded while solving. At higher
+// complexity icons change place while the user is clicking.
+type ClickOrderGenerator struct {
+	config         *config.CaptchaConfig
+	difficulty     *DifficultyProfiles
+	repo           ChallengeRepository
+	templateEngine TemplateEngine
+	renderer       ClickOrderRenderer
+	distortion     imaging.DistortionProfile
+
+	challengeSource
+}
+
+func NewClickOrderGenerator(config *config.CaptchaConfig, difficulty *DifficultyProfiles, repo ChallengeRepository, templateEngine TemplateEngine, renderer ClickOrderRenderer) *ClickOrderGenerator {
+	return &ClickOrderGenerator{
+		config:         config,
+		difficulty:     difficulty,
+		repo:           repo,
+		templateEngine: templateEngine,
+		renderer:       renderer,
+		distortion:     distortionProfile(entity.ChallengeTypeClickOrder, config.DistortionClickOrder),
+	}
+}
+
+func (g *ClickOrderGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	if complexity < 0 || complexity > 100 {
+		complexity = g.config.ComplexityMedium
+	}
+
+	params := g.difficulty.At(entity.ChallengeTypeClickOrder, complexity)
+	seed, err := g.newSeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	targetCount := max(1, params.Int("targets"))
+	decoyCount := params.Int("decoys")
+	size := params.Int("icon_size")
+
+	icons, err := imaging.RandomIcons(rng, targetCount+decoyCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := placeIcons(rng, icons, size, entity.CanvasWidth, entity.CanvasHeight); err != nil {
+		return nil, err
+	}
+
+	// The first targetCount icons are the targets; shuffle the draw order so
+	// it says nothing about which ones they are.
+	targets := append([]imaging.Icon(nil), icons[:targetCount]...)
+	rng.Shuffle(len(icons), func(i, j int) { icons[i], icons[j] = icons[j], icons[i] })
+
+	images, err := g.renderer.RenderClickSequence(rng, icons, targets, g.distortion.At(complexity))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render click order: %w", err)
+	}
+
+	challengeID, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := g.templateEngine.Render("click_order", map[string]interface{}{
+		"Lang":         LocaleFromContext(ctx),
+		"Text":         localizedTexts(ctx),
+		"ChallengeID":  challengeID,
+		"UserID":       userID,
+		"CanvasWidth":  entity.CanvasWidth,
+		"CanvasHeight": entity.CanvasHeight,
+		"ClickCount":   targetCount,
+		"CanvasImage":  template.URL(images.Canvas),
+		"PromptImage":  template.URL(images.Prompt),
+		"PromptWidth":  images.PromptWidth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render click order template: %w", err)
+	}
+
+	clickIcons := make([]entity.ClickIcon, 0, len(icons))
+	for _, icon := range icons {
+		clickIcons = append(clickIcons, entity.ClickIcon{
+			Shape: int(icon.Shape),
+			Color: icon.Color,
+			X:     icon.X,
+			Y:     icon.Y,
+			Size:  icon.Size,
+			Angle: icon.Angle,
+		})
+	}
+
+	clickTargets := make([]entity.ClickTarget, 0, len(targets))
+	for _, target := range targets {
+		clickTargets = append(clickTargets, entity.ClickTarget{
+			X:      target.X,
+			Y:      target.Y,
+			Radius: int(math.Ceil(float64(size) * clickHitRadiusRatio)),
+			Icon:   iconIndex(icons, target),
+		})
+	}
+
+	now := g.now()
+	challenge := &entity.Challenge{
+		ID:         challengeID,
+		Type:       entity.ChallengeTypeClickOrder,
+		UserID:     userID,
+		Complexity: complexity,
+		Data: entity.ClickOrderData{
+			Targets:        clickTargets,
+			Icons:          clickIcons,
+			Moves:          params.Int("moves"),
+			CanvasWidth:    entity.CanvasWidth,
+			CanvasHeight:   entity.CanvasHeight,
+			BackgroundSeed: images.BackgroundSeed,
+		},
+		HTML:               html,
+		ExpiresAt:          now.Add(params.Expiration()),
+		CreatedAt:          now,
+		Attempts:           0,
+		MaxAttempts:        g.config.MaxAttempts,
+		MinTime:            params.MinTimeMs(),
+		MaxTime:            params.MaxTimeMs(),
+		IsBlocked:          false,
+		BlockReason:        "",
+		TimeoutAttempts:    0,
+		MaxTimeoutAttempts: g.config.MaxTimeoutAttempts,
+		Seed:               g.recordedSeed(seed),
+	}
+
+	return challenge, nil
+}
+
+// iconIndex finds the icon drawn for target; shape and color tell icons apart.
+func iconIndex(icons []imaging.Icon, target imaging.Icon) int {
+	for i, icon := range icons {
+		if icon.Shape == target.Shape && icon.Color == target.Color {
+			return i
+		}
+	}
+	return -1
+}
+
+// placeIcons scatters the icons so that none of them overlap.
+func placeIcons(rng *rand.Rand, icons []imaging.Icon, size, width, height int) error {
+	for i := range icons {
+		x, y, ok := freeSpot(rng, icons[:i], size, width, height)
+		if !ok {
+			return fmt.Errorf("could not place %d icons of size %d", len(icons), size)
+		}
+
+		icons[i].X, icons[i].Y = x, y
+		icons[i].Size = size
+		icons[i].Angle = float64(rng.Intn(360))
+	}
+	return nil
+}
+
+// freeSpot looks for the centre of an icon of size that keeps its distance
+// from all others.
+func freeSpot(rng *rand.Rand, others []imaging.Icon, size, width, height int) (int, int, bool) {
+	margin := size/2 + 4
+	spacing := float64(size) * clickIconSpacing
+
+	for try := 0; try < 200; try++ {
+		x := margin + rng.Intn(width-2*margin+1)
+		y := margin + rng.Intn(height-2*margin+1)
+
+		free := true
+		for _, other := range others {
+			if math.Hypot(float64(x-other.X), float64(y-other.Y)) < spacing {
+				free = false
+				break
+			}
+		}
+		if free {
+			return x, y, true
+		}
+	}
+	return 0, 0, false
+}
+
+// React moves one icon after a click while the challenge has moves left and
+// sends the redrawn canvas. The icon is drawn from all those still to be
+// clicked, decoys included, so a change of the canvas says nothing about
+// whether the click hit. Targets already clicked keep their place and the
+// delta carries only the image, never coordinates.
+func (g *ClickOrderGenerator) React(ctx context.Context, challenge *entity.Challenge, events []entity.BinaryEvent) ([]byte, error) {
+	data, ok := challenge.Data.(entity.ClickOrderData)
+	if !ok {
+		return nil, fmt.Errorf("неверный формат данных челленджа")
+	}
+	if data.Moves <= 0 || len(data.Icons) == 0 || countClicks(events) == 0 {
+		return nil, nil
+	}
+
+	clicked := countClicks(challenge.Events)
+	if clicked >= len(data.Targets) {
+		return nil, nil
+	}
+
+	done := make(map[int]bool, clicked)
+	for _, target := range data.Targets[:clicked] {
+		done[target.Icon] = true
+	}
+	icons := make([]imaging.Icon, 0, len(data.Icons))
+	movable := make([]int, 0, len(data.Icons))
+	for i, icon := range data.Icons {
+		icons = append(icons, imaging.Icon{
+			Shape: imaging.IconShape(icon.Shape),
+			Color: icon.Color,
+			X:     icon.X,
+			Y:     icon.Y,
+			Size:  icon.Size,
+			Angle: icon.Angle,
+		})
+		if !done[i] {
+			movable = append(movable, i)
+		}
+	}
+
+	seed, err := g.newSeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	moved := movable[rng.Intn(len(movable))]
+
+	// The old place counts as taken, so the icon visibly moves.
+	others := append(append([]imaging.Icon(nil), icons[:moved]...), icons[moved+1:]...)
+	others = append(others, icons[moved])
+	x, y, ok := freeSpot(rng, others, icons[moved].Size, data.CanvasWidth, data.CanvasHeight)
+	if !ok {
+		return nil, nil
+	}
+	icons[moved].X, icons[moved].Y = x, y
+
+	canvas, err := g.renderer.RedrawClickCanvas(rng, data.BackgroundSeed, icons, g.distortion.At(challenge.Complexity))
+	if err != nil {
+		return nil, fmt.Errorf("failed to redraw click order: %w", err)
+	}
+
+	data.Icons = append([]entity.ClickIcon(nil), data.Icons...)
+	data.Icons[moved].X, data.Icons[moved].Y = x, y
+	data.Targets = append([]entity.ClickTarget(nil), data.Targets...)
+	for i := range data.Targets {
+		if data.Targets[i].Icon == moved {
+			data.Targets[i].X, data.Targets[i].Y = x, y
+		}
+	}
+	data.Moves--
+	challenge.Data = data
+
+	return json.Marshal(map[string]string{
+		entity.FieldType: entity.EventTypeRedraw,
+		"canvas":         canvas,
+	})
+}
+
+func countClicks(events []entity.BinaryEvent) int {
+	clicks := 0
+	for _, event := range events {
+		if event.Type == entity.EventTypeClick {
+			clicks++
+		}
+	}
+	return clicks
+}
+
+// ValidatesEvents marks the recorded clicks as the answer itself.
+func (g *ClickOrderGenerator) ValidatesEvents() {}
+
+// Validate checks the recorded clicks: exactly one per target, each inside the
+// hit radius of the target of the same position in the order, with human
+// pauses between them. The submitted answer only triggers the check.
+func (g *ClickOrderGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	data, ok := challenge.Data.(entity.ClickOrderData)
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат данных челленджа")
+	}
+
+	clicks := make([]entity.BinaryEvent, 0, len(data.Targets))
+	for _, event := range challenge.Events {
+		if event.Type == entity.EventTypeClick {
+			clicks = append(clicks, event)
+		}
+	}
+	if len(clicks) != len(data.Targets) {
+		return false, 0, nil
+	}
+
+	var accuracy float64
+	for i, target := range data.Targets {
+		distance := math.Hypot(float64(clicks[i].X)-float64(target.X), float64(clicks[i].Y)-float64(target.Y))
+		if distance > float64(target.Radius) {
+			return false, 0, nil
+		}
+		accuracy += 1 - distance/float64(target.Radius+1)
+	}
+	accuracy /= float64(len(data.Targets))
+
+	intervals := make([]float64, 0, len(clicks)-1)
+	for i := 1; i < len(clicks); i++ {
+		interval := clicks[i].Timestamp - clicks[i-1].Timestamp
+		if interval < minClickIntervalMs || interval > maxClickIntervalMs {
+			return false, 0, nil
+		}
+		intervals = append(intervals, float64(interval))
+	}
+
+	rhythm := clamp01(variation(intervals) / humanClickVariation)
+	timing := timingScore(challenge, g.now())
+	confidence := int32(math.Round(100 * (0.5*accuracy + 0.2*rhythm + 0.3*timing)))
+
+	return true, confidence, nil
+}
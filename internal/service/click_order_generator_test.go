@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clicksOn clicks the targets in the given order with uneven, human pauses.
+func clicksOn(targets []entity.ClickTarget, order ...int) []entity.BinaryEvent {
+	pauses := []int64{0, 450, 700, 520, 880, 610}
+	events := make([]entity.BinaryEvent, 0, len(order))
+	timestamp := int64(1700000000000)
+	for i, target := range order {
+		timestamp += pauses[i%len(pauses)]
+		events = append(events, entity.BinaryEvent{
+			Type:      entity.EventTypeClick,
+			X:         int32(targets[target].X),
+			Y:         int32(targets[target].Y),
+			Timestamp: timestamp,
+		})
+	}
+	return events
+}
+
+func TestClickOrderValidateRequiresOrder(t *testing.T) {
+	generator, challenge := generateAt(t, entity.ChallengeTypeClickOrder, 50, 5*time.Second)
+	targets := challenge.Data.(entity.ClickOrderData).Targets
+	require.Len(t, targets, 4)
+
+	tests := []struct {
+		name   string
+		events []entity.BinaryEvent
+		valid  bool
+	}{
+		{name: "in order", events: clicksOn(targets, 0, 1, 2, 3), valid: true},
+		{name: "swapped", events: clicksOn(targets, 0, 2, 1, 3), valid: false},
+		{name: "reversed", events: clicksOn(targets, 3, 2, 1, 0), valid: false},
+		{name: "extra click", events: clicksOn(targets, 0, 1, 2, 3, 3), valid: false},
+		{name: "missing click", events: clicksOn(targets, 0, 1, 2), valid: false},
+		{name: "no clicks", events: nil, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			challenge.Events = tt.events
+
+			valid, _, err := generator.Validate(map[string]interface{}{}, challenge)
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, valid)
+		})
+	}
+}
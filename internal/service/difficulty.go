@@ -0,0 +1,203 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+)
+
+// Parameters every challenge type has to define.
+const (
+	ParamExpirationSec = "expiration_sec"
+	ParamMinTimeMs     = "min_time_ms"
+	ParamMaxTimeMs     = "max_time_ms"
+)
+
+const difficultyComplexityKey = "complexity"
+
+// difficultySchema lists the parameters of each challenge type on top of the
+// common ones.
+var difficultySchema = map[string][]string{
+	entity.ChallengeTypeSliderPuzzle: {"piece_size", "tolerance_px"},
+	entity.ChallengeTypeDragDrop:     {"object_size", "tolerance_px"},
+	entity.ChallengeTypeSteerGame:    {"goal_slack_px", "obstacles"},
+	entity.ChallengeTypeRotation:     {"diameter", "tolerance_deg"},
+	entity.ChallengeTypeClickOrder:   {"targets", "decoys", "icon_size", "moves"},
+	entity.ChallengeTypeAudio:        {"digits", "noise"},
+}
+
+type difficultyPoint struct {
+	complexity int32
+	params     DifficultyParams
+}
+
+// DifficultyProfiles maps complexity to generator parameters per challenge
+// type. Each type is a list of points; between two points every parameter is
+// interpolated linearly, outside of them the nearest point applies.
+type DifficultyProfiles struct {
+	curves map[string][]difficultyPoint
+}
+
+// DifficultyParams are the parameters of one challenge at one complexity.
+type DifficultyParams map[string]float64
+
+// LoadDifficultyProfiles reads and validates the profile file.
+func LoadDifficultyProfiles(path string) (*DifficultyProfiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read difficulty profiles: %w", err)
+	}
+
+	profiles, err := ParseDifficultyProfiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid difficulty profiles %s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// ParseDifficultyProfiles reads profiles of the form
+//
+//	{"slider-puzzle": [{"complexity": 0, "piece_size": 80, ...}, ...], ...}
+//
+// Every known challenge type must be present, points must be in increasing
+// complexity within 0..100 and each point must set exactly the parameters of
+// its type, none of them negative.
+func ParseDifficultyProfiles(data []byte) (*DifficultyProfiles, error) {
+	var raw map[string][]map[string]float64
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	for challengeType := range raw {
+		if _, ok := difficultySchema[challengeType]; !ok {
+			return nil, fmt.Errorf("unknown challenge type %q", challengeType)
+		}
+	}
+
+	types := make([]string, 0, len(difficultySchema))
+	for challengeType := range difficultySchema {
+		types = append(types, challengeType)
+	}
+	sort.Strings(types)
+
+	profiles := &DifficultyProfiles{curves: make(map[string][]difficultyPoint, len(types))}
+	for _, challengeType := range types {
+		points, ok := raw[challengeType]
+		if !ok || len(points) == 0 {
+			return nil, fmt.Errorf("%s: no points", challengeType)
+		}
+
+		required := append([]string{ParamExpirationSec, ParamMinTimeMs, ParamMaxTimeMs}, difficultySchema[challengeType]...)
+		curve := make([]difficultyPoint, 0, len(points))
+		for i, point := range points {
+			parsed, err := parseDifficultyPoint(point, required)
+			if err != nil {
+				return nil, fmt.Errorf("%s point %d: %w", challengeType, i, err)
+			}
+			if i > 0 && parsed.complexity <= curve[i-1].complexity {
+				return nil, fmt.Errorf("%s point %d: complexity %d does not follow %d",
+					challengeType, i, parsed.complexity, curve[i-1].complexity)
+			}
+			curve = append(curve, parsed)
+		}
+
+		profiles.curves[challengeType] = curve
+	}
+
+	return profiles, nil
+}
+
+func parseDifficultyPoint(point map[string]float64, required []string) (difficultyPoint, error) {
+	complexity, ok := point[difficultyComplexityKey]
+	if !ok {
+		return difficultyPoint{}, fmt.Errorf("missing %s", difficultyComplexityKey)
+	}
+	if complexity < 0 || complexity > 100 || complexity != math.Trunc(complexity) {
+		return difficultyPoint{}, fmt.Errorf("complexity %v is not an integer within 0..100", complexity)
+	}
+
+	params := make(DifficultyParams, len(required))
+	for _, name := range required {
+		value, ok := point[name]
+		if !ok {
+			return difficultyPoint{}, fmt.Errorf("missing %s", name)
+		}
+		if value < 0 {
+			return difficultyPoint{}, fmt.Errorf("%s is negative", name)
+		}
+		params[name] = value
+	}
+
+	if len(point) != len(required)+1 {
+		names := make([]string, 0, len(point))
+		for name := range point {
+			if _, ok := params[name]; !ok && name != difficultyComplexityKey {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		return difficultyPoint{}, fmt.Errorf("unknown parameters %v", names)
+	}
+
+	if params[ParamExpirationSec] == 0 {
+		return difficultyPoint{}, fmt.Errorf("%s must be positive", ParamExpirationSec)
+	}
+	if params[ParamMaxTimeMs] <= params[ParamMinTimeMs] {
+		return difficultyPoint{}, fmt.Errorf("%s must exceed %s", ParamMaxTimeMs, ParamMinTimeMs)
+	}
+
+	return difficultyPoint{complexity: int32(complexity), params: params}, nil
+}
+
+// At returns the parameters of a challenge type at the given complexity.
+func (d *DifficultyProfiles) At(challengeType string, complexity int32) DifficultyParams {
+	curve := d.curves[challengeType]
+	if len(curve) == 0 {
+		return DifficultyParams{}
+	}
+
+	next := sort.Search(len(curve), func(i int) bool { return curve[i].complexity >= complexity })
+	switch {
+	case next == 0:
+		return curve[0].params
+	case next == len(curve):
+		return curve[len(curve)-1].params
+	case curve[next].complexity == complexity:
+		return curve[next].params
+	}
+
+	from, to := curve[next-1], curve[next]
+	t := float64(complexity-from.complexity) / float64(to.complexity-from.complexity)
+
+	params := make(DifficultyParams, len(from.params))
+	for name, value := range from.params {
+		params[name] = value + (to.params[name]-value)*t
+	}
+	return params
+}
+
+func (p DifficultyParams) Float(name string) float64 {
+	return p[name]
+}
+
+// Int rounds the parameter to the nearest integer.
+func (p DifficultyParams) Int(name string) int {
+	return int(math.Round(p[name]))
+}
+
+func (p DifficultyParams) Expiration() time.Duration {
+	return time.Duration(p[ParamExpirationSec] * float64(time.Second))
+}
+
+func (p DifficultyParams) MinTimeMs() int64 {
+	return int64(math.Round(p[ParamMinTimeMs]))
+}
+
+func (p DifficultyParams) MaxTimeMs() int64 {
+	return int64(math.Round(p[ParamMaxTimeMs]))
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProfiles returns a valid profile file with the given slider-puzzle
+// points; every other type gets a single point.
+func testProfiles(t *testing.T, slider ...map[string]float64) []byte {
+	t.Helper()
+
+	raw := make(map[string][]map[string]float64, len(difficultySchema))
+	for challengeType, names := range difficultySchema {
+		point := map[string]float64{"complexity": 50, ParamExpirationSec: 180, ParamMinTimeMs: 1000, ParamMaxTimeMs: 30000}
+		for _, name := range names {
+			point[name] = 1
+		}
+		raw[challengeType] = []map[string]float64{point}
+	}
+	if slider != nil {
+		raw["slider-puzzle"] = slider
+	}
+
+	data, err := json.Marshal(raw)
+	require.NoError(t, err)
+	return data
+}
+
+func sliderPoint(complexity, pieceSize float64) map[string]float64 {
+	return map[string]float64{
+		"complexity": complexity, ParamExpirationSec: 300 - complexity, ParamMinTimeMs: 1000, ParamMaxTimeMs: 30000,
+		"piece_size": pieceSize, "tolerance_px": 10,
+	}
+}
+
+func TestLoadShippedDifficultyProfiles(t *testing.T) {
+	profiles, err := LoadDifficultyProfiles("../../difficulty.json")
+	require.NoError(t, err)
+
+	params := profiles.At("slider-puzzle", 50)
+	assert.Equal(t, 60, params.Int("piece_size"))
+	assert.Equal(t, 3*time.Minute, params.Expiration())
+
+	_, err = LoadDifficultyProfiles("missing.json")
+	assert.Error(t, err)
+}
+
+func TestDifficultyInterpolation(t *testing.T) {
+	profiles, err := ParseDifficultyProfiles(testProfiles(t, sliderPoint(20, 80), sliderPoint(60, 40), sliderPoint(100, 30)))
+	require.NoError(t, err)
+
+	params := profiles.At("slider-puzzle", 30)
+	assert.InDelta(t, 70, params.Float("piece_size"), 1e-9)
+	assert.Equal(t, 270*time.Second, params.Expiration())
+	assert.Equal(t, int64(1000), params.MinTimeMs())
+
+	assert.InDelta(t, 40, profiles.At("slider-puzzle", 60).Float("piece_size"), 1e-9, "exactly on a point")
+	assert.InDelta(t, 35, profiles.At("slider-puzzle", 80).Float("piece_size"), 1e-9)
+	assert.Equal(t, 38, profiles.At("slider-puzzle", 70).Int("piece_size"), "37.5 rounds to the nearest integer")
+
+	assert.InDelta(t, 80, profiles.At("slider-puzzle", 0).Float("piece_size"), 1e-9, "below the first point")
+	assert.InDelta(t, 30, profiles.At("slider-puzzle", 100).Float("piece_size"), 1e-9)
+
+	assert.Empty(t, profiles.At("no-such-type", 50))
+}
+
+func TestParseDifficultyProfilesRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data func(t *testing.T) []byte
+	}{
+		{"not json", func(t *testing.T) []byte { return []byte("{") }},
+		{"unknown type", func(t *testing.T) []byte {
+			var raw map[string]interface{}
+			require.NoError(t, json.Unmarshal(testProfiles(t), &raw))
+			raw["maze"] = raw["slider-puzzle"]
+			data, _ := json.Marshal(raw)
+			return data
+		}},
+		{"missing type", func(t *testing.T) []byte {
+			var raw map[string]interface{}
+			require.NoError(t, json.Unmarshal(testProfiles(t), &raw))
+			delete(raw, "audio")
+			data, _ := json.Marshal(raw)
+			return data
+		}},
+		{"no points", func(t *testing.T) []byte { return testProfiles(t, []map[string]float64{}...) }},
+		{"unordered points", func(t *testing.T) []byte { return testProfiles(t, sliderPoint(60, 40), sliderPoint(20, 80)) }},
+		{"repeated complexity", func(t *testing.T) []byte { return testProfiles(t, sliderPoint(20, 80), sliderPoint(20, 40)) }},
+		{"complexity out of range", func(t *testing.T) []byte { return testProfiles(t, sliderPoint(120, 40)) }},
+		{"fractional complexity", func(t *testing.T) []byte { return testProfiles(t, sliderPoint(20.5, 40)) }},
+		{"negative value", func(t *testing.T) []byte { return testProfiles(t, sliderPoint(20, -1)) }},
+		{"missing parameter", func(t *testing.T) []byte {
+			point := sliderPoint(20, 40)
+			delete(point, "tolerance_px")
+			return testProfiles(t, point)
+		}},
+		{"unknown parameter", func(t *testing.T) []byte {
+			point := sliderPoint(20, 40)
+			point["canvas_size"] = 400
+			return testProfiles(t, point)
+		}},
+		{"max time not above min time", func(t *testing.T) []byte {
+			point := sliderPoint(20, 40)
+			point[ParamMaxTimeMs] = point[ParamMinTimeMs]
+			return testProfiles(t, point)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDifficultyProfiles(tt.data(t))
+			assert.Error(t, err)
+		})
+	}
+}
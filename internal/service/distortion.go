@@ -0,0 +1,23 @@
+package service
+
+import (
+	"captcha-service/internal/infrastructure/imaging"
+	"captcha-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// distortionProfile parses the DISTORTION_* setting of a challenge type. A
+// broken setting is logged and disables distortions rather than failing every
+// challenge of that type.
+func distortionProfile(challengeType, spec string) imaging.DistortionProfile {
+	profile, err := imaging.ParseDistortionProfile(spec)
+	if err != nil {
+		logger.Error("Invalid distortion profile, distortions disabled",
+			zap.String("challengeType", challengeType),
+			zap.String("profile", spec),
+			zap.Error(err))
+		return imaging.DistortionProfile{}
+	}
+	return profile
+}
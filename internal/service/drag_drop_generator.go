@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+)
+
+const (
+	dragDropMargin = 10
+)
+
+type DragDropGenerator struct {
+	config         *config.CaptchaConfig
+	difficulty     *DifficultyProfiles
+	repo           ChallengeRepository
+	templateEngine TemplateEngine
+
+	challengeSource
+}
+
+func NewDragDropGenerator(config *config.CaptchaConfig, difficulty *DifficultyProfiles, repo ChallengeRepository, templateEngine TemplateEngine) *DragDropGenerator {
+	return &DragDropGenerator{
+		config:         config,
+		difficulty:     difficulty,
+		repo:           repo,
+		templateEngine: templateEngine,
+	}
+}
+
+func (g *DragDropGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	if complexity < 0 || complexity > 100 {
+		complexity = g.config.ComplexityMedium
+	}
+
+	canvasWidth := entity.CanvasWidth
+	canvasHeight := entity.CanvasHeight
+
+	seed, err := g.newSeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// Both the object and the slack around it shrink as complexity grows.
+	params := g.difficulty.At(entity.ChallengeTypeDragDrop, complexity)
+	objectSide := puzzleSize(params.Int("object_size"))
+	tolerance := params.Int("tolerance_px")
+	targetSide := objectSide + 2*tolerance
+
+	half := canvasWidth / 2
+	objectPosition := entity.Position{
+		X: dragDropMargin + rng.Intn(max(1, half-objectSide-2*dragDropMargin+1)),
+		Y: dragDropMargin + rng.Intn(max(1, canvasHeight-objectSide-2*dragDropMargin+1)),
+	}
+	targetPosition := entity.Position{
+		X: half + dragDropMargin + rng.Intn(max(1, half-targetSide-2*dragDropMargin+1)),
+		Y: dragDropMargin + rng.Intn(max(1, canvasHeight-targetSide-2*dragDropMargin+1)),
+	}
+
+	if rng.Intn(2) == 0 {
+		objectPosition.X = canvasWidth - objectPosition.X - objectSide
+		targetPosition.X = canvasWidth - targetPosition.X - targetSide
+	}
+
+	data := entity.DragDropChallenge{
+		UserID:         userID,
+		TargetPosition: targetPosition,
+		ObjectPosition: objectPosition,
+		ObjectSize:     entity.Size{Width: objectSide, Height: objectSide},
+		TargetSize:     entity.Size{Width: targetSide, Height: targetSide},
+		Tolerance:      tolerance,
+	}
+
+	challengeID, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := g.templateEngine.Render("drag_drop", map[string]interface{}{
+		"Lang":         LocaleFromContext(ctx),
+		"Text":         localizedTexts(ctx),
+		"ChallengeID":  challengeID,
+		"CanvasWidth":  canvasWidth,
+		"CanvasHeight": canvasHeight,
+		"ChallengeData": map[string]interface{}{
+			"challenge_id":    challengeID,
+			"user_id":         userID,
+			"object_position": data.ObjectPosition,
+			"object_size":     data.ObjectSize,
+			"target_position": data.TargetPosition,
+			"target_size":     data.TargetSize,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render drag drop template: %w", err)
+	}
+
+	now := g.now()
+	challenge := &entity.Challenge{
+		ID:         challengeID,
+		Type:       entity.ChallengeTypeDragDrop,
+		UserID:     userID,
+		Complexity: complexity,
+		Data: entity.DragDropData{
+			ChallengeData: data,
+			CanvasWidth:   canvasWidth,
+			CanvasHeight:  canvasHeight,
+		},
+		HTML:               html,
+		ExpiresAt:          now.Add(params.Expiration()),
+		CreatedAt:          now,
+		Attempts:           0,
+		MaxAttempts:        g.config.MaxAttempts,
+		MinTime:            params.MinTimeMs(),
+		MaxTime:            params.MaxTimeMs(),
+		IsBlocked:          false,
+		BlockReason:        "",
+		TimeoutAttempts:    0,
+		MaxTimeoutAttempts: g.config.MaxTimeoutAttempts,
+		Seed:               g.recordedSeed(seed),
+	}
+
+	return challenge, nil
+}
+
+// Validate expects the top-left corner of the dropped object and accepts it
+// when at least MinOverlapPct of the object lies inside the target.
+func (g *DragDropGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	answerMap, ok := answer.(map[string]interface{})
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат ответа")
+	}
+
+	answeredX, xOk := answerMap["x"].(float64)
+	answeredY, yOk := answerMap["y"].(float64)
+	if !xOk || !yOk {
+		return false, 0, fmt.Errorf("неверные координаты ответа")
+	}
+
+	data, ok := challenge.Data.(entity.DragDropData)
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат данных челленджа")
+	}
+
+	object := data.ChallengeData.ObjectSize
+	target := data.ChallengeData.TargetSize
+	targetPosition := data.ChallengeData.TargetPosition
+
+	overlapW := overlap(answeredX, float64(object.Width), float64(targetPosition.X), float64(target.Width))
+	overlapH := overlap(answeredY, float64(object.Height), float64(targetPosition.Y), float64(target.Height))
+	overlapRatio := overlapW * overlapH / float64(object.Width*object.Height)
+
+	if overlapRatio*100 < float64(g.config.MinOverlapPct) {
+		return false, 0, nil
+	}
+
+	timing := timingScore(challenge, g.now())
+	confidence := int32(math.Round(100 * (0.6*overlapRatio + 0.4*timing)))
+
+	return true, confidence, nil
+}
+
+func overlap(start, length, otherStart, otherLength float64) float64 {
+	return math.Max(0, math.Min(start+length, otherStart+otherLength)-math.Max(start, otherStart))
+}
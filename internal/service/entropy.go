@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// Clock tells generators the time; tests replace it to get stable challenges.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+type seedKey struct{}
+
+// WithSeed makes a generator draw the challenge from seed instead of a fresh
+// one. Together with the same type, complexity, locale and user it renders
+// the same puzzle again, which is how a challenge recorded in debug mode is
+// replayed.
+func WithSeed(ctx context.Context, seed int64) context.Context {
+	return context.WithValue(ctx, seedKey{}, seed)
+}
+
+func SeedFromContext(ctx context.Context) (int64, bool) {
+	seed, ok := ctx.Value(seedKey{}).(int64)
+	return seed, ok
+}
+
+// challengeSource supplies a generator with challenge seeds, IDs and the
+// time. The zero value reads crypto/rand and the system clock; tests inject a
+// fixed entropy stream and clock to make generation reproducible.
+type challengeSource struct {
+	entropy     io.Reader
+	clock       Clock
+	recordSeeds bool
+	idPrefix    string
+	mu          sync.Mutex
+}
+
+// SetEntropy replaces the source of seeds and challenge IDs. It is read under
+// a lock, so non thread-safe readers such as math/rand are fine.
+func (s *challengeSource) SetEntropy(entropy io.Reader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entropy = entropy
+}
+
+func (s *challengeSource) SetClock(clock Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+}
+
+// SetRecordSeeds keeps the seed of every generated challenge on it, so the
+// challenge can be replayed with WithSeed. Meant for debugging only: the seed
+// gives the answer away to whoever can read the stored challenge.
+func (s *challengeSource) SetRecordSeeds(record bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordSeeds = record
+}
+
+// SetIDPrefix puts the issuing instance in front of every challenge ID, see
+// entity.IssuingInstance.
+func (s *challengeSource) SetIDPrefix(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.idPrefix = prefix
+}
+
+func (s *challengeSource) now() time.Time {
+	s.mu.Lock()
+	clock := s.clock
+	s.mu.Unlock()
+
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
+
+// newSeed returns the seed requested by ctx, or a fresh one.
+func (s *challengeSource) newSeed(ctx context.Context) (int64, error) {
+	if seed, ok := SeedFromContext(ctx); ok {
+		return seed, nil
+	}
+
+	var buf [8]byte
+	s.mu.Lock()
+	_, err := io.ReadFull(s.reader(), buf[:])
+	s.mu.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read seed: %w", err)
+	}
+	return int64(binary.BigEndian.Uint64(buf[:]) & math.MaxInt64), nil
+}
+
+// newID returns a random UUID, behind the ID prefix if one is set. IDs
+// always come from the entropy source, so a replayed challenge gets an ID of
+// its own.
+func (s *challengeSource) newID() (string, error) {
+	s.mu.Lock()
+	id, err := uuid.NewRandomFromReader(s.reader())
+	prefix := s.idPrefix
+	s.mu.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate challenge id: %w", err)
+	}
+	if prefix == "" {
+		return id.String(), nil
+	}
+	return prefix + entity.ChallengeIDSeparator + id.String(), nil
+}
+
+// recordedSeed is the seed to keep on the challenge: zero unless recording.
+func (s *challengeSource) recordedSeed(seed int64) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.recordSeeds {
+		return 0
+	}
+	return seed
+}
+
+func (s *challengeSource) reader() io.Reader {
+	if s.entropy == nil {
+		return rand.Reader
+	}
+	return s.entropy
+}
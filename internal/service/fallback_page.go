@@ -0,0 +1,66 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"captcha-service/internal/domain/entity"
+)
+
+// fallbackPage is served when a generator returns no HTML of its own, e.g. a
+// plugin that only defines the answer: two sliders for a point, reported
+// through the client runtime like any other challenge.
+const fallbackPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="UTF-8" />
+  <meta name="viewport" content="width=device-width,initial-scale=1" />
+  <title>Captcha</title>
+</head>
+<body>
+  <div class="captcha-container">
+    <h3>Captcha</h3>
+    <p>Complexity: %d</p>
+    <div class="slider-area">
+      <input type="range" id="xSlider" min="0" max="%d" value="0">
+      <input type="range" id="ySlider" min="0" max="%d" value="0">
+    </div>
+    <button id="validateBtn">Validate</button>
+    <p id="msg"></p>
+  </div>
+  <script>
+    const captcha = CaptchaRuntime.start(%s);
+    const xSlider = document.getElementById("xSlider");
+    const ySlider = document.getElementById("ySlider");
+    const msg = document.getElementById("msg");
+
+    function position() {
+      return { x: parseInt(xSlider.value) || 0, y: parseInt(ySlider.value) || 0 };
+    }
+
+    [xSlider, ySlider].forEach((slider) => slider.addEventListener("input", () => {
+      const p = position();
+      captcha.push(captcha.TYPES.SLIDER_MOVED, p.x, p.y);
+    }));
+
+    document.getElementById("validateBtn").addEventListener("click", () => {
+      msg.textContent = "";
+      captcha.validate(position());
+    });
+
+    captcha.onResult((valid) => {
+      msg.textContent = valid ? "OK" : "Try again";
+    });
+  </script>
+</body>
+</html>
+`
+
+func fallbackChallengeHTML(challenge *entity.Challenge) string {
+	// json.Marshal escapes <, > and &, so the IDs cannot leave the script.
+	options, _ := json.Marshal(map[string]string{
+		"challengeId": challenge.ID,
+		"userId":      challenge.UserID,
+	})
+	return fmt.Sprintf(fallbackPage, challenge.Complexity, entity.CanvasWidth, entity.CanvasHeight, options)
+}
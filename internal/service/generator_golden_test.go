@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/audio"
+	"captcha-service/internal/infrastructure/imaging"
+	"captcha-service/internal/infrastructure/template"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var goldenTime = fixedClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+// seededGenerator is a generator whose entropy, clock and seed recording can
+// be replaced, as all built-in generators allow.
+type seededGenerator interface {
+	ChallengeGenerator
+	SetEntropy(entropy io.Reader)
+	SetClock(clock Clock)
+	SetRecordSeeds(record bool)
+}
+
+func newGoldenGenerators(t *testing.T) map[string]seededGenerator {
+	t.Helper()
+
+	cfg := &config.CaptchaConfig{
+		ComplexityMedium:       50,
+		MaxAttempts:            3,
+		MaxTimeoutAttempts:     3,
+		DistortionSliderPuzzle: "from=20,decoys=2,soften=0.7,jitter=0.35,noise=0.4,warp=2.5,shadows=3",
+		DistortionRotation:     "from=20,jitter=0.4,noise=0.5,warp=4,shadows=3",
+		DistortionClickOrder:   "from=20,jitter=0.3,noise=0.4,warp=3,shadows=4",
+	}
+	difficulty, err := LoadDifficultyProfiles("../../difficulty.json")
+	require.NoError(t, err)
+	templates := template.NewTemplateEngineService("../../templates")
+	renderer, err := imaging.NewRenderer("../../backgrounds", entity.CanvasWidth, entity.CanvasHeight, imaging.BackgroundOptions{
+		Mode: imaging.BackgroundModeProcedural,
+	})
+	require.NoError(t, err)
+
+	return map[string]seededGenerator{
+		entity.ChallengeTypeSliderPuzzle: NewSliderPuzzleGenerator(cfg, difficulty, nil, templates, renderer),
+		entity.ChallengeTypeDragDrop:     NewDragDropGenerator(cfg, difficulty, nil, templates),
+		entity.ChallengeTypeSteerGame:    NewSteerGameGenerator(cfg, difficulty, nil, templates),
+		entity.ChallengeTypeRotation:     NewRotationGenerator(cfg, difficulty, nil, templates, renderer),
+		entity.ChallengeTypeClickOrder:   NewClickOrderGenerator(cfg, difficulty, nil, templates, renderer),
+		entity.ChallengeTypeAudio:        NewAudioGenerator(cfg, difficulty, nil, templates, audio.NewSynthesizer(audio.DefaultSampleRate)),
+	}
+}
+
+// goldenChallenge is what a golden file records of a challenge. The HTML
+// embeds the rendered images, so only its digest is kept.
+type goldenChallenge struct {
+	ID         string               `json:"id"`
+	Type       string               `json:"type"`
+	Complexity int32                `json:"complexity"`
+	Data       entity.ChallengeData `json:"data"`
+	HTMLSHA256 string               `json:"html_sha256"`
+	CreatedAt  time.Time            `json:"created_at"`
+	ExpiresAt  time.Time            `json:"expires_at"`
+	MinTime    int64                `json:"min_time"`
+	MaxTime    int64                `json:"max_time"`
+	Seed       int64                `json:"seed"`
+}
+
+func goldenOf(challenge *entity.Challenge) goldenChallenge {
+	digest := sha256.Sum256([]byte(challenge.HTML))
+	return goldenChallenge{
+		ID:         challenge.ID,
+		Type:       challenge.Type,
+		Complexity: challenge.Complexity,
+		Data:       challenge.Data,
+		HTMLSHA256: hex.EncodeToString(digest[:]),
+		CreatedAt:  challenge.CreatedAt,
+		ExpiresAt:  challenge.ExpiresAt,
+		MinTime:    challenge.MinTime,
+		MaxTime:    challenge.MaxTime,
+		Seed:       challenge.Seed,
+	}
+}
+
+func TestGeneratorsGolden(t *testing.T) {
+	for challengeType, generator := range newGoldenGenerators(t) {
+		t.Run(challengeType, func(t *testing.T) {
+			generator.SetEntropy(rand.New(rand.NewSource(42)))
+			generator.SetClock(goldenTime)
+			generator.SetRecordSeeds(true)
+
+			ctx := WithLocale(context.Background(), "en")
+			challenge, err := generator.Generate(ctx, 70, "golden_user")
+			require.NoError(t, err)
+
+			got, err := json.MarshalIndent(goldenOf(challenge), "", "  ")
+			require.NoError(t, err)
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", "golden", challengeType+".json")
+			if *updateGolden {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, got, 0o644))
+			}
+
+			want, err := os.ReadFile(path)
+			require.NoError(t, err, "run go test with -update to create the golden file")
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestGeneratorsReplaySeed(t *testing.T) {
+	for challengeType, generator := range newGoldenGenerators(t) {
+		t.Run(challengeType, func(t *testing.T) {
+			generator.SetRecordSeeds(true)
+
+			ctx := WithLocale(context.Background(), "ru")
+			original, err := generator.Generate(ctx, 30, "replay_user")
+			require.NoError(t, err)
+			require.NotZero(t, original.Seed)
+
+			replayed, err := generator.Generate(WithSeed(ctx, original.Seed), 30, "replay_user")
+			require.NoError(t, err)
+
+			assert.NotEqual(t, original.ID, replayed.ID)
+			assert.Equal(t, original.Seed, replayed.Seed)
+			assert.Equal(t, original.Data, replayed.Data)
+			assert.Equal(t,
+				strings.ReplaceAll(original.HTML, original.ID, "id"),
+				strings.ReplaceAll(replayed.HTML, replayed.ID, "id"))
+		})
+	}
+}
+
+func TestGeneratorsDefaultSource(t *testing.T) {
+	generators := newGoldenGenerators(t)
+	generator := generators[entity.ChallengeTypeDragDrop]
+
+	first, err := generator.Generate(context.Background(), 50, "user")
+	require.NoError(t, err)
+	second, err := generator.Generate(context.Background(), 50, "user")
+	require.NoError(t, err)
+
+	for _, challenge := range []*entity.Challenge{first, second} {
+		_, err := uuid.Parse(challenge.ID)
+		assert.NoError(t, err)
+		assert.Zero(t, challenge.Seed, "seeds are only recorded in debug mode")
+	}
+	assert.NotEqual(t, first.ID, second.ID)
+}
+
+// generateAt generates a challenge of the given type at goldenTime and moves
+// the generator clock by elapsed, as if the answer came that much later.
+func generateAt(t *testing.T, challengeType string, complexity int32, elapsed time.Duration) (seededGenerator, *entity.Challenge) {
+	t.Helper()
+
+	generator := newGoldenGenerators(t)[challengeType]
+	generator.SetEntropy(rand.New(rand.NewSource(7)))
+	generator.SetClock(goldenTime)
+
+	challenge, err := generator.Generate(context.Background(), complexity, "user-1")
+	require.NoError(t, err)
+
+	generator.SetClock(fixedClock(time.Time(goldenTime).Add(elapsed)))
+	return generator, challenge
+}
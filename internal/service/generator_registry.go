@@ -2,18 +2,82 @@ package service
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"captcha-service/internal/domain/entity"
 )
 
 type ChallengeGenerator interface {
 	Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error)
-	Validate(answer interface{}, data interface{}) (bool, int32, error)
+	Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error)
+}
+
+// InteractiveGenerator is implemented by challenges that are played over the
+// event stream: the server runs the simulation and Validate reports how the
+// session ended instead of checking a submitted answer.
+type InteractiveGenerator interface {
+	ChallengeGenerator
+	StartSession(challenge *entity.Challenge) (GameSession, error)
+}
+
+// EventSequenceGenerator is implemented by challenges whose answer is the
+// recorded event sequence itself. Validate already judged the events, so they
+// are not scored again as a pointer trajectory.
+type EventSequenceGenerator interface {
+	ChallengeGenerator
+	ValidatesEvents()
+}
+
+// TypedAnswerGenerator is implemented by challenges answered from the keyboard,
+// which leave no pointer trajectory to score.
+type TypedAnswerGenerator interface {
+	ChallengeGenerator
+	TypedAnswer()
+}
+
+// EventHandlingGenerator is implemented by challenges that react to frontend
+// events of their own; a non-empty reply is sent back to the client.
+type EventHandlingGenerator interface {
+	ChallengeGenerator
+	HandleEvent(ctx context.Context, challenge *entity.Challenge, eventType string, data []byte) ([]byte, error)
+}
+
+// MutatingGenerator is implemented by challenges that change while they are
+// being solved. React sees every frame of pointer events once it is recorded,
+// with the whole recorded history in challenge.Events, and may change the
+// challenge: moving a target, swapping an icon, adding a decoy. A non-empty
+// delta means the challenge changed; it is stored before the delta is pushed
+// to the client, so Validate always judges the latest state.
+type MutatingGenerator interface {
+	ChallengeGenerator
+	React(ctx context.Context, challenge *entity.Challenge, events []entity.BinaryEvent) ([]byte, error)
+}
+
+// unwrapGenerator strips decorators such as the challenge pool, so that the
+// optional interfaces above are checked on the generator itself.
+func unwrapGenerator(generator ChallengeGenerator) ChallengeGenerator {
+	for {
+		wrapped, ok := generator.(interface{ Unwrap() ChallengeGenerator })
+		if !ok {
+			return generator
+		}
+		generator = wrapped.Unwrap()
+	}
+}
+
+type GameSession interface {
+	Input(frame []byte) error
+	// Step advances the simulation by one tick and returns the state frame to
+	// send, or nil when nothing changed.
+	Step() (frame []byte, done bool)
+	TickInterval() time.Duration
 }
 
 type GeneratorRegistry struct {
 	generators map[string]ChallengeGenerator
+	idPrefix   string
 	mu         sync.RWMutex
 }
 
@@ -26,6 +90,9 @@ func NewGeneratorRegistry() *GeneratorRegistry {
 func (r *GeneratorRegistry) Register(name string, generator ChallengeGenerator) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if r.idPrefix != "" {
+		setIDPrefix(generator, r.idPrefix)
+	}
 	r.generators[name] = generator
 }
 
@@ -35,3 +102,47 @@ func (r *GeneratorRegistry) Get(name string) (ChallengeGenerator, bool) {
 	generator, exists := r.generators[name]
 	return generator, exists
 }
+
+// SetRecordSeeds turns debug seed recording on or off for every registered
+// generator that draws its challenges from a seed.
+func (r *GeneratorRegistry) SetRecordSeeds(record bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, generator := range r.generators {
+		if seeded, ok := unwrapGenerator(generator).(interface{ SetRecordSeeds(bool) }); ok {
+			seeded.SetRecordSeeds(record)
+		}
+	}
+}
+
+// SetIDPrefix makes every generator that draws its own IDs put prefix in
+// front of them, including generators registered later such as plugins. The
+// service passes its instance ID.
+func (r *GeneratorRegistry) SetIDPrefix(prefix string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.idPrefix = prefix
+	for _, generator := range r.generators {
+		setIDPrefix(generator, prefix)
+	}
+}
+
+func setIDPrefix(generator ChallengeGenerator, prefix string) {
+	if prefixed, ok := unwrapGenerator(generator).(interface{ SetIDPrefix(string) }); ok {
+		prefixed.SetIDPrefix(prefix)
+	}
+}
+
+func (r *GeneratorRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.generators))
+	for name := range r.generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -51,13 +51,15 @@ func (b *GlobalUserBlocker) BlockUser(userID, reason string) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	blockedUntil := time.Now().Add(time.Duration(b.config.BlockDurationMin) * time.Minute)
+	now := time.Now()
+	blockedUntil := now.Add(time.Duration(b.config.BlockDurationMin) * time.Minute)
 
 	blockedUser := &entity.BlockedUser{
 		UserID:       userID,
 		BlockedUntil: blockedUntil,
 		Reason:       reason,
 		Attempts:     0,
+		BlockedAt:    now,
 	}
 
 	b.blockedUsers[userID] = blockedUser
@@ -70,6 +72,81 @@ func (b *GlobalUserBlocker) BlockUser(userID, reason string) error {
 	return nil
 }
 
+// BlockUserUntil records a block decided elsewhere, e.g. by the balancer,
+// keeping the time it was first set and its expiry instead of starting a new
+// configured period. Only a block set after the recorded one replaces it, so
+// passing the same block around never extends it.
+func (b *GlobalUserBlocker) BlockUserUntil(userID, reason string, blockedAt, blockedUntil time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if blockedUser, exists := b.blockedUsers[userID]; exists {
+		if !blockedUser.BlockedUntil.IsZero() && !blockedAt.After(blockedUser.BlockedAt) {
+			return
+		}
+		blockedUser.BlockedAt = blockedAt
+		blockedUser.BlockedUntil = blockedUntil
+		blockedUser.Reason = reason
+		return
+	}
+
+	b.blockedUsers[userID] = &entity.BlockedUser{
+		UserID:       userID,
+		BlockedUntil: blockedUntil,
+		Reason:       reason,
+		BlockedAt:    blockedAt,
+	}
+}
+
+// SyncBlockedUsers makes the local blocks match the cluster-wide list.
+// Blocks imposed after since are kept even when missing from the list:
+// they may not have reached the balancer yet. It returns the users it
+// unblocked.
+func (b *GlobalUserBlocker) SyncBlockedUsers(blockedUsers []*entity.BlockedUser, since time.Time) []string {
+	listed := make(map[string]struct{}, len(blockedUsers))
+	for _, blockedUser := range blockedUsers {
+		listed[blockedUser.UserID] = struct{}{}
+		b.BlockUserUntil(blockedUser.UserID, blockedUser.Reason, blockedUser.BlockedAt, blockedUser.BlockedUntil)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var unblocked []string
+	now := time.Now()
+	for userID, blockedUser := range b.blockedUsers {
+		if _, ok := listed[userID]; ok {
+			continue
+		}
+		if blockedUser.BlockedUntil.IsZero() || now.After(blockedUser.BlockedUntil) || blockedUser.BlockedAt.After(since) {
+			continue
+		}
+
+		blockedUser.Attempts = 0
+		blockedUser.BlockedUntil = time.Time{}
+		blockedUser.Reason = ""
+		unblocked = append(unblocked, userID)
+		logger.Info("User unblocked by balancer", zap.String("userID", userID))
+	}
+	return unblocked
+}
+
+// GetBlockedUsers returns the users whose block is still in force.
+func (b *GlobalUserBlocker) GetBlockedUsers() []*entity.BlockedUser {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	now := time.Now()
+	blockedUsers := make([]*entity.BlockedUser, 0)
+	for _, blockedUser := range b.blockedUsers {
+		if now.Before(blockedUser.BlockedUntil) {
+			copied := *blockedUser
+			blockedUsers = append(blockedUsers, &copied)
+		}
+	}
+	return blockedUsers
+}
+
 func (b *GlobalUserBlocker) UnblockUser(userID string) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -132,6 +209,7 @@ func (b *GlobalUserBlocker) RecordAttempt(userID, challengeID string) (isBlocked
 	if blockedUser.Attempts >= b.config.MaxAttempts {
 		blockedUser.BlockedUntil = now.Add(time.Duration(b.config.BlockDurationMin) * time.Minute)
 		blockedUser.Reason = "Too many failed attempts"
+		blockedUser.BlockedAt = now
 
 		logger.Warn("User blocked due to max attempts",
 			zap.String("userID", userID),
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	DefaultLocale = "ru"
+)
+
+type localeKey struct{}
+
+var challengeTexts = map[string]map[string]string{
+	"ru": {
+		"SliderTitle":       "Переместите слайдер, чтобы вставить фрагмент",
+		"DragDropTitle":     "Перетащите объект в цель",
+		"DragDropHint":      "Перетащите синий квадрат в красную область",
+		"DragDropTarget":    "ЦЕЛЬ",
+		"Success":           "Успешно! Капча пройдена.",
+		"Retry":             "Неверно. Попробуйте ещё раз.",
+		"PageTitleSlider":   "Капча — Слайдер-пазл",
+		"PageTitleDragDrop": "Капча — Перетаскивание",
+		"SteerTitle":        "Доведите шар до цели",
+		"SteerHint":         "Стрелки, WASD или зажатая кнопка мыши",
+		"SteerTimeout":      "Время вышло. Попробуйте ещё раз.",
+		"PageTitleSteer":    "Капча — Игра",
+		"RotationTitle":     "Поверните картинку, чтобы она стояла ровно",
+		"PageTitleRotation": "Капча — Поворот",
+		"ClickOrderTitle":   "Нажмите на значки в указанном порядке",
+		"PageTitleClick":    "Капча — Порядок нажатий",
+		"AudioTitle":        "Прослушайте запись",
+		"AudioHint":         "После сигнала звучат группы гудков. Введите, сколько гудков в каждой группе, например 352",
+		"AudioSubmit":       "Проверить",
+		"AudioSwitch":       "Аудиокапча",
+		"PageTitleAudio":    "Капча — Аудио",
+	},
+	"en": {
+		"SliderTitle":       "Move the slider to fit the piece",
+		"DragDropTitle":     "Drag the object onto the target",
+		"DragDropHint":      "Drag the blue square into the red area",
+		"DragDropTarget":    "TARGET",
+		"Success":           "Success! Captcha passed.",
+		"Retry":             "Incorrect. Please try again.",
+		"PageTitleSlider":   "Captcha — Slider puzzle",
+		"PageTitleDragDrop": "Captcha — Drag and drop",
+		"SteerTitle":        "Steer the ball into the goal",
+		"SteerHint":         "Arrow keys, WASD or hold the mouse button",
+		"SteerTimeout":      "Time is up. Please try again.",
+		"PageTitleSteer":    "Captcha — Game",
+		"RotationTitle":     "Rotate the picture until it is upright",
+		"PageTitleRotation": "Captcha — Rotation",
+		"ClickOrderTitle":   "Click the icons in the order shown",
+		"PageTitleClick":    "Captcha — Click order",
+		"AudioTitle":        "Listen to the recording",
+		"AudioHint":         "After the chime you will hear groups of beeps. Type how many beeps each group has, e.g. 352",
+		"AudioSubmit":       "Verify",
+		"AudioSwitch":       "Audio challenge",
+		"PageTitleAudio":    "Captcha — Audio",
+	},
+}
+
+// WithLocale attaches the requested UI locale so generators can pick the
+// template language without changing the ChallengeGenerator interface.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, NormalizeLocale(locale))
+}
+
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// NormalizeLocale reduces tags like "en-US" to a supported language and falls
+// back to DefaultLocale.
+func NormalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if _, ok := challengeTexts[locale]; !ok {
+		return DefaultLocale
+	}
+	return locale
+}
+
+func localizedTexts(ctx context.Context) map[string]string {
+	return challengeTexts[LocaleFromContext(ctx)]
+}
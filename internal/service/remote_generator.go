@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pluginProto "captcha-service/gen/proto/plugin"
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/pkg/logger"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Used when a plugin leaves the expiration of a challenge unset.
+const defaultPluginExpiration = 3 * time.Minute
+
+// remotePlugin is the connection to one out-of-process generator.
+type remotePlugin struct {
+	address string
+	conn    *grpc.ClientConn
+	client  pluginProto.GeneratorPluginClient
+	health  healthpb.HealthClient
+
+	name       string
+	types      []string
+	registered bool
+	healthy    atomic.Bool
+}
+
+// PluginHost connects to out-of-process generators, registers the challenge
+// types they serve and keeps checking their health. Plugins that are down at
+// startup are registered as soon as they answer.
+type PluginHost struct {
+	registry *GeneratorRegistry
+	config   *config.CaptchaConfig
+	timeout  time.Duration
+	interval time.Duration
+	dialOpts []grpc.DialOption
+
+	plugins []*remotePlugin
+	mu      sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewPluginHost(cfg *config.CaptchaConfig, registry *GeneratorRegistry) *PluginHost {
+	interval := time.Duration(cfg.PluginHealthIntervalSec) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PluginHost{
+		registry: registry,
+		config:   cfg,
+		timeout:  time.Duration(cfg.PluginTimeoutMs) * time.Millisecond,
+		interval: interval,
+		dialOpts: []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// SetDialOptions adds options to the connections of plugins added later, e.g.
+// a dialer for plugins that are not reachable over TCP.
+func (h *PluginHost) SetDialOptions(opts ...grpc.DialOption) {
+	h.dialOpts = append(h.dialOpts, opts...)
+}
+
+// Add connects to the plugin at address and registers its challenge types
+// once it has described itself.
+func (h *PluginHost) Add(address string) error {
+	conn, err := grpc.NewClient(address, h.dialOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to plugin %s: %w", address, err)
+	}
+
+	plugin := &remotePlugin{
+		address: address,
+		conn:    conn,
+		client:  pluginProto.NewGeneratorPluginClient(conn),
+		health:  healthpb.NewHealthClient(conn),
+	}
+
+	h.mu.Lock()
+	h.plugins = append(h.plugins, plugin)
+	h.mu.Unlock()
+
+	h.wg.Add(1)
+	go h.supervise(plugin)
+	return nil
+}
+
+func (h *PluginHost) Stop() {
+	h.cancel()
+	h.wg.Wait()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, plugin := range h.plugins {
+		plugin.conn.Close()
+	}
+}
+
+func (h *PluginHost) supervise(plugin *remotePlugin) {
+	defer h.wg.Done()
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		if plugin.registered {
+			h.checkHealth(plugin)
+		} else {
+			h.register(plugin)
+		}
+
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *PluginHost) register(plugin *remotePlugin) {
+	ctx, cancel := context.WithTimeout(h.ctx, h.timeout)
+	defer cancel()
+
+	description, err := plugin.client.Describe(ctx, &pluginProto.DescribeRequest{})
+	if err != nil {
+		logger.Warn("Plugin not reachable yet", zap.String("address", plugin.address), zap.Error(err))
+		return
+	}
+
+	plugin.name = description.Name
+	for _, challengeType := range description.ChallengeTypes {
+		if _, exists := h.registry.Get(challengeType); exists {
+			logger.Warn("Plugin challenge type already registered, skipped",
+				zap.String("plugin", plugin.name),
+				zap.String("challengeType", challengeType))
+			continue
+		}
+
+		h.registry.Register(challengeType, &RemoteGenerator{
+			plugin:        plugin,
+			challengeType: challengeType,
+			config:        h.config,
+			timeout:       h.timeout,
+		})
+		plugin.types = append(plugin.types, challengeType)
+	}
+
+	plugin.registered = true
+	plugin.healthy.Store(true)
+	logger.Info("Plugin registered",
+		zap.String("plugin", plugin.name),
+		zap.String("version", description.Version),
+		zap.String("address", plugin.address),
+		zap.Strings("challengeTypes", plugin.types))
+}
+
+func (h *PluginHost) checkHealth(plugin *remotePlugin) {
+	ctx, cancel := context.WithTimeout(h.ctx, h.timeout)
+	defer cancel()
+
+	response, err := plugin.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	healthy := err == nil && response.Status == healthpb.HealthCheckResponse_SERVING
+
+	if plugin.healthy.Swap(healthy) != healthy {
+		if healthy {
+			logger.Info("Plugin is healthy again", zap.String("plugin", plugin.name))
+		} else {
+			logger.Warn("Plugin is unhealthy, its challenges are unavailable",
+				zap.String("plugin", plugin.name),
+				zap.Error(err))
+		}
+	}
+}
+
+// RemoteGenerator serves one challenge type of a plugin. Challenges are stored
+// by the captcha service as usual, with the plugin state as their data; the
+// recorded events go to the plugin, which judges them itself.
+type RemoteGenerator struct {
+	plugin        *remotePlugin
+	challengeType string
+	config        *config.CaptchaConfig
+	timeout       time.Duration
+
+	challengeSource
+}
+
+func (g *RemoteGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	if !g.plugin.healthy.Load() {
+		return nil, entity.ErrGeneratorUnavailable
+	}
+	if complexity < 0 || complexity > 100 {
+		complexity = g.config.ComplexityMedium
+	}
+
+	challengeID, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	response, err := g.plugin.client.Generate(ctx, &pluginProto.GenerateRequest{
+		ChallengeType: g.challengeType,
+		ChallengeId:   challengeID,
+		UserId:        userID,
+		Complexity:    complexity,
+		Locale:        LocaleFromContext(ctx),
+	})
+	if err != nil {
+		return nil, g.callError("generate", err)
+	}
+
+	now := g.now()
+	expiration := time.Duration(response.ExpirationSec) * time.Second
+	if expiration <= 0 {
+		expiration = defaultPluginExpiration
+	}
+
+	challenge := &entity.Challenge{
+		ID:         challengeID,
+		Type:       g.challengeType,
+		UserID:     userID,
+		Complexity: complexity,
+		Data: entity.PluginData{
+			Type:   g.challengeType,
+			Plugin: g.plugin.name,
+			State:  response.State,
+		},
+		HTML:               response.Html,
+		ExpiresAt:          now.Add(expiration),
+		CreatedAt:          now,
+		Attempts:           0,
+		MaxAttempts:        g.config.MaxAttempts,
+		MinTime:            response.MinTimeMs,
+		MaxTime:            response.MaxTimeMs,
+		IsBlocked:          false,
+		BlockReason:        "",
+		TimeoutAttempts:    0,
+		MaxTimeoutAttempts: g.config.MaxTimeoutAttempts,
+	}
+
+	return challenge, nil
+}
+
+// ValidatesEvents marks the events as judged by the plugin.
+func (g *RemoteGenerator) ValidatesEvents() {}
+
+// Validate forwards the answer, the plugin state and the recorded events. An
+// InvalidArgument status from the plugin is a malformed answer and its message
+// is returned as is.
+func (g *RemoteGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	data, ok := challenge.Data.(entity.PluginData)
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат данных челленджа")
+	}
+
+	encoded, err := json.Marshal(answer)
+	if err != nil {
+		return false, 0, fmt.Errorf("неверный формат ответа")
+	}
+
+	events := make([]*pluginProto.Event, 0, len(challenge.Events))
+	for _, event := range challenge.Events {
+		events = append(events, &pluginProto.Event{
+			Type:      int32(event.Type),
+			X:         event.X,
+			Y:         event.Y,
+			Timestamp: event.Timestamp,
+		})
+	}
+
+	start := challenge.CreatedAt
+	if challenge.StartTime != nil {
+		start = *challenge.StartTime
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+
+	response, err := g.plugin.client.Validate(ctx, &pluginProto.ValidateRequest{
+		ChallengeType: g.challengeType,
+		ChallengeId:   challenge.ID,
+		Complexity:    challenge.Complexity,
+		State:         data.State,
+		Answer:        encoded,
+		Events:        events,
+		ElapsedMs:     g.now().Sub(start).Milliseconds(),
+	})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			return false, 0, errors.New(status.Convert(err).Message())
+		}
+		return false, 0, g.callError("validate", err)
+	}
+
+	if !response.Valid {
+		return false, 0, nil
+	}
+	return true, min(max(response.Confidence, 0), 100), nil
+}
+
+// HandleEvent passes a frontend event to the plugin and keeps the state it
+// returns on the challenge.
+func (g *RemoteGenerator) HandleEvent(ctx context.Context, challenge *entity.Challenge, eventType string, payload []byte) ([]byte, error) {
+	data, ok := challenge.Data.(entity.PluginData)
+	if !ok {
+		return nil, fmt.Errorf("неверный формат данных челленджа")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	response, err := g.plugin.client.HandleEvent(ctx, &pluginProto.HandleEventRequest{
+		ChallengeType: g.challengeType,
+		ChallengeId:   challenge.ID,
+		State:         data.State,
+		EventType:     eventType,
+		Data:          payload,
+	})
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			return nil, entity.ErrUnknownEvent
+		}
+		return nil, g.callError("handle event", err)
+	}
+
+	if len(response.State) > 0 {
+		data.State = response.State
+		challenge.Data = data
+	}
+	return response.Reply, nil
+}
+
+// callError reports a plugin that is down or too slow as unavailable, so the
+// transports can tell it apart from a broken answer.
+func (g *RemoteGenerator) callError(call string, err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return fmt.Errorf("%w: plugin %s failed to %s: %v", entity.ErrGeneratorUnavailable, g.plugin.name, call, err)
+	default:
+		return fmt.Errorf("plugin %s failed to %s: %w", g.plugin.name, call, err)
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"math"
+	"math/rand"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/imaging"
+)
+
+const (
+	// The disc is never rendered closer to upright than this, so leaving the
+	// slider untouched is never an answer.
+	minRotationOffset = 30
+)
+
+type RotationRenderer interface {
+	RenderRotation(rng *rand.Rand, puzzle imaging.RotationPuzzle) (*imaging.RotationImages, error)
+}
+
+// RotationGenerator asks the user to turn a circular crop of a background
+// upright. The slider position is the clockwise correction in degrees.
+type RotationGenerator struct {
+	config         *config.CaptchaConfig
+	difficulty     *DifficultyProfiles
+	repo           ChallengeRepository
+	templateEngine TemplateEngine
+	renderer       RotationRenderer
+	distortion     imaging.DistortionProfile
+
+	challengeSource
+}
+
+func NewRotationGenerator(config *config.CaptchaConfig, difficulty *DifficultyProfiles, repo ChallengeRepository, templateEngine TemplateEngine, renderer RotationRenderer) *RotationGenerator {
+	return &RotationGenerator{
+		config:         config,
+		difficulty:     difficulty,
+		repo:           repo,
+		templateEngine: templateEngine,
+		renderer:       renderer,
+		distortion:     distortionProfile(entity.ChallengeTypeRotation, config.DistortionRotation),
+	}
+}
+
+func (g *RotationGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	if complexity < 0 || complexity > 100 {
+		complexity = g.config.ComplexityMedium
+	}
+
+	params := g.difficulty.At(entity.ChallengeTypeRotation, complexity)
+	seed, err := g.newSeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	angle := minRotationOffset + rng.Intn(360-2*minRotationOffset+1)
+
+	// Smaller discs show less of the picture, which makes the upright
+	// orientation harder to judge.
+	diameter := params.Int("diameter")
+
+	images, err := g.renderer.RenderRotation(rng, imaging.RotationPuzzle{
+		Angle:      float64(angle),
+		Diameter:   diameter,
+		Distortion: g.distortion.At(complexity),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render rotation: %w", err)
+	}
+
+	challengeID, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := g.templateEngine.Render("rotation", map[string]interface{}{
+		"Lang":        LocaleFromContext(ctx),
+		"Text":        localizedTexts(ctx),
+		"ChallengeID": challengeID,
+		"UserID":      userID,
+		"Diameter":    images.Diameter,
+		"DiscImage":   template.URL(images.Image),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render rotation template: %w", err)
+	}
+
+	now := g.now()
+	challenge := &entity.Challenge{
+		ID:         challengeID,
+		Type:       entity.ChallengeTypeRotation,
+		UserID:     userID,
+		Complexity: complexity,
+		Data: entity.RotationData{
+			Angle:          angle,
+			BackgroundSeed: images.BackgroundSeed,
+		},
+		HTML:               html,
+		ExpiresAt:          now.Add(params.Expiration()),
+		CreatedAt:          now,
+		Attempts:           0,
+		MaxAttempts:        g.config.MaxAttempts,
+		MinTime:            params.MinTimeMs(),
+		MaxTime:            params.MaxTimeMs(),
+		IsBlocked:          false,
+		BlockReason:        "",
+		TimeoutAttempts:    0,
+		MaxTimeoutAttempts: g.config.MaxTimeoutAttempts,
+		Seed:               g.recordedSeed(seed),
+	}
+
+	return challenge, nil
+}
+
+// Validate checks that the submitted correction turns the disc upright. The
+// streamed slider events are the rotation trajectory: when there are any, the
+// last one must agree with the submitted angle, so an answer cannot be posted
+// without actually turning the disc. Their humanness is scored by the service.
+func (g *RotationGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	answerMap, ok := answer.(map[string]interface{})
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат ответа")
+	}
+
+	answered, ok := answerMap["angle"].(float64)
+	if !ok {
+		return false, 0, fmt.Errorf("неверный угол ответа")
+	}
+
+	data, ok := challenge.Data.(entity.RotationData)
+	if !ok {
+		return false, 0, fmt.Errorf("неверный формат данных челленджа")
+	}
+
+	tolerance := g.difficulty.At(entity.ChallengeTypeRotation, challenge.Complexity).Float("tolerance_deg")
+
+	if last, moved := lastSliderPosition(challenge.Events); moved && angleDistance(float64(last), answered) > tolerance {
+		return false, 0, nil
+	}
+
+	distance := angleDistance(answered+float64(data.Angle), 0)
+	if distance > tolerance {
+		return false, 0, nil
+	}
+
+	distanceScore := 1 - distance/(tolerance+1)
+	timing := timingScore(challenge, g.now())
+	confidence := int32(math.Round(100 * (0.6*distanceScore + 0.4*timing)))
+
+	return true, confidence, nil
+}
+
+func lastSliderPosition(events []entity.BinaryEvent) (int32, bool) {
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type == entity.EventTypeSliderMoved {
+			return events[i].X, true
+		}
+	}
+	return 0, false
+}
+
+// angleDistance is the shortest way around the circle between two angles.
+func angleDistance(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 360)
+	return math.Min(d, 360-d)
+}
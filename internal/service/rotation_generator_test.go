@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotationValidateWrapsAngles(t *testing.T) {
+	// Tolerance is 10 degrees at complexity 50.
+	tests := []struct {
+		name     string
+		angle    int
+		answered float64
+		slider   *int32
+		valid    bool
+	}{
+		{name: "exact", angle: 355, answered: 5, valid: true},
+		{name: "over 360", angle: 355, answered: 12, valid: true},
+		{name: "below 0", angle: 355, answered: -4, valid: true},
+		{name: "full turns", angle: 355, answered: 5 + 720, valid: true},
+		{name: "near 0 from above", angle: 3, answered: 355, valid: true},
+		{name: "near 0 from below", angle: 3, answered: -9, valid: true},
+		{name: "out of tolerance past 360", angle: 355, answered: 17, valid: false},
+		{name: "out of tolerance below 0", angle: 3, answered: 343, valid: false},
+		{name: "slider across 0", angle: 355, answered: 5, slider: int32Ptr(359), valid: true},
+		{name: "slider far from answer", angle: 355, answered: 5, slider: int32Ptr(180), valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator, challenge := generateAt(t, entity.ChallengeTypeRotation, 50, 3*time.Second)
+			challenge.Data = entity.RotationData{Angle: tt.angle}
+			if tt.slider != nil {
+				challenge.Events = []entity.BinaryEvent{{Type: entity.EventTypeSliderMoved, X: *tt.slider}}
+			}
+
+			valid, confidence, err := generator.Validate(map[string]interface{}{"angle": tt.answered}, challenge)
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, valid)
+			if tt.valid {
+				assert.Positive(t, confidence)
+			}
+		})
+	}
+}
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
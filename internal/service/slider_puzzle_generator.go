@@ -3,31 +3,48 @@ package service
 import (
 	"context"
 	"fmt"
+	"html/template"
+	"math"
 	"math/rand"
 	"time"
 
 	"captcha-service/internal/config"
 	"captcha-service/internal/domain/dto"
 	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/imaging"
+)
+
+const (
+	minPuzzleSize = 24
 )
 
 type TemplateEngine interface {
 	Render(templateName string, data interface{}) (string, error)
 }
 
+type PuzzleRenderer interface {
+	RenderSliderPuzzle(rng *rand.Rand, puzzle imaging.SliderPuzzle) (*imaging.SliderPuzzleImages, error)
+}
+
 type SliderPuzzleGenerator struct {
 	config         *config.CaptchaConfig
+	difficulty     *DifficultyProfiles
 	repo           ChallengeRepository
 	templateEngine TemplateEngine
-	rand           *rand.Rand
+	renderer       PuzzleRenderer
+	distortion     imaging.DistortionProfile
+
+	challengeSource
 }
 
-func NewSliderPuzzleGenerator(config *config.CaptchaConfig, repo ChallengeRepository, templateEngine TemplateEngine) *SliderPuzzleGenerator {
+func NewSliderPuzzleGenerator(config *config.CaptchaConfig, difficulty *DifficultyProfiles, repo ChallengeRepository, templateEngine TemplateEngine, renderer PuzzleRenderer) *SliderPuzzleGenerator {
 	return &SliderPuzzleGenerator{
 		config:         config,
+		difficulty:     difficulty,
 		repo:           repo,
 		templateEngine: templateEngine,
-		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		renderer:       renderer,
+		distortion:     distortionProfile(entity.ChallengeTypeSliderPuzzle, config.DistortionSliderPuzzle),
 	}
 }
 
@@ -39,23 +56,52 @@ func (g *SliderPuzzleGenerator) Generate(ctx context.Context, complexity int32,
 	canvasWidth := entity.CanvasWidth
 	canvasHeight := entity.CanvasHeight
 
-	minSize := int(g.config.PuzzleSizeLow)
-	maxSize := int(g.config.PuzzleSizeHigh)
-
-	puzzleSize := minSize + int((int(complexity)-1)*(maxSize-minSize)/99)
-
-	if puzzleSize > canvasWidth {
-		puzzleSize = canvasWidth - 20
+	params := g.difficulty.At(entity.ChallengeTypeSliderPuzzle, complexity)
+	seed, err := g.newSeed(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if puzzleSize > canvasHeight-entity.PuzzleGapTop {
-		puzzleSize = canvasHeight - entity.PuzzleGapTop - 20
+	rng := rand.New(rand.NewSource(seed))
+	shape := imaging.RandomJigsawShape(rng, puzzleSize(params.Int("piece_size")))
+	side := shape.Bounds()
+
+	// The piece starts at x=0, so keep the hole at least one piece away from it.
+	targetX := side + rng.Intn(canvasWidth-2*side+1)
+	targetY := rng.Intn(canvasHeight - side + 1)
+
+	images, err := g.renderer.RenderSliderPuzzle(rng, imaging.SliderPuzzle{
+		X:          targetX,
+		Y:          targetY,
+		Shape:      shape,
+		Distortion: g.distortion.At(complexity),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render slider puzzle: %w", err)
 	}
 
-	targetX := g.rand.Intn(canvasWidth - puzzleSize)
-	targetY := g.rand.Intn(canvasHeight-puzzleSize-entity.PuzzleGapTop) + entity.PuzzleGapTop
+	challengeID, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
 
-	challengeID := fmt.Sprintf("slider_%d", time.Now().UnixNano())
+	html, err := g.templateEngine.Render("slider_puzzle", map[string]interface{}{
+		"Lang":            LocaleFromContext(ctx),
+		"Text":            localizedTexts(ctx),
+		"ChallengeID":     challengeID,
+		"UserID":          userID,
+		"CanvasWidth":     canvasWidth,
+		"CanvasHeight":    canvasHeight,
+		"PieceWidth":      images.PieceWidth,
+		"PieceHeight":     images.PieceHeight,
+		"SliderMax":       canvasWidth - images.PieceWidth,
+		"BackgroundImage": template.URL(images.Background),
+		"PieceImage":      template.URL(images.Piece),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render slider puzzle template: %w", err)
+	}
 
+	now := g.now()
 	challenge := &entity.Challenge{
 		ID:         challengeID,
 		Type:       entity.ChallengeTypeSliderPuzzle,
@@ -66,51 +112,88 @@ func (g *SliderPuzzleGenerator) Generate(ctx context.Context, complexity int32,
 				TargetX: targetX,
 				TargetY: targetY,
 			},
-			CanvasWidth:  entity.CanvasWidth,
-			CanvasHeight: entity.CanvasHeight,
+			CanvasWidth:    entity.CanvasWidth,
+			CanvasHeight:   entity.CanvasHeight,
+			BackgroundSeed: images.BackgroundSeed,
 		},
-		ExpiresAt:          time.Now().Add(time.Duration(g.config.ExpirationTimeMedium) * time.Second),
-		CreatedAt:          time.Now(),
+		HTML:               html,
+		ExpiresAt:          now.Add(params.Expiration()),
+		CreatedAt:          now,
 		Attempts:           0,
 		MaxAttempts:        g.config.MaxAttempts,
-		MinTime:            int64(g.config.MinTimeMs),
-		MaxTime:            int64(g.config.MaxTimeMs),
+		MinTime:            params.MinTimeMs(),
+		MaxTime:            params.MaxTimeMs(),
 		IsBlocked:          false,
 		BlockReason:        "",
 		TimeoutAttempts:    0,
 		MaxTimeoutAttempts: g.config.MaxTimeoutAttempts,
+		Seed:               g.recordedSeed(seed),
 	}
 
 	return challenge, nil
 }
 
-func (g *SliderPuzzleGenerator) Validate(answer interface{}, data interface{}) (bool, int32, error) {
+// puzzleSize keeps a piece size from the difficulty profile within what the
+// canvas can hold.
+func puzzleSize(size int) int {
+	if maxSize := entity.CanvasHeight / 3; size > maxSize {
+		size = maxSize
+	}
+	if size < minPuzzleSize {
+		size = minPuzzleSize
+	}
+	return size
+}
+
+// Validate checks the slider position against the target stored on the challenge.
+// The piece strip spans the whole canvas height, so only x is compared.
+func (g *SliderPuzzleGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
 	answerMap, ok := answer.(map[string]interface{})
 	if !ok {
 		return false, 0, fmt.Errorf("неверный формат ответа")
 	}
 
-	answeredX, xOk := answerMap["x"].(float64)
-	answeredY, yOk := answerMap["y"].(float64)
-	if !xOk || !yOk {
+	answeredX, ok := answerMap["x"].(float64)
+	if !ok {
 		return false, 0, fmt.Errorf("неверные координаты ответа")
 	}
 
-	_, ok = data.(entity.SliderPuzzleData)
+	data, ok := challenge.Data.(entity.SliderPuzzleData)
 	if !ok {
 		return false, 0, fmt.Errorf("неверный формат данных челленджа")
 	}
 
-	targetX := int(g.config.DefaultTargetX)
-	targetY := int(g.config.DefaultTargetY)
-	tolerance := int(g.config.DefaultTolerance)
-
-	diffX := int(answeredX) - targetX
-	diffY := int(answeredY) - targetY
+	tolerance := g.difficulty.At(entity.ChallengeTypeSliderPuzzle, challenge.Complexity).Float("tolerance_px")
+	distance := math.Abs(answeredX - float64(data.ChallengeData.TargetX))
+	if distance > tolerance {
+		return false, 0, nil
+	}
 
-	isValid := (diffX >= -tolerance && diffX <= tolerance) && (diffY >= -tolerance && diffY <= tolerance)
+	distanceScore := 1 - distance/(tolerance+1)
+	timing := timingScore(challenge, g.now())
+	confidence := int32(math.Round(100 * (0.6*distanceScore + 0.4*timing)))
 
-	confidence := g.config.DefaultConfidence
+	return true, confidence, nil
+}
 
-	return isValid, confidence, nil
+// timingScore is 1 when the challenge was solved within [MinTime, MaxTime] and
+// falls off proportionally outside of it: instant answers look automated, very
+// slow ones look like a relayed challenge.
+func timingScore(challenge *entity.Challenge, now time.Time) float64 {
+	start := challenge.CreatedAt
+	if challenge.StartTime != nil {
+		start = *challenge.StartTime
+	}
+	elapsed := float64(now.Sub(start).Milliseconds())
+
+	switch {
+	case elapsed <= 0:
+		return 0
+	case challenge.MinTime > 0 && elapsed < float64(challenge.MinTime):
+		return elapsed / float64(challenge.MinTime)
+	case challenge.MaxTime > 0 && elapsed > float64(challenge.MaxTime):
+		return float64(challenge.MaxTime) / elapsed
+	default:
+		return 1
+	}
 }
@@ -0,0 +1,526 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+)
+
+const (
+	steerObjectRadius = 10
+	steerMargin       = 20
+	steerMaxObstacles = 4
+
+	// Physics is expressed per second so the feel does not depend on the tick
+	// rate.
+	steerAcceleration = 600.0
+	steerMaxSpeed     = 180.0
+	steerDamping      = 0.05
+
+	// Input frame: seq (uint16), key bits (uint8), pointer x<<13|y (uint32).
+	steerInputFrameSize = 7
+	steerMaxInputMarks  = 1024
+
+	steerKeyUp      = 1 << 0
+	steerKeyDown    = 1 << 1
+	steerKeyLeft    = 1 << 2
+	steerKeyRight   = 1 << 3
+	steerKeyPointer = 1 << 4
+)
+
+// State frames sent to the client. A key frame carries the full scene, delta
+// frames only the movement since the previous frame, and the end frame the
+// outcome.
+const (
+	SteerFrameKey   = 0x01
+	SteerFrameDelta = 0x02
+	SteerFrameEnd   = 0x03
+
+	SteerOutcomeGoal    = 1
+	SteerOutcomeTimeout = 2
+)
+
+var errInvalidInputFrame = errors.New("invalid game input frame")
+
+type SteerGameGenerator struct {
+	config         *config.CaptchaConfig
+	difficulty     *DifficultyProfiles
+	repo           ChallengeRepository
+	templateEngine TemplateEngine
+
+	challengeSource
+
+	sessions   map[string]*SteerSession
+	sessionsMu sync.Mutex
+}
+
+func NewSteerGameGenerator(config *config.CaptchaConfig, difficulty *DifficultyProfiles, repo ChallengeRepository, templateEngine TemplateEngine) *SteerGameGenerator {
+	return &SteerGameGenerator{
+		config:         config,
+		difficulty:     difficulty,
+		repo:           repo,
+		templateEngine: templateEngine,
+		sessions:       make(map[string]*SteerSession),
+	}
+}
+
+func (g *SteerGameGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	if complexity < 0 || complexity > 100 {
+		complexity = g.config.ComplexityMedium
+	}
+
+	canvasWidth := entity.CanvasWidth
+	canvasHeight := entity.CanvasHeight
+
+	seed, err := g.newSeed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	// Harder games get a smaller goal and more obstacles in the way.
+	params := g.difficulty.At(entity.ChallengeTypeSteerGame, complexity)
+	goalRadius := steerObjectRadius + params.Int("goal_slack_px")
+	reach := steerMargin + goalRadius
+
+	data := entity.SteerGameData{
+		Start: entity.Position{
+			X: reach + rng.Intn(max(1, canvasWidth/4-reach)),
+			Y: reach + rng.Intn(max(1, canvasHeight-2*reach)),
+		},
+		Goal: entity.Position{
+			X: canvasWidth*3/4 + rng.Intn(max(1, canvasWidth/4-reach)),
+			Y: reach + rng.Intn(max(1, canvasHeight-2*reach)),
+		},
+		GoalRadius:   goalRadius,
+		ObjectRadius: steerObjectRadius,
+		CanvasWidth:  canvasWidth,
+		CanvasHeight: canvasHeight,
+	}
+
+	if rng.Intn(2) == 0 {
+		data.Start.X = canvasWidth - data.Start.X
+		data.Goal.X = canvasWidth - data.Goal.X
+	}
+
+	data.Obstacles = steerObstacles(rng, min(params.Int("obstacles"), steerMaxObstacles), canvasWidth, canvasHeight)
+
+	challengeID, err := g.newID()
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := g.templateEngine.Render("steer_game", map[string]interface{}{
+		"Lang":         LocaleFromContext(ctx),
+		"Text":         localizedTexts(ctx),
+		"ChallengeID":  challengeID,
+		"UserID":       userID,
+		"CanvasWidth":  canvasWidth,
+		"CanvasHeight": canvasHeight,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render steer game template: %w", err)
+	}
+
+	now := g.now()
+	challenge := &entity.Challenge{
+		ID:                 challengeID,
+		Type:               entity.ChallengeTypeSteerGame,
+		UserID:             userID,
+		Complexity:         complexity,
+		Data:               data,
+		HTML:               html,
+		ExpiresAt:          now.Add(params.Expiration()),
+		CreatedAt:          now,
+		Attempts:           0,
+		MaxAttempts:        g.config.MaxAttempts,
+		MinTime:            params.MinTimeMs(),
+		MaxTime:            params.MaxTimeMs(),
+		IsBlocked:          false,
+		BlockReason:        "",
+		TimeoutAttempts:    0,
+		MaxTimeoutAttempts: g.config.MaxTimeoutAttempts,
+		Seed:               g.recordedSeed(seed),
+	}
+
+	return challenge, nil
+}
+
+// steerObstacles places vertical bars in the middle half of the canvas, where
+// neither the start nor the goal can be.
+func steerObstacles(rng *rand.Rand, count, canvasWidth, canvasHeight int) []entity.Rect {
+	obstacles := make([]entity.Rect, 0, count)
+	if count == 0 {
+		return obstacles
+	}
+
+	band := canvasWidth / 2 / count
+	for i := 0; i < count; i++ {
+		width := 16 + rng.Intn(9)
+		height := canvasHeight/4 + rng.Intn(canvasHeight/3)
+		obstacles = append(obstacles, entity.Rect{
+			X:      canvasWidth/4 + i*band + rng.Intn(max(1, band-width)),
+			Y:      rng.Intn(max(1, canvasHeight-height)),
+			Width:  width,
+			Height: height,
+		})
+	}
+	return obstacles
+}
+
+// StartSession returns the simulation of the challenge, starting it on first
+// use so a reconnecting client resumes the same game.
+func (g *SteerGameGenerator) StartSession(challenge *entity.Challenge) (GameSession, error) {
+	data, ok := challenge.Data.(entity.SteerGameData)
+	if !ok {
+		return nil, fmt.Errorf("неверный формат данных челленджа")
+	}
+
+	g.sessionsMu.Lock()
+	defer g.sessionsMu.Unlock()
+
+	now := time.Now()
+	g.pruneSessions(now)
+
+	if session, exists := g.sessions[challenge.ID]; exists {
+		return session, nil
+	}
+
+	tickRate := max(1, int(g.config.GameTickRate))
+	session := &SteerSession{
+		data:     data,
+		tickRate: tickRate,
+		interval: time.Second / time.Duration(tickRate),
+		deadline: now.Add(time.Duration(g.config.GameTimeLimitSec) * time.Second),
+		x:        float64(data.Start.X),
+		y:        float64(data.Start.Y),
+	}
+	g.sessions[challenge.ID] = session
+
+	return session, nil
+}
+
+// pruneSessions drops games abandoned without being validated.
+func (g *SteerGameGenerator) pruneSessions(now time.Time) {
+	threshold := now.Add(-time.Duration(g.config.StaleThreshold) * time.Second)
+	for challengeID, session := range g.sessions {
+		if session.deadline.Before(threshold) {
+			delete(g.sessions, challengeID)
+		}
+	}
+}
+
+// Validate ignores the answer: the outcome is whatever the server-side
+// simulation decided. A finished session is consumed by its validation.
+//
+// The simulation cannot tell who steered. Pointer steering lets a script put
+// the pointer on the goal and wait, so a game steered by pointer is judged on
+// the pointer path like any other pointer answer; keyboard games are judged
+// on the rhythm of the key changes.
+func (g *SteerGameGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	g.sessionsMu.Lock()
+	session, exists := g.sessions[challenge.ID]
+	g.sessionsMu.Unlock()
+
+	if !exists {
+		return false, 0, fmt.Errorf("игра не начата")
+	}
+
+	outcome, finishedAt, inputMarks, pointerPath := session.result()
+	if outcome == 0 {
+		return false, 0, fmt.Errorf("игра ещё не завершена")
+	}
+
+	g.sessionsMu.Lock()
+	delete(g.sessions, challenge.ID)
+	g.sessionsMu.Unlock()
+
+	if outcome != SteerOutcomeGoal {
+		return false, 0, nil
+	}
+
+	input := inputScore(inputMarks)
+	if len(pointerPath) > 0 {
+		var human bool
+		if input, human = g.pointerScore(challenge, pointerPath); !human {
+			return false, 0, nil
+		}
+	}
+
+	timing := timingScore(challenge, finishedAt)
+	confidence := int32(math.Round(100 * (0.6*timing + 0.4*input)))
+
+	return true, confidence, nil
+}
+
+// pointerScore scores the path the pointer took during the game, with the
+// same thresholds the service applies to pointer answers.
+func (g *SteerGameGenerator) pointerScore(challenge *entity.Challenge, path []entity.BinaryEvent) (float64, bool) {
+	steered := *challenge
+	steered.Events = path
+
+	score := ScoreTrajectory(&steered)
+	if score.Points < minTrajectoryPoints {
+		return 0, !g.config.RequireTrajectory
+	}
+	return score.Total, score.Total*100 >= float64(g.config.MinTrajectoryScore)
+}
+
+// inputScore looks at the ticks at which the player changed the input. People
+// correct their course at irregular intervals; a replayed script tends to
+// either never change the input or change it like clockwork.
+func inputScore(marks []int) float64 {
+	if len(marks) < 3 {
+		return 0
+	}
+
+	intervals := make([]float64, 0, len(marks)-1)
+	for i := 1; i < len(marks); i++ {
+		intervals = append(intervals, float64(marks[i]-marks[i-1]))
+	}
+	return clamp01(variation(intervals) / humanSpeedVariation)
+}
+
+// SteerSession is the authoritative state of one game. Input only changes the
+// controls; the position moves exclusively in Step.
+type SteerSession struct {
+	mu sync.Mutex
+
+	data     entity.SteerGameData
+	tickRate int
+	interval time.Duration
+	deadline time.Time
+
+	tick   int
+	x, y   float64
+	vx, vy float64
+
+	keys     uint8
+	pointerX float64
+	pointerY float64
+	seq      uint16
+	hasInput bool
+	marks    []int
+	// path holds the pointer positions as they arrived, for scoring.
+	path []entity.BinaryEvent
+
+	sentX, sentY int
+	sentAck      uint16
+	sentAny      bool
+
+	outcome    uint8
+	finishedAt time.Time
+}
+
+func (s *SteerSession) TickInterval() time.Duration {
+	return s.interval
+}
+
+func (s *SteerSession) Input(frame []byte) error {
+	if len(frame) != steerInputFrameSize {
+		return errInvalidInputFrame
+	}
+
+	seq := binary.BigEndian.Uint16(frame[0:2])
+	keys := frame[2]
+	pointer := binary.BigEndian.Uint32(frame[3:7])
+	pointerX := float64(pointer >> 13 & 0x1FFF)
+	pointerY := float64(pointer & 0x1FFF)
+
+	if keys&steerKeyPointer != 0 && (pointerX > float64(s.data.CanvasWidth) || pointerY > float64(s.data.CanvasHeight)) {
+		return entity.ErrEventOutOfRange
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Sequence numbers wrap around, so compare them as a signed distance.
+	if s.hasInput && int16(seq-s.seq) <= 0 {
+		return entity.ErrEventOutOfOrder
+	}
+
+	changed := !s.hasInput || keys != s.keys ||
+		(keys&steerKeyPointer != 0 && (pointerX != s.pointerX || pointerY != s.pointerY))
+	if changed && len(s.marks) < steerMaxInputMarks {
+		s.marks = append(s.marks, s.tick)
+	}
+	if changed && keys&steerKeyPointer != 0 && len(s.path) < steerMaxInputMarks {
+		s.path = append(s.path, entity.BinaryEvent{
+			Type:      entity.EventTypeDragMoved,
+			X:         int32(pointerX),
+			Y:         int32(pointerY),
+			Timestamp: time.Now().UnixMilli(),
+		})
+	}
+
+	s.seq = seq
+	s.hasInput = true
+	s.keys = keys
+	s.pointerX = pointerX
+	s.pointerY = pointerY
+
+	return nil
+}
+
+func (s *SteerSession) Step() ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.outcome != 0 {
+		return s.endFrame(), true
+	}
+
+	s.advance()
+	s.tick++
+
+	now := time.Now()
+	if math.Hypot(s.x-float64(s.data.Goal.X), s.y-float64(s.data.Goal.Y)) <= float64(s.data.GoalRadius) {
+		s.outcome = SteerOutcomeGoal
+	} else if now.After(s.deadline) {
+		s.outcome = SteerOutcomeTimeout
+	}
+	if s.outcome != 0 {
+		s.finishedAt = now
+		return s.endFrame(), true
+	}
+
+	return s.stateFrame(), false
+}
+
+func (s *SteerSession) advance() {
+	dt := 1 / float64(s.tickRate)
+
+	var ax, ay float64
+	if s.keys&steerKeyPointer != 0 {
+		dx, dy := s.pointerX-s.x, s.pointerY-s.y
+		if d := math.Hypot(dx, dy); d > float64(s.data.ObjectRadius)/2 {
+			ax, ay = dx/d, dy/d
+		}
+	} else {
+		if s.keys&steerKeyUp != 0 {
+			ay--
+		}
+		if s.keys&steerKeyDown != 0 {
+			ay++
+		}
+		if s.keys&steerKeyLeft != 0 {
+			ax--
+		}
+		if s.keys&steerKeyRight != 0 {
+			ax++
+		}
+		if d := math.Hypot(ax, ay); d > 0 {
+			ax, ay = ax/d, ay/d
+		}
+	}
+
+	damping := math.Pow(steerDamping, dt)
+	s.vx = (s.vx + ax*steerAcceleration*dt) * damping
+	s.vy = (s.vy + ay*steerAcceleration*dt) * damping
+	if speed := math.Hypot(s.vx, s.vy); speed > steerMaxSpeed {
+		s.vx *= steerMaxSpeed / speed
+		s.vy *= steerMaxSpeed / speed
+	}
+
+	// Axes are resolved separately so the object slides along walls.
+	if x := s.x + s.vx*dt; s.collides(x, s.y) {
+		s.vx = 0
+	} else {
+		s.x = x
+	}
+	if y := s.y + s.vy*dt; s.collides(s.x, y) {
+		s.vy = 0
+	} else {
+		s.y = y
+	}
+}
+
+func (s *SteerSession) collides(x, y float64) bool {
+	r := float64(s.data.ObjectRadius)
+	if x-r < 0 || y-r < 0 || x+r > float64(s.data.CanvasWidth) || y+r > float64(s.data.CanvasHeight) {
+		return true
+	}
+
+	for _, o := range s.data.Obstacles {
+		nearestX := math.Max(float64(o.X), math.Min(x, float64(o.X+o.Width)))
+		nearestY := math.Max(float64(o.Y), math.Min(y, float64(o.Y+o.Height)))
+		if math.Hypot(x-nearestX, y-nearestY) < r {
+			return true
+		}
+	}
+	return false
+}
+
+// stateFrame encodes the position relative to the previous frame. A key frame
+// is sent first, once per second so a client that lost frames resyncs, and
+// whenever the movement does not fit a delta.
+func (s *SteerSession) stateFrame() []byte {
+	x, y := int(math.Round(s.x)), int(math.Round(s.y))
+	dx, dy := x-s.sentX, y-s.sentY
+
+	var frame []byte
+	switch {
+	case !s.sentAny || s.tick%s.tickRate == 0 || dx < math.MinInt8 || dx > math.MaxInt8 || dy < math.MinInt8 || dy > math.MaxInt8:
+		frame = s.keyFrame(x, y)
+	case dx == 0 && dy == 0 && s.seq == s.sentAck:
+		return nil
+	default:
+		frame = make([]byte, 7)
+		frame[0] = SteerFrameDelta
+		binary.BigEndian.PutUint16(frame[1:3], uint16(s.tick))
+		binary.BigEndian.PutUint16(frame[3:5], s.seq)
+		frame[5] = byte(int8(dx))
+		frame[6] = byte(int8(dy))
+	}
+
+	s.sentX, s.sentY = x, y
+	s.sentAck = s.seq
+	s.sentAny = true
+	return frame
+}
+
+func (s *SteerSession) keyFrame(x, y int) []byte {
+	frame := make([]byte, 16, 16+8*len(s.data.Obstacles))
+	frame[0] = SteerFrameKey
+	binary.BigEndian.PutUint16(frame[1:3], uint16(s.tick))
+	binary.BigEndian.PutUint16(frame[3:5], s.seq)
+	binary.BigEndian.PutUint16(frame[5:7], uint16(x))
+	binary.BigEndian.PutUint16(frame[7:9], uint16(y))
+	binary.BigEndian.PutUint16(frame[9:11], uint16(s.data.Goal.X))
+	binary.BigEndian.PutUint16(frame[11:13], uint16(s.data.Goal.Y))
+	frame[13] = byte(s.data.GoalRadius)
+	frame[14] = byte(s.data.ObjectRadius)
+	frame[15] = byte(len(s.data.Obstacles))
+
+	for _, o := range s.data.Obstacles {
+		frame = binary.BigEndian.AppendUint16(frame, uint16(o.X))
+		frame = binary.BigEndian.AppendUint16(frame, uint16(o.Y))
+		frame = binary.BigEndian.AppendUint16(frame, uint16(o.Width))
+		frame = binary.BigEndian.AppendUint16(frame, uint16(o.Height))
+	}
+	return frame
+}
+
+func (s *SteerSession) endFrame() []byte {
+	frame := make([]byte, 8)
+	frame[0] = SteerFrameEnd
+	binary.BigEndian.PutUint16(frame[1:3], uint16(s.tick))
+	frame[3] = s.outcome
+	binary.BigEndian.PutUint16(frame[4:6], uint16(math.Round(s.x)))
+	binary.BigEndian.PutUint16(frame[6:8], uint16(math.Round(s.y)))
+	return frame
+}
+
+func (s *SteerSession) result() (uint8, time.Time, []int, []entity.BinaryEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.outcome, s.finishedAt, append([]int(nil), s.marks...), append([]entity.BinaryEvent(nil), s.path...)
+}
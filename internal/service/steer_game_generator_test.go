@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSteerGame(t *testing.T, timeLimitSec int32) (*SteerGameGenerator, *entity.Challenge, GameSession) {
+	t.Helper()
+
+	difficulty, err := LoadDifficultyProfiles("../../difficulty.json")
+	require.NoError(t, err)
+	generator := NewSteerGameGenerator(&config.CaptchaConfig{
+		ComplexityMedium:   50,
+		MaxAttempts:        3,
+		GameTickRate:       50,
+		GameTimeLimitSec:   timeLimitSec,
+		MinTrajectoryScore: 40,
+		RequireTrajectory:  true,
+	}, difficulty, nil, template.NewTemplateEngineService("../../templates"))
+
+	// No obstacles at complexity 0, so the object can always reach the goal.
+	challenge, err := generator.Generate(context.Background(), 0, "user-1")
+	require.NoError(t, err)
+	session, err := generator.StartSession(challenge)
+	require.NoError(t, err)
+	return generator, challenge, session
+}
+
+func steerInput(seq uint16, keys uint8, x, y int) []byte {
+	frame := make([]byte, steerInputFrameSize)
+	binary.BigEndian.PutUint16(frame[0:2], seq)
+	frame[2] = keys
+	binary.BigEndian.PutUint32(frame[3:7], uint32(x)<<13|uint32(y))
+	return frame
+}
+
+func TestSteerSessionTimesOut(t *testing.T) {
+	generator, challenge, session := newSteerGame(t, 1)
+	require.Equal(t, 20*time.Millisecond, session.TickInterval())
+
+	started := time.Now()
+	ticker := time.NewTicker(session.TickInterval())
+	defer ticker.Stop()
+
+	var frame []byte
+	ticks := 0
+	for done := false; !done; ticks++ {
+		require.Less(t, time.Since(started), 3*time.Second, "the game ends at its time limit")
+		<-ticker.C
+		frame, done = session.Step()
+	}
+
+	assert.GreaterOrEqual(t, time.Since(started), time.Second)
+	assert.GreaterOrEqual(t, ticks, 45, "the game ticks until the limit")
+	require.Len(t, frame, 8)
+	assert.Equal(t, byte(SteerFrameEnd), frame[0])
+	assert.Equal(t, byte(SteerOutcomeTimeout), frame[3])
+
+	frame, done := session.Step()
+	assert.True(t, done, "a finished game stays finished")
+	assert.Equal(t, byte(SteerOutcomeTimeout), frame[3])
+
+	valid, _, err := generator.Validate(nil, challenge)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	_, _, err = generator.Validate(nil, challenge)
+	assert.Error(t, err, "the session is consumed by its validation")
+}
+
+func TestSteerSessionRejectsScriptedPointer(t *testing.T) {
+	generator, challenge, session := newSteerGame(t, 60)
+	goal := challenge.Data.(entity.SteerGameData).Goal
+
+	// A script presses the pointer on the goal once and lets the simulation
+	// drive the object there.
+	require.NoError(t, session.Input(steerInput(1, steerKeyPointer, goal.X, goal.Y)))
+
+	var frame []byte
+	for done, ticks := false, 0; !done; ticks++ {
+		require.Less(t, ticks, 10000)
+		frame, done = session.Step()
+	}
+	require.Equal(t, byte(SteerOutcomeGoal), frame[3], "the object reaches the goal")
+
+	valid, confidence, err := generator.Validate(nil, challenge)
+	require.NoError(t, err)
+	assert.False(t, valid, "a pointer path of one point is not human")
+	assert.Zero(t, confidence)
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"math"
+
+	"captcha-service/internal/domain/entity"
+)
+
+const (
+	minTrajectoryPoints = 5
+
+	// Thresholds below which a feature is considered machine-like.
+	humanSpeedVariation = 0.5
+	humanJitter         = 0.04
+	overshootPixels     = 2
+)
+
+type TrajectoryScore struct {
+	Points       int
+	DurationMs   int64
+	Duration     float64
+	Velocity     float64
+	Jitter       float64
+	Acceleration float64
+	Overshoot    float64
+	Total        float64
+}
+
+// ScoreTrajectory scores how human the recorded pointer path looks. Constant
+// speed, perfectly straight, instant movements are what simple automation
+// produces. The duration is judged against the time window of the challenge.
+func ScoreTrajectory(challenge *entity.Challenge) *TrajectoryScore {
+	points := dedupeTimestamps(challenge.Events)
+
+	score := &TrajectoryScore{Points: len(points)}
+	if len(points) < minTrajectoryPoints {
+		return score
+	}
+
+	first, last := points[0], points[len(points)-1]
+	score.DurationMs = last.Timestamp - first.Timestamp
+
+	score.Duration = durationScore(score.DurationMs, challenge.MinTime, challenge.MaxTime)
+	speeds := segmentSpeeds(points)
+	score.Velocity = clamp01(variation(speeds) / humanSpeedVariation)
+	score.Jitter = clamp01(pathJitter(points) / humanJitter)
+	score.Acceleration = accelerationScore(speeds)
+	score.Overshoot = overshootScore(points)
+
+	score.Total = 0.25*score.Duration +
+		0.25*score.Velocity +
+		0.2*score.Jitter +
+		0.2*score.Acceleration +
+		0.1*score.Overshoot
+
+	return score
+}
+
+// dedupeTimestamps keeps the latest position for events sharing a millisecond,
+// so speeds never divide by zero.
+func dedupeTimestamps(points []entity.BinaryEvent) []entity.BinaryEvent {
+	result := make([]entity.BinaryEvent, 0, len(points))
+	for _, p := range points {
+		if n := len(result); n > 0 && p.Timestamp <= result[n-1].Timestamp {
+			if p.Timestamp == result[n-1].Timestamp {
+				result[n-1] = p
+			}
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func durationScore(durationMs, minMs, maxMs int64) float64 {
+	switch {
+	case durationMs <= 0:
+		return 0
+	case minMs > 0 && durationMs < minMs:
+		return float64(durationMs) / float64(minMs)
+	case maxMs > 0 && durationMs > maxMs:
+		return float64(maxMs) / float64(durationMs)
+	default:
+		return 1
+	}
+}
+
+func segmentSpeeds(points []entity.BinaryEvent) []float64 {
+	speeds := make([]float64, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		dx := float64(points[i].X - points[i-1].X)
+		dy := float64(points[i].Y - points[i-1].Y)
+		dt := float64(points[i].Timestamp - points[i-1].Timestamp)
+		speeds = append(speeds, math.Hypot(dx, dy)/dt)
+	}
+	return speeds
+}
+
+// variation is the coefficient of variation; a scripted drag at constant
+// speed has none, while a human hand speeds up and slows down.
+func variation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	variance /= float64(len(values))
+
+	return math.Sqrt(variance) / mean
+}
+
+// pathJitter measures how far the path strays from a straight line travelled
+// at constant speed between the first and the last point, relative to the
+// distance covered.
+func pathJitter(points []entity.BinaryEvent) float64 {
+	first, last := points[0], points[len(points)-1]
+	distance := math.Hypot(float64(last.X-first.X), float64(last.Y-first.Y))
+	duration := float64(last.Timestamp - first.Timestamp)
+	if distance == 0 || duration == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, p := range points {
+		k := float64(p.Timestamp-first.Timestamp) / duration
+		expectedX := float64(first.X) + k*float64(last.X-first.X)
+		expectedY := float64(first.Y) + k*float64(last.Y-first.Y)
+		dx := float64(p.X) - expectedX
+		dy := float64(p.Y) - expectedY
+		sum += dx*dx + dy*dy
+	}
+
+	return math.Sqrt(sum/float64(len(points))) / distance
+}
+
+// accelerationScore rewards the bell-shaped velocity profile of a human
+// movement: mostly accelerating in the first half, decelerating in the second.
+func accelerationScore(speeds []float64) float64 {
+	if len(speeds) < 4 {
+		return 0
+	}
+
+	var peak float64
+	for _, s := range speeds {
+		peak = math.Max(peak, s)
+	}
+	epsilon := peak * 0.05
+
+	half := len(speeds) / 2
+	var accelerating, decelerating, firstHalf, secondHalf int
+	for i := 1; i < len(speeds); i++ {
+		delta := speeds[i] - speeds[i-1]
+		if i <= half {
+			firstHalf++
+			if delta > epsilon {
+				accelerating++
+			}
+		} else {
+			secondHalf++
+			if delta < -epsilon {
+				decelerating++
+			}
+		}
+	}
+
+	if firstHalf == 0 || secondHalf == 0 {
+		return 0
+	}
+
+	score := float64(accelerating)/float64(firstHalf) + float64(decelerating)/float64(secondHalf)
+	return clamp01(score)
+}
+
+// overshootScore is 1 when the pointer went past its final position and came
+// back. People do that often, scripts almost never; its absence alone is not
+// suspicious, so it starts at 0.5.
+func overshootScore(points []entity.BinaryEvent) float64 {
+	first, last := points[0], points[len(points)-1]
+	dirX := float64(last.X - first.X)
+	dirY := float64(last.Y - first.Y)
+	length := math.Hypot(dirX, dirY)
+	if length == 0 {
+		return 0.5
+	}
+
+	final := length
+	for _, p := range points {
+		projection := (float64(p.X-first.X)*dirX + float64(p.Y-first.Y)*dirY) / length
+		if projection > final+overshootPixels {
+			return 1
+		}
+	}
+	return 0.5
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
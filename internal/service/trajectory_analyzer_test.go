@@ -0,0 +1,106 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func trajectoryChallenge(events []entity.BinaryEvent) *entity.Challenge {
+	return &entity.Challenge{ID: "challenge-1", MinTime: 500, MaxTime: 10000, Events: events}
+}
+
+// straightDrag moves at constant speed along a straight line, as a script does.
+func straightDrag(points int) []entity.BinaryEvent {
+	events := make([]entity.BinaryEvent, points)
+	for i := range events {
+		events[i] = entity.BinaryEvent{Type: entity.EventTypeSliderMoved, X: int32(10 + 10*i), Y: 100, Timestamp: 1000 + 20*int64(i)}
+	}
+	return events
+}
+
+// humanDrag follows a minimum-jerk profile, wobbles off the line and
+// overshoots the end before settling.
+func humanDrag() []entity.BinaryEvent {
+	const points = 40
+	events := make([]entity.BinaryEvent, 0, points+3)
+	for i := 0; i < points; i++ {
+		t := float64(i) / float64(points-1)
+		progress := 10*t*t*t - 15*t*t*t*t + 6*t*t*t*t*t
+		events = append(events, entity.BinaryEvent{
+			Type:      entity.EventTypeSliderMoved,
+			X:         int32(10 + math.Round(300*progress)),
+			Y:         int32(100 + math.Round(6*math.Sin(7*t))),
+			Timestamp: 1000 + int64(math.Round(1200*t)),
+		})
+	}
+	for i, x := range []int32{318, 314, 310} {
+		events = append(events, entity.BinaryEvent{Type: entity.EventTypeSliderMoved, X: x, Y: 100, Timestamp: 2300 + 60*int64(i)})
+	}
+	return events
+}
+
+func TestScoreTrajectoryTellsScriptFromHand(t *testing.T) {
+	threshold := 0.4
+
+	scripted := ScoreTrajectory(trajectoryChallenge(straightDrag(30)))
+	assert.Equal(t, 30, scripted.Points)
+	assert.InDelta(t, 0, scripted.Velocity, 1e-9, "constant speed")
+	assert.InDelta(t, 0, scripted.Jitter, 1e-9, "straight line")
+	assert.Less(t, scripted.Total, threshold)
+
+	human := ScoreTrajectory(trajectoryChallenge(humanDrag()))
+	assert.Greater(t, human.Velocity, 0.5)
+	assert.Greater(t, human.Acceleration, 0.5)
+	assert.Equal(t, 1.0, human.Overshoot)
+	assert.Equal(t, 1.0, human.Duration)
+	assert.Greater(t, human.Total, threshold)
+}
+
+func TestScoreTrajectoryWithoutEnoughPoints(t *testing.T) {
+	assert.Equal(t, &TrajectoryScore{}, ScoreTrajectory(trajectoryChallenge(nil)))
+
+	score := ScoreTrajectory(trajectoryChallenge(humanDrag()[:minTrajectoryPoints-1]))
+	assert.Equal(t, minTrajectoryPoints-1, score.Points)
+	assert.Zero(t, score.Total)
+}
+
+func TestApplyTrajectoryRequiresRecordedPath(t *testing.T) {
+	cfg := &config.CaptchaConfig{MinTrajectoryScore: 40, RequireTrajectory: true}
+	svc := &CaptchaService{config: cfg}
+
+	valid, confidence := svc.applyTrajectory(trajectoryChallenge(nil), 90)
+	assert.False(t, valid, "no events")
+	assert.Zero(t, confidence)
+
+	valid, _ = svc.applyTrajectory(trajectoryChallenge(humanDrag()[:minTrajectoryPoints-1]), 90)
+	assert.False(t, valid, "too few points")
+
+	valid, _ = svc.applyTrajectory(trajectoryChallenge(straightDrag(30)), 90)
+	assert.False(t, valid, "scripted path")
+
+	valid, confidence = svc.applyTrajectory(trajectoryChallenge(humanDrag()), 90)
+	assert.True(t, valid)
+	assert.Greater(t, confidence, int32(45))
+
+	cfg.RequireTrajectory = false
+	valid, confidence = svc.applyTrajectory(trajectoryChallenge(nil), 90)
+	assert.True(t, valid, "an optional trajectory only halves the confidence")
+	assert.Equal(t, int32(45), confidence)
+}
+
+func TestClickOrderRejectsAnswerWithoutRecordedClicks(t *testing.T) {
+	generator := NewClickOrderGenerator(&config.CaptchaConfig{}, nil, nil, nil, nil)
+	challenge := &entity.Challenge{
+		Type: entity.ChallengeTypeClickOrder,
+		Data: entity.ClickOrderData{Targets: []entity.ClickTarget{{X: 50, Y: 50, Radius: 20}, {X: 150, Y: 80, Radius: 20}, {X: 250, Y: 120, Radius: 20}}},
+	}
+
+	valid, _, err := generator.Validate(map[string]interface{}{"clicks": 3}, challenge)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
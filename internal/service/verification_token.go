@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// A verification token proves to a customer backend that a user solved a
+// challenge: a valid answer gets one, the browser passes it on with the form
+// and the backend redeems it through VerifyToken. The token is the base64url
+// JSON of its claims and their HMAC-SHA256, joined by a dot. It is bound to
+// the challenge, the user and the site, expires quickly and is accepted once.
+
+type TokenClaims struct {
+	ID          string `json:"jti"`
+	ChallengeID string `json:"cid"`
+	UserID      string `json:"uid"`
+	Site        string `json:"site,omitempty"`
+	IssuedAt    int64  `json:"iat"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+// TokenStore remembers redeemed tokens, at least until they expire.
+type TokenStore interface {
+	// Redeem marks a token as used and reports whether it was unused.
+	Redeem(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error)
+}
+
+type TokenIssuer struct {
+	secret []byte
+	ttl    time.Duration
+	store  TokenStore
+	clock  Clock
+}
+
+// NewTokenIssuer signs tokens with secret. Instances that verify each
+// other's tokens need the same secret and a shared store, or a token could be
+// redeemed once on every instance.
+func NewTokenIssuer(secret []byte, ttl time.Duration, store TokenStore) *TokenIssuer {
+	return &TokenIssuer{
+		secret: secret,
+		ttl:    ttl,
+		store:  store,
+		clock:  systemClock{},
+	}
+}
+
+func (t *TokenIssuer) SetClock(clock Clock) {
+	t.clock = clock
+}
+
+// Issue mints a token for a challenge that has just been solved.
+func (t *TokenIssuer) Issue(challenge *entity.Challenge) (string, error) {
+	now := t.clock.Now()
+	payload, err := json.Marshal(TokenClaims{
+		ID:          uuid.NewString(),
+		ChallengeID: challenge.ID,
+		UserID:      challenge.UserID,
+		Site:        challenge.Site,
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   now.Add(t.ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode token claims: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(t.sign(encoded)), nil
+}
+
+// Verify checks a token and redeems it. A token is only accepted for the site
+// it is bound to, and one issued without a site only by a verifier that names
+// none: the site comes from the browser, so a missing one proves nothing.
+func (t *TokenIssuer) Verify(ctx context.Context, token, site string) (*TokenClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, entity.ErrTokenInvalid
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, t.sign(encoded)) {
+		return nil, entity.ErrTokenInvalid
+	}
+
+	claims, err := decodeClaims(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if !t.clock.Now().Before(expiresAt) {
+		return nil, entity.ErrTokenExpired
+	}
+	if claims.Site != normalizeSite(site) {
+		return nil, entity.ErrTokenSiteMismatch
+	}
+
+	unused, err := t.store.Redeem(ctx, claims.ID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !unused {
+		return nil, entity.ErrTokenUsed
+	}
+
+	return claims, nil
+}
+
+func (t *TokenIssuer) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// TokenChallengeID reads which challenge a token was issued for, without
+// checking it, so that it can be routed to the instance that issued it.
+func TokenChallengeID(token string) (string, error) {
+	encoded, _, _ := strings.Cut(token, ".")
+	claims, err := decodeClaims(encoded)
+	if err != nil {
+		return "", err
+	}
+	return claims.ChallengeID, nil
+}
+
+func decodeClaims(encoded string) (*TokenClaims, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, entity.ErrTokenInvalid
+	}
+
+	var claims TokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" {
+		return nil, entity.ErrTokenInvalid
+	}
+	return &claims, nil
+}
+
+type siteKey struct{}
+
+// WithSite binds the challenges created with ctx, and so their tokens, to
+// the site they are shown on.
+func WithSite(ctx context.Context, site string) context.Context {
+	return context.WithValue(ctx, siteKey{}, normalizeSite(site))
+}
+
+func SiteFromContext(ctx context.Context) string {
+	site, _ := ctx.Value(siteKey{}).(string)
+	return site
+}
+
+// normalizeSite makes sites compare like host names do.
+func normalizeSite(site string) string {
+	return strings.ToLower(strings.TrimSpace(site))
+}
+
+// TokenErrorCode names why a token was rejected, as reported to customer
+// backends; ok is false for errors that say nothing about the token.
+func TokenErrorCode(err error) (code string, ok bool) {
+	switch {
+	case errors.Is(err, entity.ErrTokenInvalid):
+		return "invalid_token", true
+	case errors.Is(err, entity.ErrTokenExpired):
+		return "token_expired", true
+	case errors.Is(err, entity.ErrTokenUsed):
+		return "token_used", true
+	case errors.Is(err, entity.ErrTokenSiteMismatch):
+		return "site_mismatch", true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryTokens struct {
+	mu   sync.Mutex
+	used map[string]bool
+}
+
+func (m *memoryTokens) Redeem(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.used[tokenID] {
+		return false, nil
+	}
+	m.used[tokenID] = true
+	return true, nil
+}
+
+type movableClock struct{ now time.Time }
+
+func (c *movableClock) Now() time.Time { return c.now }
+
+func newTestTokenIssuer(secret string) (*TokenIssuer, *movableClock) {
+	clock := &movableClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	issuer := NewTokenIssuer([]byte(secret), 2*time.Minute, &memoryTokens{used: make(map[string]bool)})
+	issuer.SetClock(clock)
+	return issuer, clock
+}
+
+func TestVerificationTokenRedeemedOnce(t *testing.T) {
+	issuer, _ := newTestTokenIssuer("secret")
+	ctx := context.Background()
+
+	token, err := issuer.Issue(&entity.Challenge{ID: "challenge-1", UserID: "user-1", Site: "shop.example"})
+	require.NoError(t, err)
+
+	challengeID, err := TokenChallengeID(token)
+	require.NoError(t, err)
+	assert.Equal(t, "challenge-1", challengeID)
+
+	claims, err := issuer.Verify(ctx, token, "Shop.Example")
+	require.NoError(t, err)
+	assert.Equal(t, "challenge-1", claims.ChallengeID)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, "shop.example", claims.Site)
+
+	_, err = issuer.Verify(ctx, token, "shop.example")
+	assert.ErrorIs(t, err, entity.ErrTokenUsed)
+}
+
+func TestVerificationTokenRejected(t *testing.T) {
+	challenge := &entity.Challenge{ID: "challenge-1", UserID: "user-1", Site: "shop.example"}
+
+	cases := map[string]struct {
+		verify func(t *testing.T, issuer *TokenIssuer, clock *movableClock, token string) error
+		want   error
+	}{
+		"expired": {
+			verify: func(t *testing.T, issuer *TokenIssuer, clock *movableClock, token string) error {
+				clock.now = clock.now.Add(2 * time.Minute)
+				_, err := issuer.Verify(context.Background(), token, "shop.example")
+				return err
+			},
+			want: entity.ErrTokenExpired,
+		},
+		"other site": {
+			verify: func(t *testing.T, issuer *TokenIssuer, clock *movableClock, token string) error {
+				_, err := issuer.Verify(context.Background(), token, "evil.example")
+				return err
+			},
+			want: entity.ErrTokenSiteMismatch,
+		},
+		"other secret": {
+			verify: func(t *testing.T, issuer *TokenIssuer, clock *movableClock, token string) error {
+				other, _ := newTestTokenIssuer("another secret")
+				_, err := other.Verify(context.Background(), token, "shop.example")
+				return err
+			},
+			want: entity.ErrTokenInvalid,
+		},
+		"changed claims": {
+			verify: func(t *testing.T, issuer *TokenIssuer, clock *movableClock, token string) error {
+				forged, err := issuer.Issue(&entity.Challenge{ID: "challenge-2", UserID: "user-2", Site: "shop.example"})
+				require.NoError(t, err)
+				payload, _, _ := strings.Cut(forged, ".")
+				_, signature, _ := strings.Cut(token, ".")
+				_, err = issuer.Verify(context.Background(), payload+"."+signature, "shop.example")
+				return err
+			},
+			want: entity.ErrTokenInvalid,
+		},
+		"garbage": {
+			verify: func(t *testing.T, issuer *TokenIssuer, clock *movableClock, token string) error {
+				_, err := issuer.Verify(context.Background(), "not a token", "shop.example")
+				return err
+			},
+			want: entity.ErrTokenInvalid,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			issuer, clock := newTestTokenIssuer("secret")
+			token, err := issuer.Issue(challenge)
+			require.NoError(t, err)
+
+			assert.ErrorIs(t, c.verify(t, issuer, clock, token), c.want)
+		})
+	}
+}
+
+func TestVerificationTokenWithoutSite(t *testing.T) {
+	issuer, _ := newTestTokenIssuer("secret")
+
+	token, err := issuer.Issue(&entity.Challenge{ID: "challenge-1", UserID: "user-1"})
+	require.NoError(t, err)
+
+	_, err = issuer.Verify(context.Background(), token, "any.example")
+	assert.ErrorIs(t, err, entity.ErrTokenSiteMismatch, "a verifier that names a site needs a token bound to it")
+
+	_, err = issuer.Verify(context.Background(), token, "")
+	assert.NoError(t, err)
+}
@@ -64,20 +64,25 @@ func (h *Handlers) RegisterInstance(stream protoBalancer.BalancerService_Registe
 func (h *Handlers) CheckUserBlocked(ctx context.Context, req *protoBalancer.CheckUserBlockedRequest) (*protoBalancer.CheckUserBlockedResponse, error) {
 	log.Printf("Checking if user is blocked: %s", req.UserId)
 
-	return &protoBalancer.CheckUserBlockedResponse{
-		IsBlocked:    false,
-		Reason:       "",
-		BlockedUntil: 0,
-	}, nil
+	return h.balancerService.CheckUserBlocked(ctx, req)
 }
 
 func (h *Handlers) BlockUser(ctx context.Context, req *protoBalancer.BlockUserRequest) (*protoBalancer.BlockUserResponse, error) {
 	log.Printf("Blocking user: %s for %d minutes", req.UserId, req.DurationMinutes)
 
-	return &protoBalancer.BlockUserResponse{
-		Status:  protoBalancer.BlockUserResponse_SUCCESS,
-		Message: "User blocked successfully",
-	}, nil
+	return h.balancerService.BlockUserGRPC(ctx, req)
+}
+
+func (h *Handlers) UnblockUser(ctx context.Context, req *protoBalancer.UnblockUserRequest) (*protoBalancer.UnblockUserResponse, error) {
+	log.Printf("Unblocking user: %s", req.UserId)
+
+	return h.balancerService.UnblockUserGRPC(ctx, req)
+}
+
+func (h *Handlers) ListBlockedUsers(ctx context.Context, req *protoBalancer.ListBlockedUsersRequest) (*protoBalancer.ListBlockedUsersResponse, error) {
+	log.Printf("Listing blocked users")
+
+	return h.balancerService.ListBlockedUsersGRPC(ctx, req)
 }
 
 func (h *Handlers) GetInstances(ctx context.Context, req *protoBalancer.GetInstancesRequest) (*protoBalancer.GetInstancesResponse, error) {
@@ -1,9 +1,13 @@
 package grpc
 
 import (
-	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"log"
+	"strconv"
+	"sync"
+	"time"
 
 	captchaProto "captcha-service/gen/proto/captcha"
 	"captcha-service/internal/domain/entity"
@@ -15,18 +19,38 @@ import (
 
 type EventStreamHandler struct {
 	captchaService *service.CaptchaService
+
+	// games holds the challenges whose tick loop is running on some stream.
+	games   map[string]struct{}
+	gamesMu sync.Mutex
 }
 
 func NewEventStreamHandler(captchaService *service.CaptchaService) *EventStreamHandler {
 	return &EventStreamHandler{
 		captchaService: captchaService,
+		games:          make(map[string]struct{}),
 	}
 }
 
+// serializedStream lets game loops send frames while the receive loop answers
+// other events; a gRPC stream does not allow concurrent Send calls.
+type serializedStream struct {
+	captchaProto.CaptchaService_MakeEventStreamServer
+	mu sync.Mutex
+}
+
+func (s *serializedStream) Send(event *captchaProto.ServerEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.CaptchaService_MakeEventStreamServer.Send(event)
+}
+
 func (h *EventStreamHandler) MakeEventStream(stream captchaProto.CaptchaService_MakeEventStreamServer) error {
 	log.Println("Event stream started")
 	defer log.Println("Event stream ended")
 
+	stream = &serializedStream{CaptchaService_MakeEventStreamServer: stream}
+
 	for {
 		clientEvent, err := stream.Recv()
 		if err != nil {
@@ -73,17 +97,124 @@ func (h *EventStreamHandler) handleFrontendEvent(stream captchaProto.CaptchaServ
 	switch eventType {
 	case entity.EventTypeSliderMove:
 		return h.handleSliderMove(stream, event, eventData)
+	case entity.EventTypeEventFrameStr, entity.EventTypeSliderMovedStr, entity.EventTypeClickEventStr, entity.EventTypeDragMovedStr:
+		return h.handleInteraction(stream, event, eventData)
 	case entity.EventTypeValidation:
 		return h.handleValidation(stream, event, eventData)
+	case entity.EventTypeGameStart:
+		return h.handleGameStart(stream, event)
+	case entity.EventTypeGameInput:
+		return h.handleGameInput(stream, event, eventData)
 	default:
+		return h.handleChallengeEvent(stream, event, eventType, eventData)
+	}
+}
+
+// handleChallengeEvent hands events the stream does not know to the generator
+// of the challenge, which is how plugins receive their own events.
+func (h *EventStreamHandler) handleChallengeEvent(stream captchaProto.CaptchaService_MakeEventStreamServer, event *captchaProto.ClientEvent, eventType string, eventData map[string]interface{}) error {
+	data, err := json.Marshal(eventData["data"])
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid event data")
+	}
+
+	reply, err := h.captchaService.HandleChallengeEvent(stream.Context(), event.ChallengeId, eventType, data)
+	if errors.Is(err, entity.ErrUnknownEvent) {
 		log.Printf("Unknown frontend event type: %s", eventType)
 		return nil
 	}
+	if service.IsChallengeGone(err) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		log.Printf("Failed to handle %s for challenge %s: %v", eventType, event.ChallengeId, err)
+		return nil
+	}
+
+	return sendClientData(stream, event.ChallengeId, reply)
+}
+
+func (h *EventStreamHandler) handleInteraction(stream captchaProto.CaptchaService_MakeEventStreamServer, event *captchaProto.ClientEvent, eventData map[string]interface{}) error {
+	data, ok := packedEventBytes(eventData["data"])
+	if !ok {
+		log.Printf("Invalid packed event for challenge %s", event.ChallengeId)
+		return nil
+	}
+
+	return h.recordInteraction(stream, event.ChallengeId, data)
+}
+
+// recordInteraction records a frame of pointer events and pushes back the
+// change the challenge made in response, if any.
+func (h *EventStreamHandler) recordInteraction(stream captchaProto.CaptchaService_MakeEventStreamServer, challengeID string, data []byte) error {
+	delta, err := h.captchaService.RecordInteraction(stream.Context(), challengeID, data)
+	if err != nil {
+		log.Printf("Failed to record interaction for challenge %s: %v", challengeID, err)
+		return nil
+	}
+
+	return sendClientData(stream, challengeID, delta)
+}
+
+// sendClientData passes data to the challenge page; empty data is not sent.
+func sendClientData(stream captchaProto.CaptchaService_MakeEventStreamServer, challengeID string, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return stream.Send(&captchaProto.ServerEvent{
+		Event: &captchaProto.ServerEvent_ClientData{
+			ClientData: &captchaProto.ServerEvent_SendClientData{
+				ChallengeId: challengeID,
+				Data:        data,
+			},
+		},
+	})
+}
+
+// packedEventBytes accepts the forms a Uint8Array takes after crossing
+// postMessage and JSON: a base64 string, an array of numbers or an object
+// keyed by index.
+func packedEventBytes(value interface{}) ([]byte, bool) {
+	switch v := value.(type) {
+	case string:
+		data, err := base64.StdEncoding.DecodeString(v)
+		return data, err == nil
+	case []interface{}:
+		data := make([]byte, len(v))
+		for i, item := range v {
+			b, ok := item.(float64)
+			if !ok {
+				return nil, false
+			}
+			data[i] = byte(b)
+		}
+		return data, true
+	case map[string]interface{}:
+		data := make([]byte, len(v))
+		for key, item := range v {
+			i, err := strconv.Atoi(key)
+			b, ok := item.(float64)
+			if err != nil || !ok || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			data[i] = byte(b)
+		}
+		return data, true
+	default:
+		return nil, false
+	}
 }
 
 func (h *EventStreamHandler) handleSliderMove(stream captchaProto.CaptchaService_MakeEventStreamServer, event *captchaProto.ClientEvent, eventData map[string]interface{}) error {
 	log.Printf("Slider move for challenge %s: %+v", event.ChallengeId, eventData["data"])
 
+	if data, ok := packedEventBytes(eventData["data"]); ok {
+		if err := h.recordInteraction(stream, event.ChallengeId, data); err != nil {
+			return err
+		}
+	}
+
 	response := &captchaProto.ServerEvent{
 		Event: &captchaProto.ServerEvent_ClientData{
 			ClientData: &captchaProto.ServerEvent_SendClientData{
@@ -102,17 +233,38 @@ func (h *EventStreamHandler) handleValidation(stream captchaProto.CaptchaService
 		return status.Errorf(codes.InvalidArgument, "invalid answer data")
 	}
 
-	valid, confidence, err := h.captchaService.ValidateChallenge(context.Background(), event.ChallengeId, answerData)
+	verdict, err := h.captchaService.Validate(stream.Context(), event.ChallengeId, answerData)
+	if service.IsAttemptRejected(err) {
+		// An answer during the cooldown or after the last attempt is an
+		// ordinary outcome; the stream stays open for the next one.
+		return h.sendRejection(stream, event.ChallengeId, err)
+	}
+	if service.IsChallengeGone(err) {
+		return status.Error(codes.NotFound, err.Error())
+	}
 	if err != nil {
 		log.Printf("Error validating challenge: %v", err)
 		return status.Errorf(codes.Internal, "validation error")
 	}
 
+	return h.sendValidationResult(stream, event.ChallengeId, verdict)
+}
+
+func (h *EventStreamHandler) sendValidationResult(stream captchaProto.CaptchaService_MakeEventStreamServer, challengeID string, verdict service.Verdict) error {
+	return h.sendResult(stream, challengeID, verdict, nil)
+}
+
+// sendRejection reports an answer that was turned away without being judged.
+func (h *EventStreamHandler) sendRejection(stream captchaProto.CaptchaService_MakeEventStreamServer, challengeID string, rejection error) error {
+	return h.sendResult(stream, challengeID, service.Verdict{}, rejection)
+}
+
+func (h *EventStreamHandler) sendResult(stream captchaProto.CaptchaService_MakeEventStreamServer, challengeID string, verdict service.Verdict, rejection error) error {
 	response := &captchaProto.ServerEvent{
 		Event: &captchaProto.ServerEvent_Result{
 			Result: &captchaProto.ServerEvent_ChallengeResult{
-				ChallengeId:       event.ChallengeId,
-				ConfidencePercent: int32(confidence),
+				ChallengeId:       challengeID,
+				ConfidencePercent: verdict.Confidence,
 			},
 		},
 	}
@@ -122,17 +274,24 @@ func (h *EventStreamHandler) handleValidation(stream captchaProto.CaptchaService
 	}
 
 	clientData := map[string]interface{}{
-		"valid":      valid,
-		"confidence": confidence,
+		"valid":      verdict.Valid,
+		"confidence": verdict.Confidence,
 		"message":    entity.EventTypeValidationComplete,
 	}
+	if verdict.Token != "" {
+		clientData["token"] = verdict.Token
+	}
+	if rejection != nil {
+		clientData["error"] = rejection.Error()
+		clientData["blocked"] = true
+	}
 
 	clientDataBytes, _ := json.Marshal(clientData)
 
 	clientResponse := &captchaProto.ServerEvent{
 		Event: &captchaProto.ServerEvent_ClientData{
 			ClientData: &captchaProto.ServerEvent_SendClientData{
-				ChallengeId: event.ChallengeId,
+				ChallengeId: challengeID,
 				Data:        clientDataBytes,
 			},
 		},
@@ -141,6 +300,106 @@ func (h *EventStreamHandler) handleValidation(stream captchaProto.CaptchaService
 	return stream.Send(clientResponse)
 }
 
+// handleGameStart starts ticking the challenge's simulation on this stream.
+// A repeated start, e.g. after the client reconnected, does not start a
+// second loop.
+func (h *EventStreamHandler) handleGameStart(stream captchaProto.CaptchaService_MakeEventStreamServer, event *captchaProto.ClientEvent) error {
+	session, err := h.captchaService.GameSession(stream.Context(), event.ChallengeId)
+	if err != nil {
+		log.Printf("Failed to start game for challenge %s: %v", event.ChallengeId, err)
+		return nil
+	}
+
+	h.gamesMu.Lock()
+	_, running := h.games[event.ChallengeId]
+	if !running {
+		h.games[event.ChallengeId] = struct{}{}
+	}
+	h.gamesMu.Unlock()
+
+	if !running {
+		go h.runGame(stream, event.ChallengeId, session)
+	}
+	return nil
+}
+
+func (h *EventStreamHandler) handleGameInput(stream captchaProto.CaptchaService_MakeEventStreamServer, event *captchaProto.ClientEvent, eventData map[string]interface{}) error {
+	data, ok := packedEventBytes(eventData["data"])
+	if !ok {
+		log.Printf("Invalid game input for challenge %s", event.ChallengeId)
+		return nil
+	}
+
+	session, err := h.captchaService.GameSession(stream.Context(), event.ChallengeId)
+	if err != nil {
+		log.Printf("No game for challenge %s: %v", event.ChallengeId, err)
+		return nil
+	}
+
+	if err := session.Input(data); err != nil {
+		log.Printf("Rejected game input for challenge %s: %v", event.ChallengeId, err)
+	}
+	return nil
+}
+
+// runGame ticks the simulation at its fixed rate and streams the state frames
+// until the game resolves or the stream goes away.
+func (h *EventStreamHandler) runGame(stream captchaProto.CaptchaService_MakeEventStreamServer, challengeID string, session service.GameSession) {
+	defer func() {
+		h.gamesMu.Lock()
+		delete(h.games, challengeID)
+		h.gamesMu.Unlock()
+	}()
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(session.TickInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		frame, done := session.Step()
+		if frame != nil {
+			err := stream.Send(&captchaProto.ServerEvent{
+				Event: &captchaProto.ServerEvent_ClientData{
+					ClientData: &captchaProto.ServerEvent_SendClientData{
+						ChallengeId: challengeID,
+						Data:        frame,
+					},
+				},
+			})
+			if err != nil {
+				log.Printf("Failed to send game frame for challenge %s: %v", challengeID, err)
+				return
+			}
+		}
+
+		if !done {
+			continue
+		}
+
+		verdict, err := h.captchaService.Validate(ctx, challengeID, nil)
+		if service.IsAttemptRejected(err) {
+			if err := h.sendRejection(stream, challengeID, err); err != nil {
+				log.Printf("Failed to send game result for challenge %s: %v", challengeID, err)
+			}
+			return
+		}
+		if err != nil {
+			log.Printf("Error validating game for challenge %s: %v", challengeID, err)
+			return
+		}
+		if err := h.sendValidationResult(stream, challengeID, verdict); err != nil {
+			log.Printf("Failed to send game result for challenge %s: %v", challengeID, err)
+		}
+		return
+	}
+}
+
 func (h *EventStreamHandler) handleBalancerEvent(stream captchaProto.CaptchaService_MakeEventStreamServer, event *captchaProto.ClientEvent) error {
 	log.Printf("Balancer event for challenge %s: %+v", event.ChallengeId, event.Data)
 
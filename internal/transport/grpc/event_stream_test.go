@@ -0,0 +1,115 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	captchaProto "captcha-service/gen/proto/captcha"
+	"captcha-service/internal/domain/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// codeGenerator accepts the code "right", typed rather than dragged.
+type codeGenerator struct{}
+
+func (codeGenerator) Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error) {
+	return nil, entity.ErrUnsupportedChallengeType
+}
+
+func (codeGenerator) Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error) {
+	code, _ := answer.(map[string]interface{})["code"].(string)
+	if code == "right" {
+		return true, 100, nil
+	}
+	return false, 0, nil
+}
+
+func (codeGenerator) TypedAnswer() {}
+
+type recordedStream struct {
+	captchaProto.CaptchaService_MakeEventStreamServer
+	incoming []*captchaProto.ClientEvent
+	sent     []*captchaProto.ServerEvent
+}
+
+func (s *recordedStream) Context() context.Context { return context.Background() }
+
+func (s *recordedStream) Recv() (*captchaProto.ClientEvent, error) {
+	if len(s.incoming) == 0 {
+		return nil, io.EOF
+	}
+	event := s.incoming[0]
+	s.incoming = s.incoming[1:]
+	return event, nil
+}
+
+func (s *recordedStream) Send(event *captchaProto.ServerEvent) error {
+	s.sent = append(s.sent, event)
+	return nil
+}
+
+func answerEvent(t *testing.T, code string) *captchaProto.ClientEvent {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		entity.EventTypeFieldEventType: entity.EventTypeValidation,
+		"data":                         map[string]interface{}{"code": code},
+	})
+	require.NoError(t, err)
+	return &captchaProto.ClientEvent{EventType: captchaProto.ClientEvent_FRONTEND_EVENT, ChallengeId: "challenge-1", Data: data}
+}
+
+func TestEventStreamReportsRejectedAnswers(t *testing.T) {
+	svc, repo := newCodeService(t)
+	require.NoError(t, repo.SaveChallenge(context.Background(), &entity.Challenge{
+		ID: "challenge-1", UserID: "user-1", Type: "code", MaxAttempts: 1,
+		CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute),
+	}))
+
+	stream := &recordedStream{incoming: []*captchaProto.ClientEvent{
+		answerEvent(t, "wrong"),
+		answerEvent(t, "right"),
+		answerEvent(t, "right"),
+	}}
+	handler := NewEventStreamHandler(svc)
+
+	assert.ErrorIs(t, handler.MakeEventStream(stream), io.EOF, "the stream stays open until the client leaves")
+
+	var replies []map[string]interface{}
+	for _, event := range stream.sent {
+		if data := event.GetClientData(); data != nil {
+			var reply map[string]interface{}
+			require.NoError(t, json.Unmarshal(data.Data, &reply))
+			replies = append(replies, reply)
+		}
+	}
+	require.Len(t, replies, 3)
+	assert.Equal(t, false, replies[0]["valid"])
+	assert.Nil(t, replies[0]["error"], "a judged answer")
+	for _, reply := range replies[1:] {
+		assert.Equal(t, false, reply["valid"])
+		assert.Equal(t, true, reply["blocked"])
+		assert.Equal(t, entity.ErrMaxAttemptsReached.Error(), reply["error"])
+	}
+}
+
+func TestEventStreamEndsForConsumedChallenge(t *testing.T) {
+	svc, repo := newCodeService(t)
+	require.NoError(t, repo.SaveChallenge(context.Background(), &entity.Challenge{
+		ID: "challenge-1", UserID: "user-1", Type: "code", MaxAttempts: 3,
+		CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute),
+	}))
+
+	stream := &recordedStream{incoming: []*captchaProto.ClientEvent{
+		answerEvent(t, "right"),
+		answerEvent(t, "right"),
+	}}
+	err := NewEventStreamHandler(svc).MakeEventStream(stream)
+	assert.Equal(t, codes.NotFound, status.Code(err), "an answer to a consumed challenge ends the stream")
+}
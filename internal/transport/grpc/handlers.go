@@ -3,7 +3,7 @@ package grpc
 import (
 	"context"
 	"encoding/json"
-	"strconv"
+	"errors"
 
 	captchav1 "captcha-service/gen/proto/captcha"
 	"captcha-service/internal/domain/entity"
@@ -11,6 +11,8 @@ import (
 	"captcha-service/pkg/logger"
 
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Handlers struct {
@@ -27,37 +29,27 @@ func NewHandlers(captchaService *service.CaptchaService) *Handlers {
 }
 
 func (h *Handlers) NewChallenge(ctx context.Context, req *captchav1.ChallengeRequest) (*captchav1.ChallengeResponse, error) {
-	challenge, err := h.captchaService.CreateChallenge(ctx, entity.ChallengeTypeSliderPuzzle, req.Complexity, req.UserId)
+	ctx = service.WithLocale(ctx, req.Locale)
+	ctx = service.WithSite(ctx, req.Site)
+
+	challenge, err := h.captchaService.CreateChallenge(ctx, req.ChallengeType, req.Complexity, req.UserId)
 	if err != nil {
+		if errors.Is(err, entity.ErrUnsupportedChallengeType) {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported challenge type %q", req.ChallengeType)
+		}
+		if errors.Is(err, entity.ErrGeneratorUnavailable) {
+			return nil, status.Errorf(codes.Unavailable, "challenge type %q is unavailable", req.ChallengeType)
+		}
 		logger.Error("Failed to create challenge", zap.Error(err))
 		return nil, err
 	}
 
-	html := h.generateChallengeHTML(challenge)
-	if html == "" {
-		logger.Error("Failed to generate HTML for challenge")
-		return nil, err
-	}
-
 	return &captchav1.ChallengeResponse{
 		ChallengeId: challenge.ID,
-		Html:        html,
+		Html:        challenge.HTML,
 	}, nil
 }
 
-func (h *Handlers) generateChallengeHTML(challenge *entity.Challenge) string {
-	return `<div class="captcha-container">
-		<h3>Slider Puzzle Captcha</h3>
-		<p>Challenge ID: ` + challenge.ID + `</p>
-		<p>Complexity: ` + strconv.Itoa(int(challenge.Complexity)) + `</p>
-		<div class="slider-area">
-			<input type="range" id="xSlider" min="0" max="380" value="0">
-			<input type="range" id="ySlider" min="0" max="180" value="0">
-		</div>
-		<button id="validateBtn">Validate</button>
-	</div>`
-}
-
 func (h *Handlers) ValidateChallenge(ctx context.Context, req *captchav1.ValidateRequest) (*captchav1.ValidateResponse, error) {
 	var answer interface{}
 	if err := json.Unmarshal([]byte(req.Answer), &answer); err != nil {
@@ -65,15 +57,47 @@ func (h *Handlers) ValidateChallenge(ctx context.Context, req *captchav1.Validat
 		return nil, err
 	}
 
-	valid, confidence, err := h.captchaService.ValidateChallenge(ctx, req.ChallengeId, answer)
+	verdict, err := h.captchaService.Validate(ctx, req.ChallengeId, answer)
+	if service.IsAttemptRejected(err) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if service.IsChallengeGone(err) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
 	if err != nil {
 		logger.Error("Failed to validate challenge", zap.Error(err))
 		return nil, err
 	}
 
 	return &captchav1.ValidateResponse{
-		Valid:      valid,
-		Confidence: confidence,
+		Valid:      verdict.Valid,
+		Confidence: verdict.Confidence,
+		Token:      verdict.Token,
+	}, nil
+}
+
+func (h *Handlers) VerifyToken(ctx context.Context, req *captchav1.VerifyTokenRequest) (*captchav1.VerifyTokenResponse, error) {
+	claims, err := h.captchaService.VerifyToken(ctx, req.Token, req.Site)
+	if err != nil {
+		if code, ok := service.TokenErrorCode(err); ok {
+			return &captchav1.VerifyTokenResponse{Error: code}, nil
+		}
+		logger.Error("Failed to verify token", zap.Error(err))
+		return nil, status.Errorf(codes.Unavailable, "failed to verify token")
+	}
+
+	return &captchav1.VerifyTokenResponse{
+		Success:     true,
+		ChallengeId: claims.ChallengeID,
+		UserId:      claims.UserID,
+		Site:        claims.Site,
+		IssuedAt:    claims.IssuedAt,
+	}, nil
+}
+
+func (h *Handlers) ListChallengeTypes(ctx context.Context, req *captchav1.ListChallengeTypesRequest) (*captchav1.ListChallengeTypesResponse, error) {
+	return &captchav1.ListChallengeTypesResponse{
+		ChallengeTypes: h.captchaService.ListChallengeTypes(),
 	}, nil
 }
 
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	captchaProto "captcha-service/gen/proto/captcha"
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/event_processing"
+	"captcha-service/internal/infrastructure/persistence"
+	"captcha-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newCodeService serves codeGenerator challenges from a memory store.
+func newCodeService(t *testing.T) (*service.CaptchaService, *persistence.MemoryOptimizedRepository) {
+	t.Helper()
+
+	cfg := &config.CaptchaConfig{MaxAttempts: 3, BlockDurationMin: 5, EventHistorySize: 16, MaxChallenges: 10}
+	repo := persistence.NewMemoryOptimizedRepository(10)
+	t.Cleanup(repo.Stop)
+	events := event_processing.NewEventProcessorService(cfg)
+	t.Cleanup(events.Stop)
+
+	registry := service.NewGeneratorRegistry()
+	registry.Register("code", codeGenerator{})
+	return service.NewCaptchaService(repo, registry, cfg, events), repo
+}
+
+func TestValidateChallengeGone(t *testing.T) {
+	svc, repo := newCodeService(t)
+	handlers := NewHandlers(svc)
+	ctx := context.Background()
+
+	require.NoError(t, repo.SaveChallenge(ctx, &entity.Challenge{
+		ID: "solved", UserID: "user-1", Type: "code", MaxAttempts: 3,
+		CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute),
+	}))
+	require.NoError(t, repo.SaveChallenge(ctx, &entity.Challenge{
+		ID: "expiring", UserID: "user-1", Type: "code", MaxAttempts: 3,
+		CreatedAt: time.Now(), ExpiresAt: time.Now().Add(20 * time.Millisecond),
+	}))
+
+	response, err := handlers.ValidateChallenge(ctx, &captchaProto.ValidateRequest{ChallengeId: "solved", Answer: `{"code":"right"}`})
+	require.NoError(t, err)
+	require.True(t, response.Valid)
+
+	_, err = handlers.ValidateChallenge(ctx, &captchaProto.ValidateRequest{ChallengeId: "solved", Answer: `{"code":"right"}`})
+	assert.Equal(t, codes.NotFound, status.Code(err), "a consumed challenge")
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = handlers.ValidateChallenge(ctx, &captchaProto.ValidateRequest{ChallengeId: "expiring", Answer: `{"code":"right"}`})
+	assert.Equal(t, codes.NotFound, status.Code(err), "an expired challenge")
+
+	_, err = handlers.ValidateChallenge(ctx, &captchaProto.ValidateRequest{ChallengeId: "unknown", Answer: `{"code":"right"}`})
+	assert.Equal(t, codes.NotFound, status.Code(err), "a challenge that never existed")
+}
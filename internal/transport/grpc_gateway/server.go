@@ -35,6 +35,8 @@ func (s *Server) Start() error {
 	// Добавляем HTTP маршруты напрямую
 	router.HandleFunc("/api/challenge", s.httpHandlers.HandleChallengeRequest).Methods("POST")
 	router.HandleFunc("/api/validate", s.httpHandlers.HandleValidateRequest).Methods("POST")
+	router.HandleFunc("/api/siteverify", s.httpHandlers.HandleSiteVerify).Methods("POST")
+	router.HandleFunc("/api/challenge-types", s.httpHandlers.HandleChallengeTypes).Methods("GET")
 	router.HandleFunc("/ws", s.httpHandlers.HandleWebSocket)
 	router.HandleFunc("/health", s.httpHandlers.HandleHealthCheck).Methods("GET")
 	router.HandleFunc("/memory", s.httpHandlers.HandleMemoryStats).Methods("GET")
@@ -11,7 +11,6 @@ import (
 	"html/template"
 	"log"
 	"net/http"
-	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -22,20 +21,29 @@ import (
 	protoBalancer "captcha-service/gen/proto/proto/balancer"
 	"captcha-service/internal/config"
 	"captcha-service/internal/domain/entity"
+	templateInfra "captcha-service/internal/infrastructure/template"
 	"captcha-service/internal/service"
 
 	"github.com/gorilla/websocket"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 func isUserBlockedError(err error) bool {
 	return errors.Is(err, entity.ErrUserBlocked)
 }
 
+type captchaInstance struct {
+	id            string
+	address       string
+	challengeType string
+	client        captchaProto.CaptchaServiceClient
+}
+
 type BalancerProxy struct {
-	captchaClients []captchaProto.CaptchaServiceClient
-	serviceAddrs   []string
+	instances      []*captchaInstance
 	balancerClient protoBalancer.BalancerServiceClient
 	mu             sync.RWMutex
 	roundRobin     int
@@ -44,18 +52,25 @@ type BalancerProxy struct {
 	sessions       map[string]*entity.UserSession
 	sessionMu      sync.RWMutex
 	globalBlocker  *service.GlobalUserBlocker
+
+	// sharedStore is set when the instances keep challenges in one store, so
+	// any instance of the issuer's type can answer for a gone issuer.
+	sharedStore bool
+	issuerTypes map[string]string
+
+	websocketOnce   sync.Once
+	websocketScript *template.Template
+	websocketErr    error
 }
 
 func NewBalancerProxy(config *config.ServiceConfig) *BalancerProxy {
-	captchaClients := make([]captchaProto.CaptchaServiceClient, 0)
-	serviceAddrs := make([]string, 0)
 	sessions := make(map[string]*entity.UserSession)
 
 	return &BalancerProxy{
-		captchaClients: captchaClients,
-		serviceAddrs:   serviceAddrs,
-		roundRobin:     0,
-		sessions:       sessions,
+		instances:   make([]*captchaInstance, 0),
+		roundRobin:  0,
+		sessions:    sessions,
+		issuerTypes: make(map[string]string),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true
@@ -66,6 +81,14 @@ func NewBalancerProxy(config *config.ServiceConfig) *BalancerProxy {
 	}
 }
 
+// SetSharedStore tells the proxy whether the instances share a challenge
+// store (CHALLENGE_STORE=redis).
+func (bp *BalancerProxy) SetSharedStore(shared bool) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.sharedStore = shared
+}
+
 func (bp *BalancerProxy) ConnectToBalancer(balancerAddr string) error {
 	conn, err := grpc.Dial(balancerAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -76,20 +99,29 @@ func (bp *BalancerProxy) ConnectToBalancer(balancerAddr string) error {
 	return nil
 }
 
-func (bp *BalancerProxy) AddCaptchaService(addr string) error {
+// AddCaptchaService connects to an instance. Instances added by hand have no
+// balancer-assigned ID, so their address is used instead.
+func (bp *BalancerProxy) AddCaptchaService(instanceID, addr, challengeType string) error {
+	if instanceID == "" {
+		instanceID = addr
+	}
+
 	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		return fmt.Errorf("failed to connect to captcha service %s: %w", addr, err)
 	}
 
-	client := captchaProto.NewCaptchaServiceClient(conn)
-
 	bp.mu.Lock()
-	bp.captchaClients = append(bp.captchaClients, client)
-	bp.serviceAddrs = append(bp.serviceAddrs, addr)
+	bp.instances = append(bp.instances, &captchaInstance{
+		id:            instanceID,
+		address:       addr,
+		challengeType: challengeType,
+		client:        captchaProto.NewCaptchaServiceClient(conn),
+	})
+	bp.issuerTypes[instanceID] = challengeType
 	bp.mu.Unlock()
 
-	log.Printf("Added captcha service: %s", addr)
+	log.Printf("Added captcha service: %s at %s (%s)", instanceID, addr, challengeType)
 	return nil
 }
 
@@ -97,10 +129,9 @@ func (bp *BalancerProxy) RemoveCaptchaService(addr string) error {
 	bp.mu.Lock()
 	defer bp.mu.Unlock()
 
-	for i, serviceAddr := range bp.serviceAddrs {
-		if serviceAddr == addr {
-			bp.captchaClients = append(bp.captchaClients[:i], bp.captchaClients[i+1:]...)
-			bp.serviceAddrs = append(bp.serviceAddrs[:i], bp.serviceAddrs[i+1:]...)
+	for i, instance := range bp.instances {
+		if instance.address == addr {
+			bp.instances = append(bp.instances[:i], bp.instances[i+1:]...)
 
 			log.Printf("Removed captcha service: %s", addr)
 			return nil
@@ -110,17 +141,38 @@ func (bp *BalancerProxy) RemoveCaptchaService(addr string) error {
 	return fmt.Errorf("service not found: %s", addr)
 }
 
-func (bp *BalancerProxy) GetNextClient() captchaProto.CaptchaServiceClient {
-	bp.mu.RLock()
-	defer bp.mu.RUnlock()
+// GetNextClient round-robins over the instances serving challengeType; an
+// empty challengeType matches every instance.
+func (bp *BalancerProxy) GetNextClient(challengeType string) captchaProto.CaptchaServiceClient {
+	if instance := bp.nextInstance(challengeType); instance != nil {
+		return instance.client
+	}
+	return nil
+}
+
+func (bp *BalancerProxy) nextInstance(challengeType string) *captchaInstance {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
 
-	if len(bp.captchaClients) == 0 {
+	matching := make([]*captchaInstance, 0, len(bp.instances))
+	for _, instance := range bp.instances {
+		if challengeType == "" || instance.challengeType == challengeType {
+			matching = append(matching, instance)
+		}
+	}
+
+	// Every instance serves the audio alternative next to its own type.
+	if len(matching) == 0 && challengeType == entity.ChallengeTypeAudio {
+		matching = append(matching, bp.instances...)
+	}
+
+	if len(matching) == 0 {
 		return nil
 	}
 
-	client := bp.captchaClients[bp.roundRobin%len(bp.captchaClients)]
+	instance := matching[bp.roundRobin%len(matching)]
 	bp.roundRobin++
-	return client
+	return instance
 }
 
 func (bp *BalancerProxy) NewChallengeHandler(w http.ResponseWriter, r *http.Request) {
@@ -136,6 +188,10 @@ func (bp *BalancerProxy) NewChallengeHandler(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	challengeType := r.URL.Query().Get("type")
+	locale := r.URL.Query().Get("locale")
+	site := r.URL.Query().Get("site")
+
 	session := bp.getOrCreateSession(r)
 	userID := session.UserID
 	log.Printf("Using userID from session: %s", userID)
@@ -166,6 +222,7 @@ func (bp *BalancerProxy) NewChallengeHandler(w http.ResponseWriter, r *http.Requ
 			log.Printf("Failed to check user blocked status on balancer: %v", err)
 		} else if checkResp.IsBlocked {
 			log.Printf("User %s is blocked on balancer, showing blocked page", userID)
+			bp.globalBlocker.BlockUserUntil(userID, checkResp.Reason, time.UnixMilli(checkResp.BlockedAtMs), time.Unix(checkResp.BlockedUntil, 0))
 
 			displayUserID := userID
 			if displayUserID == "anonymous" {
@@ -186,8 +243,8 @@ func (bp *BalancerProxy) NewChallengeHandler(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	client := bp.GetNextClient()
-	if client == nil {
+	instance := bp.nextInstance(challengeType)
+	if instance == nil {
 		http.Error(w, "No captcha services available", http.StatusServiceUnavailable)
 		return
 	}
@@ -195,9 +252,12 @@ func (bp *BalancerProxy) NewChallengeHandler(w http.ResponseWriter, r *http.Requ
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	resp, err := client.NewChallenge(ctx, &captchaProto.ChallengeRequest{
-		Complexity: complexity,
-		UserId:     userID,
+	resp, err := instance.client.NewChallenge(ctx, &captchaProto.ChallengeRequest{
+		Complexity:    complexity,
+		UserId:        userID,
+		ChallengeType: challengeType,
+		Locale:        locale,
+		Site:          site,
 	})
 	if err != nil {
 		log.Printf("Failed to create challenge: %v", err)
@@ -250,6 +310,9 @@ func (bp *BalancerProxy) WebSocketHandler(w http.ResponseWriter, r *http.Request
 	responseChan := make(chan entity.WebSocketMessage, 100)
 	defer close(responseChan)
 
+	relay := newEventRelay(bp, responseChan)
+	defer relay.close()
+
 	go func() {
 		for response := range responseChan {
 			if err := conn.WriteJSON(response); err != nil {
@@ -285,7 +348,7 @@ func (bp *BalancerProxy) WebSocketHandler(w http.ResponseWriter, r *http.Request
 				continue
 			}
 
-			if msg.Type == "captcha_event" {
+			if msg.Type == "captcha_event" && msg.EventType == entity.EventTypeValidation {
 				if isNowBlocked := bp.incrementAttempts(msg.UserID); isNowBlocked {
 					log.Printf("User %s blocked after incrementing attempts", msg.UserID)
 
@@ -335,7 +398,23 @@ func (bp *BalancerProxy) WebSocketHandler(w http.ResponseWriter, r *http.Request
 			}
 
 		case "captcha_event":
-			log.Printf("Received captcha event from user %s", msg.UserID)
+			if err := relay.forward(msg); err != nil {
+				log.Printf("Failed to forward captcha event for challenge %s: %v", msg.ChallengeID, err)
+
+				status := http.StatusBadGateway
+				if errors.Is(err, errInstanceGone) {
+					status = http.StatusGone
+				}
+				responseChan <- entity.WebSocketMessage{
+					Type:        entity.MessageTypeError,
+					UserID:      msg.UserID,
+					ChallengeID: msg.ChallengeID,
+					Data: map[string]interface{}{
+						"error":  err.Error(),
+						"status": status,
+					},
+				}
+			}
 
 		default:
 			log.Printf("Unknown message type: %s", msg.Type)
@@ -350,9 +429,11 @@ func (bp *BalancerProxy) StartServiceDiscovery() {
 	defer ticker.Stop()
 
 	bp.discoverServices()
+	bp.SyncBlockedUsers()
 
 	for range ticker.C {
 		bp.discoverServices()
+		bp.SyncBlockedUsers()
 	}
 }
 
@@ -375,47 +456,61 @@ func (bp *BalancerProxy) discoverServices() {
 		address := fmt.Sprintf("%s:%d", instance.Host, instance.PortNumber)
 		currentServices[address] = true
 
-		bp.mu.RLock()
+		bp.mu.Lock()
 		exists := false
-		for _, addr := range bp.serviceAddrs {
-			if addr == address {
+		for _, known := range bp.instances {
+			if known.address == address {
+				if known.id != instance.InstanceId {
+					// Restarted on the same address: challenges issued by the
+					// previous process are gone with it.
+					log.Printf("Captcha service at %s is now %s (was %s)", address, instance.InstanceId, known.id)
+					known.id = instance.InstanceId
+				}
+				known.challengeType = instance.ChallengeType
 				exists = true
 				break
 			}
 		}
-		bp.mu.RUnlock()
+		bp.mu.Unlock()
 
 		if !exists {
 			log.Printf("Discovered new captcha service: %s", address)
-			if err := bp.AddCaptchaService(address); err != nil {
+			if err := bp.AddCaptchaService(instance.InstanceId, address, instance.ChallengeType); err != nil {
 				log.Printf("Failed to add discovered service %s: %v", address, err)
 			}
 		}
 	}
 
 	bp.mu.Lock()
-	var toRemove []int
-	for i, addr := range bp.serviceAddrs {
-		if !currentServices[addr] {
-			toRemove = append(toRemove, i)
+	active := bp.instances[:0]
+	for _, instance := range bp.instances {
+		if currentServices[instance.address] {
+			active = append(active, instance)
+		} else {
+			log.Printf("Removed stale captcha service: %s", instance.address)
 		}
 	}
-
-	for i := len(toRemove) - 1; i >= 0; i-- {
-		idx := toRemove[i]
-		addr := bp.serviceAddrs[idx]
-		bp.captchaClients = append(bp.captchaClients[:idx], bp.captchaClients[idx+1:]...)
-		bp.serviceAddrs = append(bp.serviceAddrs[:idx], bp.serviceAddrs[idx+1:]...)
-		log.Printf("Removed stale captcha service: %s", addr)
-	}
+	bp.instances = active
 	bp.mu.Unlock()
 
 }
 
+// websocketFragment is the bridge between challenge pages and the proxy
+// websocket, with the event codec it uses. It is bundled once, like the
+// challenge templates, and only executed per page.
+const websocketFragment = `<script src="event_codec.js"></script><script src="websocket.js"></script>`
+
+func (bp *BalancerProxy) websocketTemplate() (*template.Template, error) {
+	bp.websocketOnce.Do(func() {
+		bp.websocketScript, bp.websocketErr = templateInfra.CompileSource("websocket", "templates", websocketFragment)
+	})
+	return bp.websocketScript, bp.websocketErr
+}
+
 func (bp *BalancerProxy) addWebSocketCode(html, userID string) string {
-	tmpl, err := template.ParseFiles("templates/websocket.js")
+	tmpl, err := bp.websocketTemplate()
 	if err != nil {
-		log.Printf("Failed to parse websocket template: %v", err)
+		log.Printf("Failed to compile websocket script: %v", err)
 		return html
 	}
 
@@ -431,28 +526,12 @@ func (bp *BalancerProxy) addWebSocketCode(html, userID string) string {
 		return html
 	}
 
-	scriptCode := fmt.Sprintf("<script>\n%s\n</script>", websocketCode.String())
-
-	if len(html) > 0 {
-		lastBodyIndex := -1
-		for i := len(html) - 1; i >= 0; i-- {
-			if i+6 <= len(html) && html[i:i+6] == "</body>" {
-				lastBodyIndex = i
-				break
-			}
-		}
-
-		if lastBodyIndex != -1 {
-			return html[:lastBodyIndex] + scriptCode + html[lastBodyIndex:]
-		}
-	}
-
-	return html + scriptCode
+	return templateInfra.InsertBeforeBodyEnd(html, websocketCode.String())
 }
 
 func (bp *BalancerProxy) HealthHandler(w http.ResponseWriter, r *http.Request) {
 	bp.mu.RLock()
-	serviceCount := len(bp.captchaClients)
+	serviceCount := len(bp.instances)
 	bp.mu.RUnlock()
 
 	response := map[string]interface{}{
@@ -470,7 +549,7 @@ func (bp *BalancerProxy) MemoryStatsHandler(w http.ResponseWriter, r *http.Reque
 	runtime.ReadMemStats(&memStats)
 
 	bp.mu.RLock()
-	serviceCount := len(bp.captchaClients)
+	serviceCount := len(bp.instances)
 	sessionCount := len(bp.sessions)
 	bp.mu.RUnlock()
 
@@ -497,13 +576,15 @@ func (bp *BalancerProxy) MemoryStatsHandler(w http.ResponseWriter, r *http.Reque
 
 func (bp *BalancerProxy) StatsHandler(w http.ResponseWriter, r *http.Request) {
 	bp.mu.RLock()
-	serviceCount := len(bp.captchaClients)
+	serviceCount := len(bp.instances)
 	sessionCount := len(bp.sessions)
-	services := make([]map[string]interface{}, len(bp.serviceAddrs))
-	for i, addr := range bp.serviceAddrs {
+	services := make([]map[string]interface{}, len(bp.instances))
+	for i, instance := range bp.instances {
 		services[i] = map[string]interface{}{
-			"address": addr,
-			"status":  "active",
+			"id":                      instance.id,
+			"address":                 instance.address,
+			entity.FieldChallengeType: instance.challengeType,
+			"status":                  "active",
 		}
 	}
 	bp.mu.RUnlock()
@@ -568,7 +649,9 @@ func (bp *BalancerProxy) AddServiceHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	var req struct {
-		Address string `json:"address"`
+		InstanceID    string `json:"instance_id"`
+		Address       string `json:"address"`
+		ChallengeType string `json:"challenge_type"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
@@ -576,8 +659,8 @@ func (bp *BalancerProxy) AddServiceHandler(w http.ResponseWriter, r *http.Reques
 	}
 
 	bp.mu.RLock()
-	for _, addr := range bp.serviceAddrs {
-		if addr == req.Address {
+	for _, instance := range bp.instances {
+		if instance.address == req.Address {
 			http.Error(w, "Service already exists", http.StatusConflict)
 			bp.mu.RUnlock()
 			return
@@ -585,7 +668,7 @@ func (bp *BalancerProxy) AddServiceHandler(w http.ResponseWriter, r *http.Reques
 	}
 	bp.mu.RUnlock()
 
-	if err := bp.AddCaptchaService(req.Address); err != nil {
+	if err := bp.AddCaptchaService(req.InstanceID, req.Address, req.ChallengeType); err != nil {
 		http.Error(w, "Failed to add service", http.StatusInternalServerError)
 		return
 	}
@@ -624,8 +707,11 @@ func (bp *BalancerProxy) ChallengeHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	var req struct {
-		Complexity int    `json:"complexity"`
-		UserID     string `json:"user_id"`
+		Complexity    int    `json:"complexity"`
+		UserID        string `json:"user_id"`
+		ChallengeType string `json:"challenge_type"`
+		Locale        string `json:"locale"`
+		Site          string `json:"site"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -633,33 +719,21 @@ func (bp *BalancerProxy) ChallengeHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), entity.DefaultTimeoutSeconds*time.Second)
-	defer cancel()
-
-	instances, err := bp.balancerClient.GetInstances(ctx, &protoBalancer.GetInstancesRequest{})
-	if err != nil {
-		http.Error(w, "Failed to get instances: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	if len(instances.Instances) == 0 {
-		http.Error(w, "No instances available", http.StatusServiceUnavailable)
+	instance := bp.nextInstance(req.ChallengeType)
+	if instance == nil {
+		http.Error(w, "No instances available for challenge type "+req.ChallengeType, http.StatusServiceUnavailable)
 		return
 	}
 
-	instance := instances.Instances[0]
-	address := fmt.Sprintf("%s:%d", instance.Host, instance.PortNumber)
-	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		http.Error(w, "Failed to connect to captcha service: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer conn.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), entity.DefaultTimeoutSeconds*time.Second)
+	defer cancel()
 
-	client := captchaProto.NewCaptchaServiceClient(conn)
-	resp, err := client.NewChallenge(ctx, &captchaProto.ChallengeRequest{
-		Complexity: int32(req.Complexity),
-		UserId:     req.UserID,
+	resp, err := instance.client.NewChallenge(ctx, &captchaProto.ChallengeRequest{
+		Complexity:    int32(req.Complexity),
+		UserId:        req.UserID,
+		ChallengeType: req.ChallengeType,
+		Locale:        req.Locale,
+		Site:          req.Site,
 	})
 	if err != nil {
 		http.Error(w, "Failed to create challenge: "+err.Error(), http.StatusInternalServerError)
@@ -696,7 +770,11 @@ func (bp *BalancerProxy) ValidateChallengeHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	client := bp.GetNextClient()
+	client, err := bp.clientForChallenge(req.ChallengeID)
+	if errors.Is(err, errInstanceGone) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
 	if client == nil {
 		http.Error(w, "No captcha services available", http.StatusServiceUnavailable)
 		return
@@ -715,6 +793,14 @@ func (bp *BalancerProxy) ValidateChallengeHandler(w http.ResponseWriter, r *http
 		ChallengeId: req.ChallengeID,
 		Answer:      string(answerJSON),
 	})
+	if status.Code(err) == codes.ResourceExhausted {
+		http.Error(w, status.Convert(err).Message(), http.StatusTooManyRequests)
+		return
+	}
+	if status.Code(err) == codes.NotFound {
+		http.Error(w, status.Convert(err).Message(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		log.Printf("Failed to validate challenge: %v", err)
 		http.Error(w, "Failed to validate challenge: "+err.Error(), http.StatusInternalServerError)
@@ -725,11 +811,75 @@ func (bp *BalancerProxy) ValidateChallengeHandler(w http.ResponseWriter, r *http
 		"valid":      resp.Valid,
 		"confidence": resp.Confidence,
 	}
+	if resp.Token != "" {
+		response["token"] = resp.Token
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// SiteVerifyHandler redeems a verification token at the instance that
+// issued it, found by the challenge the token names.
+func (bp *BalancerProxy) SiteVerifyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Token string `json:"token"`
+		Site  string `json:"site"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	challengeID, err := service.TokenChallengeID(req.Token)
+	if err != nil {
+		code, _ := service.TokenErrorCode(err)
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": false, "error": code})
+		return
+	}
+
+	client, err := bp.clientForChallenge(challengeID)
+	if errors.Is(err, errInstanceGone) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+	if client == nil {
+		http.Error(w, "No captcha services available", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), entity.DefaultTimeoutSeconds*time.Second)
+	defer cancel()
+
+	resp, err := client.VerifyToken(ctx, &captchaProto.VerifyTokenRequest{
+		Token: req.Token,
+		Site:  req.Site,
+	})
+	if err != nil {
+		log.Printf("Failed to verify token: %v", err)
+		http.Error(w, "Failed to verify token", http.StatusServiceUnavailable)
+		return
+	}
+
+	response := map[string]interface{}{"success": resp.Success}
+	if resp.Success {
+		response[entity.FieldChallengeID] = resp.ChallengeId
+		response["user_id"] = resp.UserId
+		response["site"] = resp.Site
+		response["issued_at"] = resp.IssuedAt
+	} else {
+		response["error"] = resp.Error
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
 func (bp *BalancerProxy) BlockedPageHandler(w http.ResponseWriter, r *http.Request) {
 	duration := r.URL.Query().Get("duration")
 	userID := r.URL.Query().Get("user_id")
@@ -922,27 +1072,13 @@ func (bp *BalancerProxy) incrementAttempts(userID string) bool {
 		log.Printf("User %s attempt %d/%d", userID, session.Attempts, bp.config.MaxAttempts)
 
 		if int32(session.Attempts) > bp.config.MaxAttempts {
+			blockedAt := time.Now()
 			session.IsBlocked = true
-			session.BlockedUntil = time.Now().Add(time.Duration(bp.config.BlockDurationMin) * time.Minute)
+			session.BlockedUntil = blockedAt.Add(time.Duration(bp.config.BlockDurationMin) * time.Minute)
 			log.Printf("User %s blocked after %d attempts", userID, session.Attempts)
 
-			if bp.balancerClient != nil {
-				go func() {
-					ctx, cancel := context.WithTimeout(context.Background(), entity.DefaultTimeoutSeconds*time.Second)
-					defer cancel()
-
-					_, err := bp.balancerClient.BlockUser(ctx, &protoBalancer.BlockUserRequest{
-						UserId:          userID,
-						DurationMinutes: int32(bp.config.BlockDurationMin),
-						Reason:          "Too many failed attempts",
-					})
-					if err != nil {
-						log.Printf("Failed to block user on balancer: %v", err)
-					} else {
-						log.Printf("User %s blocked on balancer for %d minutes", userID, bp.config.BlockDurationMin)
-					}
-				}()
-			}
+			bp.globalBlocker.BlockUserUntil(userID, "Too many failed attempts", blockedAt, session.BlockedUntil)
+			go bp.shareBlock(userID, "Too many failed attempts", blockedAt, bp.config.BlockDurationMin)
 
 			return true
 		}
@@ -977,27 +1113,12 @@ func (bp *BalancerProxy) isUserBlockedInSession(userID string) (bool, string) {
 func SetupBalancerProxyRoutes(proxy *BalancerProxy, cfg *config.BalancerProxyConfig) *http.ServeMux {
 	mux := http.NewServeMux()
 
-	corsHandler := func(h http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-			h.ServeHTTP(w, r)
-		})
-	}
-
-	backgroundsPath, err := filepath.Abs(cfg.BackgroundsPath)
-	if err != nil {
-		log.Printf("Error resolving backgrounds path: %v", err)
-		backgroundsPath = cfg.BackgroundsPath
-	}
-
-	mux.Handle("/backgrounds/", corsHandler(http.StripPrefix("/backgrounds/", http.FileServer(http.Dir(backgroundsPath)))))
 	mux.HandleFunc("/ws", proxy.WebSocketHandler)
 
 	mux.HandleFunc("/challenge", proxy.ChallengeHandler)
 	mux.HandleFunc("/api/challenge", proxy.ChallengeHandler)
 	mux.HandleFunc("/api/validate", proxy.ValidateChallengeHandler)
+	mux.HandleFunc("/api/siteverify", proxy.SiteVerifyHandler)
 	mux.HandleFunc("/api/services/add", proxy.AddServiceHandler)
 	mux.HandleFunc("/api/services/remove", proxy.RemoveServiceHandler)
 	mux.HandleFunc("/api/health", proxy.HealthHandler)
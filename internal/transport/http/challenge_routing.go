@@ -0,0 +1,212 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	captchaProto "captcha-service/gen/proto/captcha"
+	"captcha-service/internal/domain/entity"
+)
+
+var errInstanceGone = errors.New("the captcha instance that issued this challenge is no longer available")
+
+// clientForChallenge returns the instance that issued the challenge, named
+// by the prefix of its ID. IDs without one (issued by an instance that does
+// not register with the balancer) go round-robin, which works when the
+// instances share a challenge store. With a shared store a challenge of a
+// gone issuer goes to another instance of the issuer's type.
+func (bp *BalancerProxy) clientForChallenge(challengeID string) (captchaProto.CaptchaServiceClient, error) {
+	instanceID, ok := entity.IssuingInstance(challengeID)
+	if !ok {
+		return bp.GetNextClient(""), nil
+	}
+
+	bp.mu.RLock()
+	for _, instance := range bp.instances {
+		if instance.id == instanceID {
+			bp.mu.RUnlock()
+			return instance.client, nil
+		}
+	}
+	shared := bp.sharedStore
+	challengeType := bp.issuerTypes[instanceID]
+	bp.mu.RUnlock()
+
+	if !shared {
+		return nil, errInstanceGone
+	}
+	// The type is unknown when the issuer left before this proxy started.
+	if client := bp.GetNextClient(challengeType); client != nil {
+		return client, nil
+	}
+	return bp.GetNextClient(""), nil
+}
+
+// eventRelay forwards the captcha events of one WebSocket connection to the
+// issuing instances over MakeEventStream, one stream per challenge, and
+// passes whatever the instances send back to the browser.
+type eventRelay struct {
+	bp        *BalancerProxy
+	ctx       context.Context
+	cancel    context.CancelFunc
+	responses chan<- entity.WebSocketMessage
+	streams   map[string]captchaProto.CaptchaService_MakeEventStreamClient
+	wg        sync.WaitGroup
+}
+
+func newEventRelay(bp *BalancerProxy, responses chan<- entity.WebSocketMessage) *eventRelay {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &eventRelay{
+		bp:        bp,
+		ctx:       ctx,
+		cancel:    cancel,
+		responses: responses,
+		streams:   make(map[string]captchaProto.CaptchaService_MakeEventStreamClient),
+	}
+}
+
+func (r *eventRelay) forward(msg entity.WebSocketMessage) error {
+	if msg.ChallengeID == "" || msg.EventType == "" {
+		return fmt.Errorf("captcha event without challenge_id or event_type")
+	}
+
+	payload := msg.EventData
+	if len(payload) == 0 {
+		payload = json.RawMessage("null")
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		entity.EventTypeFieldEventType: msg.EventType,
+		"data":                         payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	event := &captchaProto.ClientEvent{
+		EventType:   captchaProto.ClientEvent_FRONTEND_EVENT,
+		ChallengeId: msg.ChallengeID,
+		UserId:      msg.UserID,
+		Data:        data,
+	}
+
+	stream, err := r.stream(msg.ChallengeID, msg.UserID)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(event); err == nil {
+		return nil
+	}
+
+	// The stream broke, e.g. the instance restarted its listener; reopen once.
+	delete(r.streams, msg.ChallengeID)
+	stream, err = r.stream(msg.ChallengeID, msg.UserID)
+	if err != nil {
+		return err
+	}
+	return stream.Send(event)
+}
+
+func (r *eventRelay) stream(challengeID, userID string) (captchaProto.CaptchaService_MakeEventStreamClient, error) {
+	if stream, exists := r.streams[challengeID]; exists {
+		return stream, nil
+	}
+
+	client, err := r.bp.clientForChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, fmt.Errorf("no captcha services available")
+	}
+
+	stream, err := client.MakeEventStream(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.streams[challengeID] = stream
+
+	r.wg.Add(1)
+	go r.receive(challengeID, userID, stream)
+
+	return stream, nil
+}
+
+func (r *eventRelay) receive(challengeID, userID string, stream captchaProto.CaptchaService_MakeEventStreamClient) {
+	defer r.wg.Done()
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return
+		}
+
+		var msg entity.WebSocketMessage
+		switch e := event.Event.(type) {
+		case *captchaProto.ServerEvent_ClientData:
+			msg = entity.WebSocketMessage{
+				Type:        entity.MessageTypeServerData,
+				UserID:      userID,
+				ChallengeID: challengeID,
+				EventData:   clientPayload(e.ClientData.Data),
+			}
+			if isValidResult(e.ClientData.Data) {
+				r.bp.resetAttempts(userID)
+			}
+		case *captchaProto.ServerEvent_Result:
+			msg = entity.WebSocketMessage{
+				Type:        entity.MessageTypeChallengeResult,
+				UserID:      userID,
+				ChallengeID: challengeID,
+				Data: map[string]interface{}{
+					"confidence_percent": e.Result.ConfidencePercent,
+				},
+			}
+		default:
+			continue
+		}
+
+		select {
+		case r.responses <- msg:
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// clientPayload passes JSON from the instance through unchanged; binary
+// frames become a base64 string.
+func clientPayload(data []byte) json.RawMessage {
+	if json.Valid(data) {
+		return data
+	}
+	encoded, _ := json.Marshal(data)
+	return encoded
+}
+
+func isValidResult(data []byte) bool {
+	var result struct {
+		Valid bool `json:"valid"`
+	}
+	return json.Unmarshal(data, &result) == nil && result.Valid
+}
+
+// close tells the instances the browser went away and waits for the receive
+// loops, so nothing writes to the response channel after it is closed.
+func (r *eventRelay) close() {
+	for challengeID, stream := range r.streams {
+		if err := stream.Send(&captchaProto.ClientEvent{
+			EventType:   captchaProto.ClientEvent_CONNECTION_CLOSED,
+			ChallengeId: challengeID,
+		}); err != nil {
+			log.Printf("Failed to close event stream for challenge %s: %v", challengeID, err)
+		}
+		stream.CloseSend()
+	}
+
+	r.cancel()
+	r.wg.Wait()
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	captchaProto "captcha-service/gen/proto/captcha"
+	"captcha-service/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type instanceClient struct {
+	captchaProto.CaptchaServiceClient
+	name string
+}
+
+func newRoutingProxy(instanceIDs ...string) *BalancerProxy {
+	proxy := NewBalancerProxy(&config.ServiceConfig{})
+	for _, id := range instanceIDs {
+		proxy.instances = append(proxy.instances, &captchaInstance{
+			id:            id,
+			address:       id + ":38000",
+			challengeType: "slider-puzzle",
+			client:        instanceClient{name: id},
+		})
+	}
+	return proxy
+}
+
+func TestClientForChallengeRoutesToIssuer(t *testing.T) {
+	proxy := newRoutingProxy("captcha-instance-a", "captcha-instance-b", "captcha-instance-c")
+
+	// Another proxy, or this one before a restart, handed the challenge out:
+	// the ID alone names the issuer.
+	for i := 0; i < 3; i++ {
+		client, err := proxy.clientForChallenge("captcha-instance-b.6f1c2a9e-8a57-4d0b-9a55-0b4d2b1f4e21")
+		require.NoError(t, err)
+		assert.Equal(t, "captcha-instance-b", client.(instanceClient).name)
+	}
+}
+
+func TestClientForChallengeIssuerGone(t *testing.T) {
+	proxy := newRoutingProxy("captcha-instance-a")
+
+	client, err := proxy.clientForChallenge("captcha-instance-b.6f1c2a9e-8a57-4d0b-9a55-0b4d2b1f4e21")
+	assert.ErrorIs(t, err, errInstanceGone)
+	assert.Nil(t, client)
+}
+
+func TestClientForChallengeWithoutIssuer(t *testing.T) {
+	proxy := newRoutingProxy("captcha-instance-a", "captcha-instance-b")
+
+	var names []string
+	for i := 0; i < 2; i++ {
+		client, err := proxy.clientForChallenge("6f1c2a9e-8a57-4d0b-9a55-0b4d2b1f4e21")
+		require.NoError(t, err)
+		names = append(names, client.(instanceClient).name)
+	}
+	assert.ElementsMatch(t, []string{"captcha-instance-a", "captcha-instance-b"}, names, "unprefixed IDs go round-robin")
+}
+
+// goneClient answers every validation as if the challenge was consumed.
+type goneClient struct {
+	captchaProto.CaptchaServiceClient
+}
+
+func (goneClient) ValidateChallenge(ctx context.Context, in *captchaProto.ValidateRequest, opts ...grpc.CallOption) (*captchaProto.ValidateResponse, error) {
+	return nil, status.Error(codes.NotFound, "challenge with ID "+in.ChallengeId+": challenge not found")
+}
+
+func TestValidateChallengeGone(t *testing.T) {
+	proxy := newRoutingProxy("captcha-instance-a")
+	proxy.instances[0].client = goneClient{}
+
+	request := httptest.NewRequest(http.MethodPost, "/api/validate",
+		strings.NewReader(`{"challenge_id":"captcha-instance-a.6f1c2a9e-8a57-4d0b-9a55-0b4d2b1f4e21","answer":{}}`))
+	recorder := httptest.NewRecorder()
+	proxy.ValidateChallengeHandler(recorder, request)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "challenge not found")
+}
+
+func TestClientForChallengeIssuerGoneSharedStore(t *testing.T) {
+	proxy := newRoutingProxy("captcha-instance-a", "captcha-instance-b")
+	proxy.instances[1].challengeType = "rotation"
+	proxy.issuerTypes["captcha-instance-c"] = "rotation"
+	proxy.SetSharedStore(true)
+
+	for i := 0; i < 3; i++ {
+		client, err := proxy.clientForChallenge("captcha-instance-c.6f1c2a9e-8a57-4d0b-9a55-0b4d2b1f4e21")
+		require.NoError(t, err)
+		assert.Equal(t, "captcha-instance-b", client.(instanceClient).name, "another instance of the issuer's type reads the shared store")
+	}
+
+	// An issuer that left before the proxy started has no known type.
+	client, err := proxy.clientForChallenge("captcha-instance-d.6f1c2a9e-8a57-4d0b-9a55-0b4d2b1f4e21")
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
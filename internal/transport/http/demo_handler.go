@@ -79,7 +79,10 @@ func (h *DemoHandler) HandleDemo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	captchaHTML := h.createRealChallengeHTML(challenge, userID)
+	captchaHTML := challenge.HTML
+	if captchaHTML == "" {
+		captchaHTML = h.createRealChallengeHTML(challenge, userID)
+	}
 
 	tmpl, err := template.ParseFiles("./templates/demo.html")
 	if err != nil {
@@ -221,7 +224,7 @@ func (h *DemoHandler) createRealChallengeHTML(challenge *entity.Challenge, userI
       target_x: %d,
       target_y: %d,
       tolerance: 15,
-      background_image: "http://localhost:8081/backgrounds/%s",
+      background_image: "/backgrounds/%s",
       puzzle_shape: "%s"
     };
 
@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"runtime"
 	"sync/atomic"
@@ -10,7 +11,6 @@ import (
 
 	"captcha-service/internal/domain/entity"
 	"captcha-service/internal/infrastructure/cache"
-	"captcha-service/internal/infrastructure/persistence"
 	"captcha-service/internal/service"
 	"captcha-service/pkg/logger"
 
@@ -20,13 +20,22 @@ import (
 
 type CaptchaService interface {
 	CreateChallenge(ctx context.Context, challengeType string, complexity int32, userID string) (*entity.Challenge, error)
-	ValidateChallenge(ctx context.Context, challengeID string, answer interface{}) (bool, int32, error)
+	Validate(ctx context.Context, challengeID string, answer interface{}) (service.Verdict, error)
+	VerifyToken(ctx context.Context, token, site string) (*service.TokenClaims, error)
 	GetChallenge(ctx context.Context, challengeID string) (*entity.Challenge, error)
+	ListChallengeTypes() []string
+}
+
+// ChallengePoolStats reports the pre-generated challenge buffers.
+type ChallengePoolStats interface {
+	Stats() []service.PoolStats
 }
 
 type Handlers struct {
 	captchaService CaptchaService
 	memoryMonitor  *MemoryMonitor
+	pool           ChallengePoolStats
+	debugSeeds     bool
 
 	requestsTotal    int64
 	challengesTotal  int64
@@ -37,7 +46,7 @@ type Handlers struct {
 
 func NewHandlersWithMemoryMonitor(
 	captchaService CaptchaService,
-	challengeRepo *persistence.MemoryOptimizedRepository,
+	challengeRepo ChallengeStats,
 	sessionCache *cache.SessionCache,
 	globalBlocker *service.GlobalUserBlocker,
 ) *Handlers {
@@ -48,6 +57,16 @@ func NewHandlersWithMemoryMonitor(
 	}
 }
 
+func (h *Handlers) SetChallengePool(pool ChallengePoolStats) {
+	h.pool = pool
+}
+
+// SetDebugSeeds lets challenge requests carry a seed to replay a recorded
+// challenge and returns the seed of every new one.
+func (h *Handlers) SetDebugSeeds(enabled bool) {
+	h.debugSeeds = enabled
+}
+
 func (h *Handlers) HandleChallengeRequest(w http.ResponseWriter, r *http.Request) {
 	atomic.AddInt64(&h.requestsTotal, 1)
 
@@ -55,6 +74,9 @@ func (h *Handlers) HandleChallengeRequest(w http.ResponseWriter, r *http.Request
 		ChallengeType string `json:"challenge_type"`
 		Complexity    int32  `json:"complexity"`
 		UserID        string `json:"user_id"`
+		Locale        string `json:"locale"`
+		Site          string `json:"site"`
+		Seed          *int64 `json:"seed"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		atomic.AddInt64(&h.errorsTotal, 1)
@@ -67,17 +89,53 @@ func (h *Handlers) HandleChallengeRequest(w http.ResponseWriter, r *http.Request
 		userID = "demo_user"
 	}
 
-	challenge, err := h.captchaService.CreateChallenge(r.Context(), req.ChallengeType, req.Complexity, userID)
+	ctx := service.WithLocale(r.Context(), req.Locale)
+	ctx = service.WithSite(ctx, req.Site)
+	if req.Seed != nil {
+		if !h.debugSeeds {
+			atomic.AddInt64(&h.errorsTotal, 1)
+			http.Error(w, "Seed replay is disabled", http.StatusBadRequest)
+			return
+		}
+		ctx = service.WithSeed(ctx, *req.Seed)
+	}
+
+	challenge, err := h.captchaService.CreateChallenge(ctx, req.ChallengeType, req.Complexity, userID)
 	if err != nil {
 		atomic.AddInt64(&h.errorsTotal, 1)
+		if errors.Is(err, entity.ErrUnsupportedChallengeType) {
+			http.Error(w, "Unsupported challenge type", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, entity.ErrGeneratorUnavailable) {
+			http.Error(w, "Challenge type unavailable", http.StatusServiceUnavailable)
+			return
+		}
 		logger.Error("Failed to create challenge", zap.Error(err))
 		http.Error(w, "Failed to create challenge", http.StatusInternalServerError)
 		return
 	}
 
 	atomic.AddInt64(&h.challengesTotal, 1)
+	response := map[string]interface{}{
+		entity.FieldChallengeID: challenge.ID,
+		"html":                  challenge.HTML,
+		entity.FieldType:        challenge.Type,
+		entity.FieldComplexity:  challenge.Complexity,
+	}
+	if h.debugSeeds {
+		response["seed"] = challenge.Seed
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(challenge)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *Handlers) HandleChallengeTypes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"challenge_types": h.captchaService.ListChallengeTypes(),
+	})
 }
 
 func (h *Handlers) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
@@ -126,7 +184,17 @@ func (h *Handlers) HandleValidateRequest(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	valid, confidence, err := h.captchaService.ValidateChallenge(r.Context(), req.ChallengeID, req.Answer)
+	verdict, err := h.captchaService.Validate(r.Context(), req.ChallengeID, req.Answer)
+	if service.IsAttemptRejected(err) {
+		atomic.AddInt64(&h.errorsTotal, 1)
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if service.IsChallengeGone(err) {
+		atomic.AddInt64(&h.errorsTotal, 1)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		atomic.AddInt64(&h.errorsTotal, 1)
 		logger.Error("Failed to validate challenge", zap.Error(err))
@@ -136,8 +204,46 @@ func (h *Handlers) HandleValidateRequest(w http.ResponseWriter, r *http.Request)
 
 	atomic.AddInt64(&h.validationsTotal, 1)
 	response := map[string]interface{}{
-		"valid":      valid,
-		"confidence": confidence,
+		"valid":      verdict.Valid,
+		"confidence": verdict.Confidence,
+	}
+	if verdict.Token != "" {
+		response["token"] = verdict.Token
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// HandleSiteVerify lets a customer backend redeem the verification token its
+// user got for a valid answer. Rejected tokens are reported with
+// success false and the reason, not with an HTTP error.
+func (h *Handlers) HandleSiteVerify(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token string `json:"token"`
+		Site  string `json:"site"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{"success": false}
+	claims, err := h.captchaService.VerifyToken(r.Context(), req.Token, req.Site)
+	if err != nil {
+		code, ok := service.TokenErrorCode(err)
+		if !ok {
+			logger.Error("Failed to verify token", zap.Error(err))
+			http.Error(w, "Failed to verify token", http.StatusServiceUnavailable)
+			return
+		}
+		response["error"] = code
+	} else {
+		response["success"] = true
+		response[entity.FieldChallengeID] = claims.ChallengeID
+		response["user_id"] = claims.UserID
+		response["site"] = claims.Site
+		response["issued_at"] = claims.IssuedAt
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -189,6 +295,10 @@ func (h *Handlers) HandleStats(w http.ResponseWriter, r *http.Request) {
 		stats["rps"] = 0.0
 	}
 
+	if h.pool != nil {
+		stats["pool"] = h.pool.Stats()
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(stats)
 }
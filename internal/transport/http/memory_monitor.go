@@ -7,18 +7,21 @@ import (
 	"time"
 
 	"captcha-service/internal/infrastructure/cache"
-	"captcha-service/internal/infrastructure/persistence"
 	"captcha-service/internal/service"
 )
 
+type ChallengeStats interface {
+	GetStats() map[string]interface{}
+}
+
 type MemoryMonitor struct {
-	challengeRepo *persistence.MemoryOptimizedRepository
+	challengeRepo ChallengeStats
 	sessionCache  *cache.SessionCache
 	globalBlocker *service.GlobalUserBlocker
 }
 
 func NewMemoryMonitor(
-	challengeRepo *persistence.MemoryOptimizedRepository,
+	challengeRepo ChallengeStats,
 	sessionCache *cache.SessionCache,
 	globalBlocker *service.GlobalUserBlocker,
 ) *MemoryMonitor {
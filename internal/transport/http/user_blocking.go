@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"log"
+	"time"
+
+	protoBalancer "captcha-service/gen/proto/proto/balancer"
+	"captcha-service/internal/domain/entity"
+)
+
+// SyncBlockedUsers pulls the cluster-wide block list from the balancer, so a
+// user blocked through one proxy is blocked on all of them and an unblock
+// reaches every proxy within one discovery period. Blocks are lifted only
+// through the balancer's UnblockUser RPC; the proxy serves browsers and has
+// no endpoint for it.
+func (bp *BalancerProxy) SyncBlockedUsers() {
+	if bp.balancerClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), entity.DefaultTimeoutSeconds*time.Second)
+	defer cancel()
+
+	since := time.Now()
+	resp, err := bp.balancerClient.ListBlockedUsers(ctx, &protoBalancer.ListBlockedUsersRequest{})
+	if err != nil {
+		log.Printf("Failed to list blocked users on balancer: %v", err)
+		return
+	}
+
+	blockedUsers := make([]*entity.BlockedUser, 0, len(resp.Users))
+	for _, user := range resp.Users {
+		blockedUsers = append(blockedUsers, &entity.BlockedUser{
+			UserID:       user.UserId,
+			Reason:       user.Reason,
+			BlockedUntil: time.Unix(user.BlockedUntil, 0),
+			BlockedAt:    time.UnixMilli(user.BlockedAtMs),
+		})
+	}
+
+	for _, userID := range bp.globalBlocker.SyncBlockedUsers(blockedUsers, since) {
+		bp.unblockUser(userID)
+	}
+}
+
+// shareBlock tells the balancer about a block decided by this proxy at
+// blockedAt, so the block expires durationMinutes after it was set here.
+func (bp *BalancerProxy) shareBlock(userID, reason string, blockedAt time.Time, durationMinutes int32) {
+	if bp.balancerClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), entity.DefaultTimeoutSeconds*time.Second)
+	defer cancel()
+
+	resp, err := bp.balancerClient.BlockUser(ctx, &protoBalancer.BlockUserRequest{
+		UserId:          userID,
+		DurationMinutes: durationMinutes,
+		Reason:          reason,
+		BlockedAtMs:     blockedAt.UnixMilli(),
+	})
+	if err != nil {
+		log.Printf("Failed to block user on balancer: %v", err)
+		return
+	}
+	if resp.Status != protoBalancer.BlockUserResponse_SUCCESS {
+		log.Printf("Balancer refused to block user %s: %s", userID, resp.Message)
+		return
+	}
+
+	log.Printf("User %s blocked on balancer for %d minutes", userID, durationMinutes)
+}
+
+func (bp *BalancerProxy) unblockUser(userID string) {
+	bp.globalBlocker.UnblockUser(userID)
+
+	bp.sessionMu.Lock()
+	if session, exists := bp.sessions[userID]; exists {
+		session.IsBlocked = false
+		session.BlockedUntil = time.Time{}
+		session.Attempts = 0
+	}
+	bp.sessionMu.Unlock()
+}
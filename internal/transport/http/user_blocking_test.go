@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	protoBalancer "captcha-service/gen/proto/proto/balancer"
+	"captcha-service/internal/config"
+	"captcha-service/internal/domain/entity"
+	"captcha-service/internal/infrastructure/persistence"
+	"captcha-service/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// blockList serves the balancer's cluster-wide block list in process.
+type blockList struct {
+	protoBalancer.BalancerServiceClient
+	balancer *service.BalancerService
+}
+
+func newBlockList() *blockList {
+	balancer := service.NewBalancerService(persistence.NewMemoryInstanceRepository(), persistence.NewMemoryUserBlockRepository(),
+		&config.ServiceConfig{BlockDurationMin: 5})
+	return &blockList{balancer: balancer.(*service.BalancerService)}
+}
+
+func (b *blockList) BlockUser(ctx context.Context, req *protoBalancer.BlockUserRequest, opts ...grpc.CallOption) (*protoBalancer.BlockUserResponse, error) {
+	return b.balancer.BlockUserGRPC(ctx, req)
+}
+
+func (b *blockList) UnblockUser(ctx context.Context, req *protoBalancer.UnblockUserRequest, opts ...grpc.CallOption) (*protoBalancer.UnblockUserResponse, error) {
+	return b.balancer.UnblockUserGRPC(ctx, req)
+}
+
+func (b *blockList) ListBlockedUsers(ctx context.Context, req *protoBalancer.ListBlockedUsersRequest, opts ...grpc.CallOption) (*protoBalancer.ListBlockedUsersResponse, error) {
+	return b.balancer.ListBlockedUsersGRPC(ctx, req)
+}
+
+func (b *blockList) blocked(userID string) bool {
+	return b.balancer.IsUserBlocked(userID)
+}
+
+func newBlockingProxy(balancer *blockList) *BalancerProxy {
+	proxy := NewBalancerProxy(&config.ServiceConfig{MaxAttempts: 2, BlockDurationMin: 5})
+	proxy.balancerClient = balancer
+	return proxy
+}
+
+func TestBlockPropagatesThroughBalancer(t *testing.T) {
+	balancer := newBlockList()
+	first, second := newBlockingProxy(balancer), newBlockingProxy(balancer)
+
+	first.sessions["user-1"] = &entity.UserSession{UserID: "user-1"}
+	for i := 0; i < 2; i++ {
+		assert.False(t, first.incrementAttempts("user-1"))
+	}
+	assert.True(t, first.incrementAttempts("user-1"))
+
+	blocked, _ := first.isUserBlockedInSession("user-1")
+	assert.True(t, blocked)
+	assert.Eventually(t, func() bool { return balancer.blocked("user-1") }, time.Second, 10*time.Millisecond)
+
+	blocked, _ = second.isUserBlockedInSession("user-1")
+	assert.False(t, blocked, "not synced yet")
+	second.SyncBlockedUsers()
+	blocked, _ = second.isUserBlockedInSession("user-1")
+	assert.True(t, blocked)
+}
+
+func TestUnblockPropagatesThroughBalancer(t *testing.T) {
+	balancer := newBlockList()
+	first, second := newBlockingProxy(balancer), newBlockingProxy(balancer)
+
+	_, err := balancer.BlockUser(context.Background(), &protoBalancer.BlockUserRequest{UserId: "user-1", DurationMinutes: 5, Reason: "Too many failed attempts"})
+	assert.NoError(t, err)
+	first.sessions["user-1"] = &entity.UserSession{UserID: "user-1", Attempts: 3}
+	for _, proxy := range []*BalancerProxy{first, second} {
+		proxy.SyncBlockedUsers()
+		blocked, _ := proxy.isUserBlockedInSession("user-1")
+		assert.True(t, blocked)
+	}
+
+	// An operator lifts the block through the balancer's UnblockUser RPC.
+	_, err = balancer.UnblockUser(context.Background(), &protoBalancer.UnblockUserRequest{UserId: "user-1"})
+	assert.NoError(t, err)
+	for _, proxy := range []*BalancerProxy{first, second} {
+		proxy.SyncBlockedUsers()
+		blocked, _ := proxy.isUserBlockedInSession("user-1")
+		assert.False(t, blocked)
+	}
+	assert.Zero(t, first.sessions["user-1"].Attempts, "the unblocked user starts over")
+}
+
+func TestSyncedBlockExpiresFromWhenItWasSet(t *testing.T) {
+	balancer := newBlockList()
+	first, second := newBlockingProxy(balancer), newBlockingProxy(balancer)
+
+	blockedAt := time.Now().Add(-4 * time.Minute)
+	for i := 0; i < 3; i++ {
+		// Relaying the same block again must not extend it.
+		first.shareBlock("user-1", "Too many failed attempts", blockedAt, 5)
+		second.SyncBlockedUsers()
+	}
+
+	blockedUser, err := second.globalBlocker.GetBlockedUser("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, blockedAt.UnixMilli(), blockedUser.BlockedAt.UnixMilli())
+	assert.WithinDuration(t, blockedAt.Add(5*time.Minute), blockedUser.BlockedUntil, time.Second, "BLOCK_DURATION_MINUTES after the block was first set")
+
+	first.shareBlock("user-1", "Older block", blockedAt.Add(-time.Minute), 5)
+	second.SyncBlockedUsers()
+	blockedUser, err = second.globalBlocker.GetBlockedUser("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Too many failed attempts", blockedUser.Reason, "an older block does not replace a newer one")
+
+	renewedAt := time.Now()
+	first.shareBlock("user-1", "Blocked again", renewedAt, 5)
+	second.SyncBlockedUsers()
+	blockedUser, err = second.globalBlocker.GetBlockedUser("user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Blocked again", blockedUser.Reason)
+	assert.WithinDuration(t, renewedAt.Add(5*time.Minute), blockedUser.BlockedUntil, time.Second)
+}
+
+func TestBlockListIsNotServedToBrowsers(t *testing.T) {
+	mux := SetupBalancerProxyRoutes(NewBalancerProxy(&config.ServiceConfig{}), &config.BalancerProxyConfig{})
+
+	for _, path := range []string{"/api/users/blocked", "/api/users/unblock"} {
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, path, nil))
+		assert.NotEqual(t, path, pattern)
+	}
+}
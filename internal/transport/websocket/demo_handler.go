@@ -468,7 +468,7 @@ func (h *DemoWebSocketHandler) handleCaptchaEvent(conn *websocket.Conn, msg WebS
 			UserID:    userID,
 			SessionID: sessionID,
 			Data: map[string]interface{}{
-				"background_image": fmt.Sprintf("http://localhost:8081/backgrounds/%s", backgroundImage),
+				"background_image": fmt.Sprintf("/backgrounds/%s", backgroundImage),
 				"puzzle_shape":     puzzleShape,
 				"target_x":         newTargetX,
 				"challenge_id":     fmt.Sprintf("mock_challenge_%d", time.Now().UnixNano()),
@@ -51,7 +51,7 @@ type GeneratorRegistry interface {
 
 type ChallengeGenerator interface {
 	Generate(ctx context.Context, complexity int32, userID string) (*entity.Challenge, error)
-	Validate(answer interface{}, data interface{}) (bool, int32, error)
+	Validate(answer interface{}, challenge *entity.Challenge) (bool, int32, error)
 }
 
 type EventProcessingUseCase struct {
@@ -94,28 +94,24 @@ func (uc *EventProcessingUseCase) ProcessBinaryEvent(ctx context.Context, challe
 	}
 
 	binaryEvent := &entity.BinaryEvent{
-		Type:      eventType,
-		Timestamp: time.Now().UnixNano(),
+		ChallengeID: challengeID,
+		Type:        eventType,
+		Timestamp:   time.Now().UnixMilli(),
 	}
 
 	switch eventType {
-	case entity.EventTypeSliderMoved:
-		position, _, err := entity.UnpackSliderEvent(data)
+	case entity.EventTypeSliderMoved, entity.EventTypeClick:
+		events, err := entity.DecodeEventFrame(challengeID, data)
 		if err != nil {
-			return entity.NewEventResult(false, "Invalid slider event data", nil), err
+			return entity.NewEventResult(false, "Invalid event frame", nil), err
 		}
-		binaryEvent.X = position
-		binaryEvent.Y = 0
-		return uc.eventProcessor.ProcessEvent(binaryEvent)
-
-	case entity.EventTypeClick:
-		x, y, _, err := entity.UnpackClickEvent(data)
-		if err != nil {
-			return entity.NewEventResult(false, "Invalid click event data", nil), err
+		var result *entity.EventResult
+		for i := range events {
+			if result, err = uc.eventProcessor.ProcessEvent(&events[i]); err != nil {
+				return result, err
+			}
 		}
-		binaryEvent.X = x
-		binaryEvent.Y = y
-		return uc.eventProcessor.ProcessEvent(binaryEvent)
+		return result, nil
 
 	case entity.EventTypeChallengeCompleted:
 		var eventData map[string]interface{}
@@ -156,7 +152,8 @@ func (uc *EventProcessingUseCase) ProcessJSONEvent(ctx context.Context, eventDat
 	}
 
 	binaryEvent := &entity.BinaryEvent{
-		Timestamp: time.Now().UnixNano(),
+		ChallengeID: challengeID,
+		Timestamp:   time.Now().UnixMilli(),
 	}
 
 	switch eventData.Type {
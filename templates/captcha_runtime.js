@@ -0,0 +1,137 @@
+// CaptchaRuntime is the client side every challenge page gets injected,
+// together with CaptchaEvents from event_codec.js. A page runs in an iframe
+// and talks to the server only through the embedding window: events go up as
+// captcha:sendData messages, the server answers with captcha:serverData. The
+// page itself only draws the challenge:
+//
+//     const captcha = CaptchaRuntime.start({ challengeId: "...", userId: "..." });
+//     captcha.push(captcha.TYPES.CLICK, x, y);
+//     captcha.onResult(function (valid) { ... });
+//     captcha.validate({ x: x });
+//
+// Elements with a data-captcha-switch attribute ask for a challenge of that
+// type instead, e.g. <button data-captcha-switch="audio">.
+var CaptchaRuntime = (function () {
+    // toBytes accepts the forms binary server data takes after crossing
+    // JSON and postMessage: a base64 string, an array of numbers or a buffer.
+    function toBytes(data) {
+        if (data instanceof Uint8Array) return data;
+        if (data instanceof ArrayBuffer || Array.isArray(data)) return new Uint8Array(data);
+        if (typeof data === 'string') {
+            try {
+                return Uint8Array.from(atob(data), function (c) { return c.charCodeAt(0); });
+            } catch (error) {
+                return null;
+            }
+        }
+        return null;
+    }
+
+    function isBinary(data) {
+        return typeof data === 'string' || Array.isArray(data) ||
+            data instanceof ArrayBuffer || data instanceof Uint8Array;
+    }
+
+    // start connects the page to the server. Options: challengeId, userId,
+    // batchMs (how long pointer samples are collected into one frame) and
+    // announce, which sends interaction_started right away unless false.
+    function start(options) {
+        var challengeId = options.challengeId || 'unknown';
+        var userId = options.userId || 'anonymous';
+        var handlers = { result: [], data: [], frame: [] };
+
+        function post(message) {
+            try {
+                window.top.postMessage(message, '*');
+            } catch (error) {
+                console.error('Failed to send event to server:', error);
+            }
+        }
+
+        function send(eventType, data) {
+            post({
+                type: 'captcha:sendData',
+                challengeId: challengeId,
+                userId: userId,
+                eventType: eventType,
+                data: data
+            });
+        }
+
+        var events = CaptchaEvents.batch(function (frame) {
+            send('event_frame', frame);
+        }, options.batchMs);
+
+        // validate sends the answer after every pointer sample taken so far,
+        // which the server judges together with it.
+        function validate(answer) {
+            events.flush();
+            send('validation', answer);
+        }
+
+        function announce() {
+            send('interaction_started', {
+                timestamp: Date.now(),
+                userAgent: navigator.userAgent
+            });
+        }
+
+        function switchType(challengeType) {
+            post({
+                type: 'captcha:switchType',
+                challengeId: challengeId,
+                userId: userId,
+                challengeType: challengeType
+            });
+        }
+
+        function dispatch(kind, args) {
+            handlers[kind].forEach(function (handler) {
+                handler.apply(null, args);
+            });
+        }
+
+        // Results carry a boolean valid, other JSON goes to onData and
+        // anything binary, such as game state frames, to onFrame as bytes.
+        window.addEventListener('message', function (e) {
+            if (!e.data || e.data.type !== 'captcha:serverData') return;
+
+            var data = e.data.data;
+            if (data === null || data === undefined) return;
+
+            if (isBinary(data)) {
+                var bytes = toBytes(data);
+                if (bytes && bytes.length > 0) dispatch('frame', [bytes]);
+            } else if (typeof data === 'object' && typeof data.valid === 'boolean') {
+                dispatch('result', [data.valid, data]);
+            } else if (typeof data === 'object') {
+                dispatch('data', [data]);
+            }
+        });
+
+        document.querySelectorAll('[data-captcha-switch]').forEach(function (element) {
+            element.addEventListener('click', function () {
+                switchType(element.getAttribute('data-captcha-switch'));
+            });
+        });
+
+        if (options.announce !== false) announce();
+
+        return {
+            challengeId: challengeId,
+            userId: userId,
+            TYPES: CaptchaEvents.TYPES,
+            send: send,
+            push: events.push,
+            flush: events.flush,
+            validate: validate,
+            announce: announce,
+            switchType: switchType,
+            onResult: function (handler) { handlers.result.push(handler); },
+            onData: function (handler) { handlers.data.push(handler); },
+            onFrame: function (handler) { handlers.frame.push(handler); }
+        };
+    }
+
+    return { start: start, toBytes: toBytes };
+})();
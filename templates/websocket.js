@@ -2,39 +2,6 @@ let ws = null;
 let currentChallengeId = '';
 let userId = '{{.UserID}}'; // Set by server
 
-// Binary data utilities for optimization
-const BinaryUtils = {
-    // Pack coordinates into single int (max 8192x8192 as per requirements)
-    packCoordinates: (x, y) => {
-        return (y << 13) | x; // 13 bits for x (max 8191), 13 bits for y (max 8191)
-    },
-    
-    // Unpack coordinates from int
-    unpackCoordinates: (packed) => {
-        return {
-            x: packed & 0x1FFF, // 13 bits
-            y: (packed >> 13) & 0x1FFF // 13 bits
-        };
-    },
-    
-    // Pack event data for efficient transmission
-    packEventData: (eventType, data) => {
-        const buffer = new ArrayBuffer(8);
-        const view = new DataView(buffer);
-        
-        // First 4 bytes: event type (0-255)
-        view.setUint8(0, eventType);
-        
-        // Next 4 bytes: packed coordinates or other data
-        if (data.x !== undefined && data.y !== undefined) {
-            const packed = BinaryUtils.packCoordinates(data.x, data.y);
-            view.setUint32(1, packed, true); // little-endian
-        }
-        
-        return buffer;
-    }
-};
-
 // window.postMessage API for iframe interaction (as per requirements)
 function setupPostMessageAPI() {
     // Listen for messages from iframe captcha
@@ -44,28 +11,33 @@ function setupPostMessageAPI() {
         }
     });
     
-    // Send data to iframe captcha
+    // Send data to iframe captcha, or to this window when the captcha is
+    // rendered inline
     function sendToCaptcha(data) {
         const iframe = document.getElementById('captcha-iframe');
-        if (iframe && iframe.contentWindow) {
-            iframe.contentWindow.postMessage({
-                type: "captcha:serverData",
-                data: data
-            }, '*');
-        }
+        const target = iframe && iframe.contentWindow ? iframe.contentWindow : window;
+        target.postMessage({
+            type: "captcha:serverData",
+            data: data
+        }, '*');
     }
     
     // Handle data from captcha iframe
     function handleCaptchaData(data) {
         console.log('Received from captcha:', data);
         
-        // Convert to binary for efficient transmission
+        // Challenges send event frames themselves; older pages still post
+        // slider_move with plain coordinates, which are framed here.
         if (data.eventType === 'slider_move' && data.data) {
-            const binaryData = BinaryUtils.packEventData(1, data.data); // 1 = slider_move
-            sendWebSocketEvent('slider_move', binaryData);
-        } else if (data.eventType === 'validation' && data.data) {
-            const binaryData = BinaryUtils.packEventData(2, data.data); // 2 = validation
-            sendWebSocketEvent('validation', binaryData);
+            const frame = CaptchaEvents.encode([{
+                type: CaptchaEvents.TYPES.SLIDER_MOVED,
+                x: Math.round(data.data.x || 0),
+                y: Math.round(data.data.y || 0),
+                timestamp: Date.now()
+            }]);
+            if (frame) {
+                forwardCaptchaEvent(data.challengeId, data.userId, 'event_frame', frame);
+            }
         }
     }
     
@@ -223,6 +195,11 @@ function connectWebSocket() {
                             window.location.reload();
                         }, 1000);
                     }
+                } else if (data.type === 'server_data') {
+                    // Forwarded from the instance that issued the challenge
+                    window.sendToCaptcha(data.event_data);
+                } else if (data.type === 'challenge_result') {
+                    console.log('Challenge result:', data.challenge_id, data.data);
                 } else if (data.type === 'challenge_created') {
                     showStatus('Challenge created successfully');
                 } else if (data.type === 'grpc_response') {
@@ -233,7 +210,7 @@ function connectWebSocket() {
                         showError(data.data.error);
                     }
                 } else if (data.type === 'error') {
-                    showError(data.message || 'Unknown error');
+                    showError((data.data && data.data.error) || data.message || 'Unknown error');
                 }
             } catch (e) {
                 console.error('Error parsing WebSocket message:', e);
@@ -260,19 +237,34 @@ window.addEventListener('message', function(event) {
     if (event.data && event.data.type === 'captcha:sendData') {
         console.log('Captcha event:', event.data);
         
-        if (ws && ws.readyState === WebSocket.OPEN) {
-            // Forward captcha events to WebSocket
-            const message = {
-                type: 'captcha_event',
-                challenge_id: event.data.challengeId || currentChallengeId,
-                user_id: event.data.userId || userId,
-                event_type: event.data.eventType,
-                data: event.data.data
-            };
-            ws.send(JSON.stringify(message));
-        } else {
-            showError('WebSocket not connected');
-        }
+        forwardCaptchaEvent(event.data.challengeId, event.data.userId, event.data.eventType, event.data.data);
+    }
+});
+
+// Forward a captcha event to the WebSocket
+function forwardCaptchaEvent(challengeId, eventUserId, eventType, payload) {
+    if (!ws || ws.readyState !== WebSocket.OPEN) {
+        showError('WebSocket not connected');
+        return;
+    }
+
+    const message = {
+        type: 'captcha_event',
+        challenge_id: challengeId || currentChallengeId,
+        user_id: eventUserId || userId,
+        event_type: eventType,
+        // Typed arrays would otherwise serialize as index-keyed objects
+        event_data: ArrayBuffer.isView(payload) ? Array.from(payload) : payload
+    };
+    ws.send(JSON.stringify(message));
+}
+
+// Reload with another challenge type, e.g. the audio alternative
+window.addEventListener('message', function(event) {
+    if (event.data && event.data.type === 'captcha:switchType' && event.data.challengeType) {
+        const url = new URL(window.location.href);
+        url.searchParams.set('type', event.data.challengeType);
+        window.location.assign(url.toString());
     }
 });
 